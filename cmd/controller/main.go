@@ -41,6 +41,8 @@ func main() {
 		op.SubnetProvider,
 	)
 	lo.Must0(op.AddHealthzCheck("cloud-provider", awsCloudProvider.LivenessProbe))
+	lo.Must0(op.AddReadyzCheck("pricing", op.PricingProvider.ReadinessProbe))
+	lo.Must0(op.AddReadyzCheck("warm-up", op.WarmUp.ReadinessProbe))
 	cloudProvider := metrics.Decorate(awsCloudProvider)
 
 	op.
@@ -66,6 +68,10 @@ func main() {
 			op.InstanceProfileProvider,
 			op.PricingProvider,
 			op.AMIProvider,
+			op.InstanceTypesProvider,
+			op.OverheadStore,
+			op.InstanceProvider,
+			op.LaunchTemplateProvider.ClusterCIDR,
 		)...).
 		WithWebhooks(ctx, webhooks.NewWebhooks()...).
 		Start(ctx)
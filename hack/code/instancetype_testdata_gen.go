@@ -119,7 +119,13 @@ func getInstanceTypeInfo(info *ec2.InstanceTypeInfo) string {
 	fmt.Fprintf(src, "BareMetal: aws.Bool(%t),\n", lo.FromPtr(info.BareMetal))
 	fmt.Fprintf(src, "Hypervisor: aws.String(\"%s\"),\n", lo.FromPtr(info.Hypervisor))
 	fmt.Fprintf(src, "ProcessorInfo: &ec2.ProcessorInfo{\n")
+	if info.ProcessorInfo.Manufacturer != nil {
+		fmt.Fprintf(src, "Manufacturer: aws.String(\"%s\"),\n", lo.FromPtr(info.ProcessorInfo.Manufacturer))
+	}
 	fmt.Fprintf(src, "SupportedArchitectures: aws.StringSlice([]string{%s}),\n", getStringSliceData(info.ProcessorInfo.SupportedArchitectures))
+	if info.ProcessorInfo.SustainedClockSpeedInGhz != nil {
+		fmt.Fprintf(src, "SustainedClockSpeedInGhz: aws.Float64(%v),\n", lo.FromPtr(info.ProcessorInfo.SustainedClockSpeedInGhz))
+	}
 	fmt.Fprintf(src, "},\n")
 	fmt.Fprintf(src, "VCpuInfo: &ec2.VCpuInfo{\n")
 	fmt.Fprintf(src, "DefaultCores: aws.Int64(%d),\n", lo.FromPtr(info.VCpuInfo.DefaultCores))
@@ -153,10 +159,18 @@ func getInstanceTypeInfo(info *ec2.InstanceTypeInfo) string {
 		fmt.Fprintf(src, "TotalSizeInGB: aws.Int64(%d),\n", lo.FromPtr(info.InstanceStorageInfo.TotalSizeInGB))
 		fmt.Fprintf(src, "},\n")
 	}
+	if info.EbsInfo != nil && info.EbsInfo.EbsOptimizedInfo != nil {
+		fmt.Fprintf(src, "EbsInfo: &ec2.EbsInfo{\n")
+		fmt.Fprintf(src, "EbsOptimizedInfo: &ec2.EbsOptimizedInfo{\n")
+		fmt.Fprintf(src, "MaximumBandwidthInMbps: aws.Int64(%d),\n", lo.FromPtr(info.EbsInfo.EbsOptimizedInfo.MaximumBandwidthInMbps))
+		fmt.Fprintf(src, "},\n")
+		fmt.Fprintf(src, "},\n")
+	}
 	fmt.Fprintf(src, "NetworkInfo: &ec2.NetworkInfo{\n")
 	fmt.Fprintf(src, "MaximumNetworkInterfaces: aws.Int64(%d),\n", lo.FromPtr(info.NetworkInfo.MaximumNetworkInterfaces))
 	fmt.Fprintf(src, "Ipv4AddressesPerInterface: aws.Int64(%d),\n", lo.FromPtr(info.NetworkInfo.Ipv4AddressesPerInterface))
 	fmt.Fprintf(src, "EncryptionInTransitSupported: aws.Bool(%t),\n", lo.FromPtr(info.NetworkInfo.EncryptionInTransitSupported))
+	fmt.Fprintf(src, "EnaSrdSupported: aws.Bool(%t),\n", lo.FromPtr(info.NetworkInfo.EnaSrdSupported))
 	fmt.Fprintf(src, "DefaultNetworkCardIndex: aws.Int64(%d),\n", lo.FromPtr(info.NetworkInfo.DefaultNetworkCardIndex))
 	fmt.Fprintf(src, "NetworkCards: []*ec2.NetworkCardInfo{\n")
 	for _, networkCard := range info.NetworkInfo.NetworkCards {
@@ -0,0 +1,115 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/samber/lo"
+)
+
+// coresPerDevice is the number of NeuronCores on each Neuron device of an instance family
+// https://awsdocs-neuron.readthedocs-hosted.com/en/latest/general/arch/neuron-hardware/
+var coresPerDevice = map[string]int64{
+	"inf1":  4,
+	"inf2":  2,
+	"trn1":  2,
+	"trn1n": 2,
+}
+
+// trainiumDevices is the number of Neuron devices of the Trainium instance types, which aren't described by the
+// InferenceAcceleratorInfo of DescribeInstanceTypes
+// https://aws.amazon.com/ec2/instance-types/trn1/
+var trainiumDevices = map[string]int64{
+	"trn1.2xlarge":   1,
+	"trn1.32xlarge":  16,
+	"trn1n.32xlarge": 16,
+}
+
+const fileFormat = `
+%s
+package instancetype
+
+// GENERATED FILE. DO NOT EDIT DIRECTLY.
+// Update hack/code/neuroncores_gen.go and re-generate to edit
+
+var (
+	InstanceTypeNeuronCores = map[string]int64{
+		%s
+	}
+)
+`
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 1 {
+		log.Fatalf("Usage: `neuroncores_gen.go pkg/providers/instancetype/zz_generated.neuroncores.go`")
+	}
+	if err := os.Setenv("AWS_SDK_LOAD_CONFIG", "true"); err != nil {
+		log.Fatalf("setting AWS_SDK_LOAD_CONFIG, %s", err)
+	}
+	if err := os.Setenv("AWS_REGION", "us-east-1"); err != nil {
+		log.Fatalf("setting AWS_REGION, %s", err)
+	}
+	sess := session.Must(session.NewSession())
+	ec2api := ec2.New(sess)
+
+	neuronCores := map[string]int64{}
+	lo.Must0(ec2api.DescribeInstanceTypesPages(&ec2.DescribeInstanceTypesInput{}, func(page *ec2.DescribeInstanceTypesOutput, _ bool) bool {
+		for _, info := range page.InstanceTypes {
+			instanceType := aws.StringValue(info.InstanceType)
+			cores, ok := coresPerDevice[strings.Split(instanceType, ".")[0]]
+			if !ok {
+				continue
+			}
+			devices := trainiumDevices[instanceType]
+			if info.InferenceAcceleratorInfo != nil {
+				for _, accelerator := range info.InferenceAcceleratorInfo.Accelerators {
+					devices += aws.Int64Value(accelerator.Count)
+				}
+			}
+			if devices > 0 {
+				neuronCores[instanceType] = devices * cores
+			}
+		}
+		return true
+	}))
+
+	instanceTypes := lo.Keys(neuronCores)
+	sort.Strings(instanceTypes)
+
+	// Generate body
+	var body string
+	for _, instanceType := range instanceTypes {
+		body += fmt.Sprintf("\t\"%s\": %d,\n", instanceType, neuronCores[instanceType])
+	}
+
+	license := lo.Must(os.ReadFile("hack/boilerplate.go.txt"))
+
+	// Format and print to the file
+	formatted := lo.Must(format.Source([]byte(fmt.Sprintf(fileFormat, license, body))))
+	file := lo.Must(os.Create(flag.Args()[0]))
+	lo.Must(file.Write(formatted))
+	file.Close()
+}
@@ -31,8 +31,6 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	ec22 "github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/samber/lo"
-	"k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/aws/karpenter/pkg/apis/settings"
 	"github.com/aws/karpenter/pkg/providers/pricing"
@@ -103,14 +101,14 @@ func main() {
 	fmt.Fprintln(src, "package pricing")
 	now := time.Now().UTC().Format(time.RFC3339)
 	fmt.Fprintf(src, "// generated at %s for %s\n\n\n", now, region)
+	fmt.Fprintf(src, "// InitialOnDemandPrices%sGeneratedAt is the time at which the static pricing data below was retrieved\n", getPartitionSuffix(opts.partition))
+	fmt.Fprintf(src, "const InitialOnDemandPrices%sGeneratedAt = %q\n\n", getPartitionSuffix(opts.partition), now)
 	fmt.Fprintf(src, "var InitialOnDemandPrices%s = map[string]map[string]float64{\n", getPartitionSuffix(opts.partition))
 	// record prices for each region we are interested in
 	for _, region := range getAWSRegions(opts.partition) {
 		log.Println("fetching for", region)
 		pricingProvider := pricing.NewProvider(ctx, pricing.NewAPI(sess, region), ec2, region)
-		controller := pricing.NewController(pricingProvider)
-		_, err := controller.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{}})
-		if err != nil {
+		if err := pricingProvider.UpdateOnDemandPricing(ctx); err != nil {
 			log.Fatalf("failed to initialize pricing provider %s", err)
 		}
 		instanceTypes := pricingProvider.InstanceTypes()
@@ -18,6 +18,17 @@ bandwidth() {
   checkForUpdates "${GIT_DIFF}" "${NO_UPDATE}" "${SUBJECT}" "${GENERATED_FILE}"
 }
 
+neuronCores() {
+  GENERATED_FILE="pkg/providers/instancetype/zz_generated.neuroncores.go"
+  NO_UPDATE=''
+  SUBJECT="Neuron Cores"
+
+  go run hack/code/neuroncores_gen.go -- "${GENERATED_FILE}"
+
+  GIT_DIFF=$(git diff --stat "${GENERATED_FILE}")
+  checkForUpdates "${GIT_DIFF}" "${NO_UPDATE}" "${SUBJECT}" "${GENERATED_FILE}"
+}
+
 pricing() {
   declare -a PARTITIONS=(
     "aws"
@@ -102,6 +113,7 @@ if [[ $ENABLE_GIT_PUSH == true ]]; then
 fi
 
 bandwidth
+neuronCores
 pricing
 vpcLimits
 instanceTypeTestData
@@ -34,6 +34,7 @@ var defaultSettings = &Settings{
 	ClusterCABundle:            "",
 	ClusterName:                "",
 	ClusterEndpoint:            "",
+	ClusterCIDR:                "",
 	DefaultInstanceProfile:     "",
 	EnablePodENI:               false,
 	EnableENILimitedPodDensity: true,
@@ -42,6 +43,7 @@ var defaultSettings = &Settings{
 	InterruptionQueueName:      "",
 	Tags:                       map[string]string{},
 	ReservedENIs:               0,
+	SpotPriceUpdateInterval:    time.Minute * 5,
 }
 
 // +k8s:deepcopy-gen=true
@@ -51,6 +53,7 @@ type Settings struct {
 	ClusterCABundle            string
 	ClusterName                string
 	ClusterEndpoint            string
+	ClusterCIDR                string
 	DefaultInstanceProfile     string
 	EnablePodENI               bool
 	EnableENILimitedPodDensity bool
@@ -59,6 +62,7 @@ type Settings struct {
 	InterruptionQueueName      string
 	Tags                       map[string]string
 	ReservedENIs               int
+	SpotPriceUpdateInterval    time.Duration
 }
 
 func (*Settings) ConfigMap() string {
@@ -75,6 +79,7 @@ func (*Settings) Inject(ctx context.Context, cm *v1.ConfigMap) (context.Context,
 		configmap.AsString("aws.clusterCABundle", &s.ClusterCABundle),
 		configmap.AsString("aws.clusterName", &s.ClusterName),
 		configmap.AsString("aws.clusterEndpoint", &s.ClusterEndpoint),
+		configmap.AsString("aws.clusterCIDR", &s.ClusterCIDR),
 		configmap.AsString("aws.defaultInstanceProfile", &s.DefaultInstanceProfile),
 		configmap.AsBool("aws.enablePodENI", &s.EnablePodENI),
 		configmap.AsBool("aws.enableENILimitedPodDensity", &s.EnableENILimitedPodDensity),
@@ -83,6 +88,7 @@ func (*Settings) Inject(ctx context.Context, cm *v1.ConfigMap) (context.Context,
 		configmap.AsString("aws.interruptionQueueName", &s.InterruptionQueueName),
 		AsStringMap("aws.tags", &s.Tags),
 		configmap.AsInt("aws.reservedENIs", &s.ReservedENIs),
+		configmap.AsDuration("aws.spotPriceUpdateInterval", &s.SpotPriceUpdateInterval),
 	); err != nil {
 		return ctx, fmt.Errorf("parsing settings, %w", err)
 	}
@@ -16,6 +16,7 @@ package settings
 
 import (
 	"fmt"
+	"net"
 	"net/url"
 	"time"
 
@@ -27,11 +28,13 @@ import (
 func (s Settings) Validate() (errs *apis.FieldError) {
 	return errs.Also(
 		s.validateEndpoint(),
+		s.validateClusterCIDR(),
 		s.validateTags(),
 		s.validateClusterName(),
 		s.validateVMMemoryOverheadPercent(),
 		s.validateReservedENIs(),
 		s.validateAssumeRoleDuration(),
+		s.validateSpotPriceUpdateInterval(),
 	).ViaField("aws")
 }
 
@@ -62,6 +65,16 @@ func (s Settings) validateEndpoint() (errs *apis.FieldError) {
 	return nil
 }
 
+func (s Settings) validateClusterCIDR() (errs *apis.FieldError) {
+	if s.ClusterCIDR == "" {
+		return nil
+	}
+	if _, _, err := net.ParseCIDR(s.ClusterCIDR); err != nil {
+		return errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%q not a valid clusterCIDR", s.ClusterCIDR), "clusterCIDR"))
+	}
+	return nil
+}
+
 func (s Settings) validateTags() (errs *apis.FieldError) {
 	for k := range s.Tags {
 		for _, pattern := range v1alpha1.RestrictedTagPatterns {
@@ -86,3 +99,10 @@ func (s Settings) validateReservedENIs() (errs *apis.FieldError) {
 	}
 	return nil
 }
+
+func (s Settings) validateSpotPriceUpdateInterval() (errs *apis.FieldError) {
+	if s.SpotPriceUpdateInterval < time.Minute {
+		return errs.Also(apis.ErrInvalidValue("spotPriceUpdateInterval cannot be less than 1 Minute", "spotPriceUpdateInterval"))
+	}
+	return nil
+}
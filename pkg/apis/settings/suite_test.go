@@ -49,6 +49,7 @@ var _ = Describe("Validation", func() {
 		Expect(s.AssumeRoleARN).To(Equal(""))
 		Expect(s.AssumeRoleDuration).To(Equal(time.Duration(15) * time.Minute))
 		Expect(s.ClusterCABundle).To(Equal(""))
+		Expect(s.ClusterCIDR).To(Equal(""))
 		Expect(s.DefaultInstanceProfile).To(Equal(""))
 		Expect(s.EnablePodENI).To(BeFalse())
 		Expect(s.EnableENILimitedPodDensity).To(BeTrue())
@@ -56,6 +57,7 @@ var _ = Describe("Validation", func() {
 		Expect(s.VMMemoryOverheadPercent).To(Equal(0.075))
 		Expect(len(s.Tags)).To(BeZero())
 		Expect(s.ReservedENIs).To(Equal(0))
+		Expect(s.SpotPriceUpdateInterval).To(Equal(time.Duration(5) * time.Minute))
 	})
 	It("should succeed to set custom values", func() {
 		cm := &v1.ConfigMap{
@@ -63,6 +65,7 @@ var _ = Describe("Validation", func() {
 				"aws.assumeRoleARN":              "arn:aws:iam::111222333444:role/testrole",
 				"aws.assumeRoleDuration":         "27m",
 				"aws.clusterCABundle":            "ca-bundle",
+				"aws.clusterCIDR":                "10.100.0.0/16",
 				"aws.clusterEndpoint":            "https://00000000000000000000000.gr7.us-west-2.eks.amazonaws.com",
 				"aws.clusterName":                "my-cluster",
 				"aws.defaultInstanceProfile":     "karpenter",
@@ -72,6 +75,7 @@ var _ = Describe("Validation", func() {
 				"aws.vmMemoryOverheadPercent":    "0.1",
 				"aws.tags":                       `{"tag1": "value1", "tag2": "value2", "example.com/tag": "my-value"}`,
 				"aws.reservedENIs":               "1",
+				"aws.spotPriceUpdateInterval":    "10m",
 			},
 		}
 		ctx, err := (&settings.Settings{}).Inject(ctx, cm)
@@ -80,6 +84,7 @@ var _ = Describe("Validation", func() {
 		Expect(s.AssumeRoleARN).To(Equal("arn:aws:iam::111222333444:role/testrole"))
 		Expect(s.AssumeRoleDuration).To(Equal(time.Duration(27) * time.Minute))
 		Expect(s.ClusterCABundle).To(Equal("ca-bundle"))
+		Expect(s.ClusterCIDR).To(Equal("10.100.0.0/16"))
 		Expect(s.DefaultInstanceProfile).To(Equal("karpenter"))
 		Expect(s.EnablePodENI).To(BeTrue())
 		Expect(s.EnableENILimitedPodDensity).To(BeFalse())
@@ -90,6 +95,7 @@ var _ = Describe("Validation", func() {
 		Expect(s.Tags).To(HaveKeyWithValue("tag2", "value2"))
 		Expect(s.Tags).To(HaveKeyWithValue("example.com/tag", "my-value"))
 		Expect(s.ReservedENIs).To(Equal(1))
+		Expect(s.SpotPriceUpdateInterval).To(Equal(time.Duration(10) * time.Minute))
 	})
 	It("should succeed when setting values that no longer exist (backwards compatibility)", func() {
 		cm := &v1.ConfigMap{
@@ -155,6 +161,16 @@ var _ = Describe("Validation", func() {
 		_, err := (&settings.Settings{}).Inject(ctx, cm)
 		Expect(err).To(HaveOccurred())
 	})
+	It("should fail validation when clusterCIDR is invalid", func() {
+		cm := &v1.ConfigMap{
+			Data: map[string]string{
+				"aws.clusterName": "my-name",
+				"aws.clusterCIDR": "10.100.0.0",
+			},
+		}
+		_, err := (&settings.Settings{}).Inject(ctx, cm)
+		Expect(err).To(HaveOccurred())
+	})
 	It("should fail validation with panic when vmMemoryOverheadPercent is negative", func() {
 		cm := &v1.ConfigMap{
 			Data: map[string]string{
@@ -217,4 +233,14 @@ var _ = Describe("Validation", func() {
 		_, err := (&settings.Settings{}).Inject(ctx, cm)
 		Expect(err).To(HaveOccurred())
 	})
+	It("should fail validation with spotPriceUpdateInterval is less than 1m", func() {
+		cm := &v1.ConfigMap{
+			Data: map[string]string{
+				"aws.spotPriceUpdateInterval": "30s",
+				"aws.clusterName":             "my-cluster",
+			},
+		}
+		_, err := (&settings.Settings{}).Inject(ctx, cm)
+		Expect(err).To(HaveOccurred())
+	})
 })
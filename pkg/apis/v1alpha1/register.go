@@ -82,6 +82,7 @@ var (
 	ResourceNVIDIAGPU             v1.ResourceName         = "nvidia.com/gpu"
 	ResourceAMDGPU                v1.ResourceName         = "amd.com/gpu"
 	ResourceAWSNeuron             v1.ResourceName         = "aws.amazon.com/neuron"
+	ResourceAWSNeuronCore         v1.ResourceName         = "aws.amazon.com/neuroncore"
 	ResourceHabanaGaudi           v1.ResourceName         = "habana.ai/gaudi"
 	ResourceAWSPodENI             v1.ResourceName         = "vpc.amazonaws.com/pod-eni"
 	ResourcePrivateIPv4Address    v1.ResourceName         = "vpc.amazonaws.com/PrivateIPv4Address"
@@ -96,8 +97,12 @@ var (
 	LabelInstanceLocalNVME                    = LabelDomain + "/instance-local-nvme"
 	LabelInstanceSize                         = LabelDomain + "/instance-size"
 	LabelInstanceCPU                          = LabelDomain + "/instance-cpu"
+	LabelInstanceCPUManufacturer              = LabelDomain + "/instance-cpu-manufacturer"
+	LabelInstanceCPUSustainedClockSpeedMhz    = LabelDomain + "/instance-cpu-sustained-clock-speed-mhz"
 	LabelInstanceMemory                       = LabelDomain + "/instance-memory"
+	LabelInstanceEBSBandwidth                 = LabelDomain + "/instance-ebs-bandwidth"
 	LabelInstanceNetworkBandwidth             = LabelDomain + "/instance-network-bandwidth"
+	LabelInstanceENAExpress                   = LabelDomain + "/instance-ena-express"
 	LabelInstancePods                         = LabelDomain + "/instance-pods"
 	LabelInstanceGPUName                      = LabelDomain + "/instance-gpu-name"
 	LabelInstanceGPUManufacturer              = LabelDomain + "/instance-gpu-manufacturer"
@@ -107,6 +112,9 @@ var (
 	LabelInstanceAcceleratorName              = LabelDomain + "/instance-accelerator-name"
 	LabelInstanceAcceleratorManufacturer      = LabelDomain + "/instance-accelerator-manufacturer"
 	LabelInstanceAcceleratorCount             = LabelDomain + "/instance-accelerator-count"
+	LabelInstanceNeuronCoreCount              = LabelDomain + "/instance-neuroncore-count"
+	LabelTopologyZoneID                       = "topology.k8s.aws/zone-id"
+	LabelTopologyZoneType                     = "topology.k8s.aws/zone-type"
 	AnnotationNodeTemplateHash                = LabelDomain + "/nodetemplate-hash"
 )
 
@@ -137,7 +145,11 @@ func init() {
 		LabelInstanceSize,
 		LabelInstanceLocalNVME,
 		LabelInstanceCPU,
+		LabelInstanceCPUManufacturer,
+		LabelInstanceCPUSustainedClockSpeedMhz,
 		LabelInstanceMemory,
+		LabelInstanceEBSBandwidth,
+		LabelInstanceENAExpress,
 		LabelInstanceNetworkBandwidth,
 		LabelInstancePods,
 		LabelInstanceGPUName,
@@ -147,6 +159,9 @@ func init() {
 		LabelInstanceAcceleratorName,
 		LabelInstanceAcceleratorManufacturer,
 		LabelInstanceAcceleratorCount,
+		LabelInstanceNeuronCoreCount,
+		LabelTopologyZoneID,
+		LabelTopologyZoneType,
 		v1.LabelWindowsBuild,
 	)
 }
@@ -42,15 +42,36 @@ type EC2NodeClassSpec struct {
 	// +required
 	SecurityGroupSelectorTerms []SecurityGroupSelectorTerm `json:"securityGroupSelectorTerms" hash:"ignore"`
 	// AMISelectorTerms is a list of or ami selector terms. The terms are ORed.
-	// +kubebuilder:validation:XValidation:message="expected at least one, got none, ['tags', 'id', 'name']",rule="self.all(x, has(x.tags) || has(x.id) || has(x.name))"
+	// +kubebuilder:validation:XValidation:message="expected at least one, got none, ['tags', 'id', 'name', 'ssmParameter']",rule="self.all(x, has(x.tags) || has(x.id) || has(x.name) || has(x.ssmParameter))"
 	// +kubebuilder:validation:XValidation:message="'id' is mutually exclusive, cannot be set with a combination of other fields in amiSelectorTerms",rule="!self.all(x, has(x.id) && (has(x.tags) || has(x.name)) || has(x.owner))"
+	// +kubebuilder:validation:XValidation:message="'ssmParameter' is mutually exclusive, cannot be set with a combination of other fields in amiSelectorTerms",rule="!self.exists(x, has(x.ssmParameter) && (has(x.tags) || has(x.id) || has(x.name) || has(x.owner)))"
 	// +kubebuilder:validation:MaxItems:=30
 	// +optional
 	AMISelectorTerms []AMISelectorTerm `json:"amiSelectorTerms,omitempty" hash:"ignore"`
-	// AMIFamily is the AMI family that instances use.
-	// +kubebuilder:validation:Enum:={AL2,Bottlerocket,Ubuntu,Custom,Windows2019,Windows2022}
+	// AMISoakPeriod is how long a newly published AMI is held back before it's used to launch nodes, and before nodes
+	// launched with an older AMI are drifted. Default AMIs are published when their SSM parameter is updated, and AMIs
+	// selected by name or tags are published when they're created. AMIs selected by id are used immediately.
+	// If omitted, AMIs are used as soon as they're discovered.
+	// +kubebuilder:validation:Type="string"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +optional
+	AMISoakPeriod *metav1.Duration `json:"amiSoakPeriod,omitempty" hash:"ignore"`
+	// AMIRollout progressively rolls out newly resolved AMIs. A new AMI is only used for a percentage of the launches,
+	// including drift replacements, which grows step by step as long as the nodes launched with it are healthy.
+	// If omitted, new AMIs are used for all launches as soon as they're resolved.
+	// +optional
+	AMIRollout *AMIRollout `json:"amiRollout,omitempty" hash:"ignore"`
+	// AMIFamily is the AMI family that instances use. The built-in AMI families are AL2, AL2023, Bottlerocket,
+	// Ubuntu, Custom, Windows2019 and Windows2022. Builds of Karpenter that register additional AMI families add them
+	// to the validation rule of this field.
+	// +kubebuilder:validation:XValidation:message="amiFamily must be one of AL2, AL2023, Bottlerocket, Ubuntu, Custom, Windows2019 or Windows2022",rule="self in ['AL2', 'AL2023', 'Bottlerocket', 'Ubuntu', 'Custom', 'Windows2019', 'Windows2022']"
 	// +required
 	AMIFamily *string `json:"amiFamily"`
+	// CustomAMIFamily declares the properties of the AMIs of the Custom amiFamily, which Karpenter can't otherwise
+	// know. Both the capacity of instance types and the launch templates honor the declared properties. If omitted,
+	// the Custom amiFamily uses the defaults of the EKS optimized AMIs.
+	// +optional
+	CustomAMIFamily *CustomAMIFamily `json:"customAMIFamily,omitempty"`
 	// UserData to be applied to the provisioned nodes.
 	// It must be in the appropriate format based on the AMIFamily in use. Karpenter will merge certain fields into
 	// this UserData to ensure nodes are being provisioned with the correct configuration.
@@ -76,6 +97,23 @@ type EC2NodeClassSpec struct {
 	// +kubebuilder:validation:MaxItems:=50
 	// +optional
 	BlockDeviceMappings []*BlockDeviceMapping `json:"blockDeviceMappings,omitempty"`
+	// InstanceStorePolicy specifies how to handle instance-store disks. When set to RAID0, the instance-store disks are
+	// configured as a RAID0 array that backs the kubelet and container runtime, and the ephemeral-storage capacity of
+	// nodes is the total size of the instance-store disks. If omitted, instance-store disks are not used. Not supported
+	// for the Ubuntu and Windows AMI families, and Custom AMI families must declare supportsInstanceStoreRAID0.
+	// +kubebuilder:validation:Enum:={RAID0}
+	// +optional
+	InstanceStorePolicy *string `json:"instanceStorePolicy,omitempty"`
+	// GPUSharing configures how the NVIDIA GPUs of provisioned nodes are shared between pods. The GPUs are either
+	// partitioned into MIG instances of a single profile or advertised as multiple time-sliced replicas by the NVIDIA
+	// device plugin of Bottlerocket, so it's only supported when amiFamily is Bottlerocket. If omitted, each GPU is
+	// advertised as a single nvidia.com/gpu.
+	// +optional
+	GPUSharing *GPUSharing `json:"gpuSharing,omitempty"`
+	// Bottlerocket configures the Bottlerocket settings of provisioned nodes that would otherwise be written as TOML in
+	// the userData. They're merged over the settings of the userData, and are only supported when amiFamily is Bottlerocket.
+	// +optional
+	Bottlerocket *Bottlerocket `json:"bottlerocket,omitempty"`
 	// DetailedMonitoring controls if detailed monitoring is enabled for instances that are launched
 	// +optional
 	DetailedMonitoring *bool `json:"detailedMonitoring,omitempty"`
@@ -96,6 +134,11 @@ type EC2NodeClassSpec struct {
 	// +kubebuilder:default={"httpEndpoint":"enabled","httpProtocolIPv6":"disabled","httpPutResponseHopLimit":2,"httpTokens":"required"}
 	// +optional
 	MetadataOptions *MetadataOptions `json:"metadataOptions,omitempty"`
+	// InstanceTypeRanking controls how instance types are ranked when launching nodes. The ranking determines
+	// both the order of instance types and which instance types are sent to EC2 when there are more candidates
+	// than can be sent in a single launch request. If omitted, instance types are ranked by absolute price.
+	// +optional
+	InstanceTypeRanking *InstanceTypeRanking `json:"instanceTypeRanking,omitempty" hash:"ignore"`
 	// Context is a Reserved field in EC2 APIs
 	// https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_CreateFleet.html
 	// +optional
@@ -181,6 +224,17 @@ type AMISelectorTerm struct {
 	// You can specify a combination of AWS account IDs, "self", "amazon", and "aws-marketplace"
 	// +optional
 	Owner string `json:"owner,omitempty"`
+	// SSMParameter is the name of an SSM parameter that contains the ami id.
+	// The name is a template that's rendered with {{.KubernetesVersion}} and {{.Arch}} (amd64 or arm64),
+	// and an ami is selected for each architecture that the parameter exists for.
+	// +kubebuilder:validation:Pattern:="^/"
+	// +kubebuilder:validation:MaxLength:=2048
+	// +optional
+	SSMParameter string `json:"ssmParameter,omitempty"`
+	// IncludeDeprecated selects deprecated amis, which aren't selected by default.
+	// Amis that are being deregistered are never selected.
+	// +optional
+	IncludeDeprecated bool `json:"includeDeprecated,omitempty"`
 }
 
 // MetadataOptions contains parameters for specifying the exposure of the
@@ -233,6 +287,173 @@ type MetadataOptions struct {
 	HTTPTokens *string `json:"httpTokens,omitempty"`
 }
 
+// InstanceTypeRanking defines how instance types are ranked against each other when launching nodes.
+// +kubebuilder:validation:XValidation:message="performanceScores is required when policy is 'PricePerPerformanceScore'",rule="self.policy == 'PricePerPerformanceScore' ? has(self.performanceScores) && self.performanceScores.size() != 0 : true"
+type InstanceTypeRanking struct {
+	// Policy is the ranking policy that is applied to the cheapest available offering of each instance type.
+	// Price ranks by absolute price, PricePerVCPU ranks by price divided by the vCPU count, PricePerGiB ranks
+	// by price divided by the memory in GiB, and PricePerPerformanceScore ranks by price divided by the
+	// performance score of the instance family.
+	// +kubebuilder:validation:Enum:={Price,PricePerVCPU,PricePerGiB,PricePerPerformanceScore}
+	// +required
+	Policy string `json:"policy"`
+	// PerformanceScores is a map of instance family (for example, c7g) to a relative performance score that is
+	// used by the PricePerPerformanceScore policy. A higher score represents more work per instance of the family.
+	// Instance families without a score are ranked after all scored instance families.
+	// +kubebuilder:validation:XValidation:message="empty instance family keys aren't supported",rule="self.all(k, k != '')"
+	// +kubebuilder:validation:XValidation:message="performance scores must be greater than zero",rule="self.all(k, self[k] > 0)"
+	// +optional
+	PerformanceScores map[string]int64 `json:"performanceScores,omitempty"`
+}
+
+// CustomAMIFamily declares the properties of custom AMIs.
+type CustomAMIFamily struct {
+	// FeatureFlags declare which features of the EKS optimized AMIs the AMIs support. Features that aren't declared
+	// are assumed to be supported.
+	// +optional
+	FeatureFlags *CustomAMIFamilyFeatureFlags `json:"featureFlags,omitempty"`
+	// EphemeralBlockDevice is the device name of the block device that backs the kubelet root dir, and so the
+	// ephemeral-storage of nodes. If omitted, the kubelet root dir is assumed to be on the root device of the AMI.
+	// +kubebuilder:validation:Pattern:="^/dev/[a-z0-9]+$"
+	// +optional
+	EphemeralBlockDevice *string `json:"ephemeralBlockDevice,omitempty"`
+	// KubeReservedModel is how the resources that are reserved for kubernetes system daemons are computed when they
+	// aren't configured in the kubelet configuration of the NodePool. EKS reserves resources the same way as the EKS
+	// optimized AMIs, and None doesn't reserve any resources. If omitted, defaults to EKS.
+	// +kubebuilder:validation:Enum:={EKS,None}
+	// +optional
+	KubeReservedModel *string `json:"kubeReservedModel,omitempty"`
+	// Architectures are the architectures that the AMIs support. Instance types of other architectures aren't
+	// launched. If omitted, the architecture of each AMI is used.
+	// +kubebuilder:validation:XValidation:message="architectures must be amd64 or arm64",rule="self.all(x, x == 'amd64' || x == 'arm64')"
+	// +kubebuilder:validation:MaxItems:=2
+	// +optional
+	Architectures []string `json:"architectures,omitempty"`
+	// OperatingSystem is the operating system of the AMIs, which nodes are labeled with. If omitted, defaults to linux.
+	// +kubebuilder:validation:Enum:={linux,windows}
+	// +optional
+	OperatingSystem *string `json:"operatingSystem,omitempty"`
+}
+
+// CustomAMIFamilyFeatureFlags declare which features of the EKS optimized AMIs custom AMIs support.
+type CustomAMIFamilyFeatureFlags struct {
+	// UsesENILimitedMemoryOverhead declares whether the memory that's reserved for kubernetes system daemons is
+	// computed from the ENI limited number of pods rather than the maximum number of pods.
+	// +optional
+	UsesENILimitedMemoryOverhead *bool `json:"usesENILimitedMemoryOverhead,omitempty"`
+	// PodsPerCoreEnabled declares whether the AMIs support the podsPerCore kubelet configuration.
+	// +optional
+	PodsPerCoreEnabled *bool `json:"podsPerCoreEnabled,omitempty"`
+	// EvictionSoftEnabled declares whether the AMIs support the evictionSoft kubelet configuration.
+	// +optional
+	EvictionSoftEnabled *bool `json:"evictionSoftEnabled,omitempty"`
+	// SupportsENILimitedPodDensity declares whether the AMIs limit the number of pods to the number of pods that the
+	// ENIs of an instance type support.
+	// +optional
+	SupportsENILimitedPodDensity *bool `json:"supportsENILimitedPodDensity,omitempty"`
+	// SupportsInstanceStoreRAID0 declares whether the AMIs assemble the instance-store disks into a RAID0 array that
+	// backs the kubelet and container runtime, which instanceStorePolicy RAID0 requires. Unlike the other features, it's
+	// assumed to be unsupported if it isn't declared.
+	// +optional
+	SupportsInstanceStoreRAID0 *bool `json:"supportsInstanceStoreRAID0,omitempty"`
+}
+
+// AMIRollout defines how newly resolved AMIs are rolled out.
+type AMIRollout struct {
+	// Steps are the percentages of nodes that are launched with a new AMI at each step of its rollout. The rollout
+	// completes once the last step has lasted for the step duration, after which the new AMI is used for all nodes.
+	// +kubebuilder:validation:XValidation:message="steps must be between 1 and 100",rule="self.all(x, x >= 1 && x <= 100)"
+	// +kubebuilder:validation:MinItems:=1
+	// +kubebuilder:validation:MaxItems:=10
+	// +required
+	Steps []int32 `json:"steps"`
+	// StepDuration is how long each step lasts before moving on to the next one. A step doesn't move on while nodes
+	// launched with the new AMI fail to register or aren't ready.
+	// +kubebuilder:validation:Type="string"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +required
+	StepDuration metav1.Duration `json:"stepDuration"`
+}
+
+// GPUSharing defines how NVIDIA GPUs are shared between pods.
+// +kubebuilder:validation:XValidation:message="migProfile is required when strategy is 'MIG'",rule="self.strategy == 'MIG' ? has(self.migProfile) : true"
+// +kubebuilder:validation:XValidation:message="replicas is required when strategy is 'TimeSlicing'",rule="self.strategy == 'TimeSlicing' ? has(self.replicas) : true"
+// +kubebuilder:validation:XValidation:message="migProfile is only supported when strategy is 'MIG'",rule="has(self.migProfile) ? self.strategy == 'MIG' : true"
+// +kubebuilder:validation:XValidation:message="replicas is only supported when strategy is 'TimeSlicing'",rule="has(self.replicas) ? self.strategy == 'TimeSlicing' : true"
+type GPUSharing struct {
+	// Strategy is the GPU sharing strategy. MIG partitions each MIG capable GPU into as many instances of the
+	// MIG profile as fit on the GPU, which are advertised as nvidia.com/mig-<profile>. TimeSlicing advertises
+	// each GPU as replicas of nvidia.com/gpu.
+	// +kubebuilder:validation:Enum:={MIG,TimeSlicing}
+	// +required
+	Strategy string `json:"strategy"`
+	// MIGProfile is the MIG profile that GPUs are partitioned into when the strategy is MIG, for example 1g.10gb.
+	// +kubebuilder:validation:Pattern:="^[1-7]g\\.[0-9]+gb$"
+	// +optional
+	MIGProfile *string `json:"migProfile,omitempty"`
+	// Replicas is the number of replicas that each GPU is advertised as when the strategy is TimeSlicing.
+	// +kubebuilder:validation:Minimum:=2
+	// +kubebuilder:validation:Maximum:=64
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+// Bottlerocket is the typed subset of the Bottlerocket settings, see https://bottlerocket.dev/en/os/latest/api/settings/
+type Bottlerocket struct {
+	// BootstrapContainers are containers that run before the kubelet starts, keyed by the name of the container.
+	// +kubebuilder:validation:XValidation:message="container names must consist of alphanumeric characters, '-' or '_'",rule="self.all(k, k.matches('^[a-zA-Z0-9][a-zA-Z0-9_-]*$'))"
+	// +kubebuilder:validation:MaxProperties:=10
+	// +optional
+	BootstrapContainers map[string]BottlerocketBootstrapContainer `json:"bootstrapContainers,omitempty"`
+	// HostContainers are containers that run alongside the container runtime of the host, keyed by the name of the
+	// container. The admin and control host containers are built into Bottlerocket, so their source is optional.
+	// +kubebuilder:validation:XValidation:message="container names must consist of alphanumeric characters, '-' or '_'",rule="self.all(k, k.matches('^[a-zA-Z0-9][a-zA-Z0-9_-]*$'))"
+	// +kubebuilder:validation:XValidation:message="source is required for host containers other than admin and control",rule="self.all(k, k == 'admin' || k == 'control' || has(self[k].source))"
+	// +kubebuilder:validation:MaxProperties:=10
+	// +optional
+	HostContainers map[string]BottlerocketHostContainer `json:"hostContainers,omitempty"`
+	// KernelLockdown is the lockdown mode of the kernel. If omitted, the default of the Bottlerocket variant is used.
+	// +kubebuilder:validation:Enum:={none,integrity,confidentiality}
+	// +optional
+	KernelLockdown *string `json:"kernelLockdown,omitempty"`
+}
+
+// BottlerocketBootstrapContainer is a container that runs before the kubelet starts
+type BottlerocketBootstrapContainer struct {
+	// Source is the image of the container.
+	// +kubebuilder:validation:MinLength:=1
+	// +required
+	Source string `json:"source"`
+	// Mode controls when the container runs. off doesn't run it, once runs it on the next boot only and always runs
+	// it on every boot. If omitted, defaults to always.
+	// +kubebuilder:validation:Enum:={off,once,always}
+	// +optional
+	Mode *string `json:"mode,omitempty"`
+	// Essential fails the boot of the node if the container fails.
+	// +optional
+	Essential *bool `json:"essential,omitempty"`
+	// UserData is passed to the container, which reads it from /.bottlerocket/bootstrap-containers/current/user-data.
+	// +optional
+	UserData *string `json:"userData,omitempty"`
+}
+
+// BottlerocketHostContainer is a container that runs alongside the container runtime of the host
+type BottlerocketHostContainer struct {
+	// Source is the image of the container.
+	// +kubebuilder:validation:MinLength:=1
+	// +optional
+	Source *string `json:"source,omitempty"`
+	// Enabled controls if the container runs.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Superpowered gives the container full privileges on the host.
+	// +optional
+	Superpowered *bool `json:"superpowered,omitempty"`
+	// UserData is passed to the container, which reads it from /.bottlerocket/host-containers/current/user-data.
+	// +optional
+	UserData *string `json:"userData,omitempty"`
+}
+
 type BlockDeviceMapping struct {
 	// The device name (for example, /dev/sdh or xvdh).
 	// +required
@@ -320,6 +541,11 @@ type EC2NodeClass struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	// +kubebuilder:validation:XValidation:message="amiSelectorTerms is required when amiFamily == 'Custom'",rule="self.amiFamily == 'Custom' ? self.amiSelectorTerms.size() != 0 : true"
+	// +kubebuilder:validation:XValidation:message="instanceStorePolicy is not supported when amiFamily is Ubuntu, Windows2019 or Windows2022",rule="has(self.instanceStorePolicy) ? !(self.amiFamily == 'Ubuntu' || self.amiFamily.startsWith('Windows')) : true"
+	// +kubebuilder:validation:XValidation:message="instanceStorePolicy requires customAMIFamily.featureFlags.supportsInstanceStoreRAID0 when amiFamily is Custom",rule="has(self.instanceStorePolicy) && self.amiFamily == 'Custom' ? has(self.customAMIFamily) && has(self.customAMIFamily.featureFlags) && has(self.customAMIFamily.featureFlags.supportsInstanceStoreRAID0) && self.customAMIFamily.featureFlags.supportsInstanceStoreRAID0 : true"
+	// +kubebuilder:validation:XValidation:message="gpuSharing is only supported when amiFamily is Bottlerocket",rule="has(self.gpuSharing) ? self.amiFamily == 'Bottlerocket' : true"
+	// +kubebuilder:validation:XValidation:message="customAMIFamily is only supported when amiFamily is Custom",rule="has(self.customAMIFamily) ? self.amiFamily == 'Custom' : true"
+	// +kubebuilder:validation:XValidation:message="bottlerocket is only supported when amiFamily is Bottlerocket",rule="has(self.bottlerocket) ? self.amiFamily == 'Bottlerocket' : true"
 	Spec   EC2NodeClassSpec   `json:"spec,omitempty"`
 	Status EC2NodeClassStatus `json:"status,omitempty"`
 
@@ -14,7 +14,10 @@ limitations under the License.
 
 package v1beta1
 
-import v1 "k8s.io/api/core/v1"
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // Subnet contains resolved Subnet selector values utilized for node launch
 type Subnet struct {
@@ -24,6 +27,9 @@ type Subnet struct {
 	// The associated availability zone
 	// +required
 	Zone string `json:"zone"`
+	// The ID of the associated availability zone
+	// +optional
+	ZoneID string `json:"zoneID,omitempty"`
 }
 
 // SecurityGroup contains resolved SecurityGroup selector values utilized for node launch
@@ -44,11 +50,49 @@ type AMI struct {
 	// Name of the AMI
 	// +optional
 	Name string `json:"name,omitempty"`
+	// DeprecationTime is the time at which the AMI is or was deprecated
+	// +optional
+	DeprecationTime *metav1.Time `json:"deprecationTime,omitempty"`
+	// EligibleTime is set while the AMI is held back by the amiSoakPeriod, and is the time from which it's used to
+	// launch nodes
+	// +optional
+	EligibleTime *metav1.Time `json:"eligibleTime,omitempty"`
+	// RootDeviceName is the device name of the root volume of the AMI
+	// +optional
+	RootDeviceName string `json:"rootDeviceName,omitempty"`
+	// BlockDeviceMappingMismatches are the ways in which the blockDeviceMappings don't match the root device of the AMI
+	// +optional
+	BlockDeviceMappingMismatches []string `json:"blockDeviceMappingMismatches,omitempty"`
 	// Requirements of the AMI to be utilized on an instance type
 	// +required
 	Requirements []v1.NodeSelectorRequirement `json:"requirements"`
 }
 
+// AMIRolloutStatus contains the state of the rollout of a newly resolved AMI
+type AMIRolloutStatus struct {
+	// ID of the AMI that's being rolled out
+	// +required
+	ID string `json:"id"`
+	// PreviousID of the AMI that's being replaced
+	// +required
+	PreviousID string `json:"previousID"`
+	// PreviousRootDeviceName is the device name of the root volume of the AMI that's being replaced, which nodes that
+	// aren't launched with the new AMI are launched with
+	// +optional
+	PreviousRootDeviceName string `json:"previousRootDeviceName,omitempty"`
+	// Step is the index of the current step of the rollout
+	// +required
+	Step int32 `json:"step"`
+	// StepStartTime is the time at which the current step started
+	// +required
+	StepStartTime metav1.Time `json:"stepStartTime"`
+	// Halted is set once nodes launched with the AMI fail to register or aren't ready. Nodes aren't launched with
+	// the AMI while the rollout is halted, and it stays halted until a newer AMI replaces it or the
+	// karpenter.k8s.aws/ami-rollout-resume annotation on the EC2NodeClass is set to its ID.
+	// +optional
+	Halted bool `json:"halted,omitempty"`
+}
+
 // EC2NodeClassStatus contains the resolved state of the EC2NodeClass
 type EC2NodeClassStatus struct {
 	// Subnets contains the current Subnet values that are available to the
@@ -63,6 +107,9 @@ type EC2NodeClassStatus struct {
 	// cluster under the AMI selectors.
 	// +optional
 	AMIs []AMI `json:"amis,omitempty"`
+	// AMIRollouts contains the rollouts of newly resolved AMIs that are in progress
+	// +optional
+	AMIRollouts []AMIRolloutStatus `json:"amiRollouts,omitempty"`
 	// InstanceProfile contains the resolved instance profile for the role
 	// +optional
 	InstanceProfile string `json:"instanceProfile,omitempty"`
@@ -15,14 +15,25 @@ limitations under the License.
 package v1beta1
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"regexp"
 	"strings"
+	"text/template"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/samber/lo"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"knative.dev/pkg/apis"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -33,11 +44,21 @@ const (
 	tagsPath                       = "tags"
 	metadataOptionsPath            = "metadataOptions"
 	blockDeviceMappingsPath        = "blockDeviceMappings"
+	instanceTypeRankingPath        = "instanceTypeRanking"
+	instanceStorePolicyPath        = "instanceStorePolicy"
+	gpuSharingPath                 = "gpuSharing"
+	customAMIFamilyPath            = "customAMIFamily"
+	bottlerocketPath               = "bottlerocket"
+	userDataPath                   = "userData"
 )
 
 var (
-	minVolumeSize = *resource.NewScaledQuantity(1, resource.Giga)
-	maxVolumeSize = *resource.NewScaledQuantity(64, resource.Tera)
+	deviceNameRegex = regexp.MustCompile(`^/dev/[a-z0-9]+$`)
+	minVolumeSize   = *resource.NewScaledQuantity(1, resource.Giga)
+	maxVolumeSize   = *resource.NewScaledQuantity(64, resource.Tera)
+	migProfileRegex = regexp.MustCompile(`^[1-7]g\.[0-9]+gb$`)
+	// containerNameRegex matches the names of Bottlerocket bootstrap and host containers
+	containerNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
 )
 
 func (in *EC2NodeClass) SupportedVerbs() []admissionregistrationv1.OperationType {
@@ -73,6 +94,12 @@ func (in *EC2NodeClassSpec) validate(_ context.Context) (errs *apis.FieldError)
 		in.validateAMIFamily().ViaField(amiFamilyPath),
 		in.validateBlockDeviceMappings().ViaField(blockDeviceMappingsPath),
 		in.validateTags().ViaField(tagsPath),
+		in.validateInstanceTypeRanking().ViaField(instanceTypeRankingPath),
+		in.validateInstanceStorePolicy(),
+		in.validateGPUSharing().ViaField(gpuSharingPath),
+		in.validateCustomAMIFamily().ViaField(customAMIFamilyPath),
+		in.validateBottlerocket().ViaField(bottlerocketPath),
+		in.validateUserData().ViaField(userDataPath),
 	)
 }
 
@@ -129,10 +156,19 @@ func (in *EC2NodeClassSpec) validateAMISelectorTerms() (errs *apis.FieldError) {
 //nolint:gocyclo
 func (in *AMISelectorTerm) validate() (errs *apis.FieldError) {
 	errs = errs.Also(validateTags(in.Tags).ViaField("tags"))
-	if len(in.Tags) == 0 && in.ID == "" && in.Name == "" {
-		errs = errs.Also(apis.ErrGeneric("expect at least one, got none", "tags", "id", "name"))
+	if len(in.Tags) == 0 && in.ID == "" && in.Name == "" && in.SSMParameter == "" {
+		errs = errs.Also(apis.ErrGeneric("expect at least one, got none", "tags", "id", "name", "ssmParameter"))
 	} else if in.ID != "" && (len(in.Tags) > 0 || in.Name != "" || in.Owner != "") {
 		errs = errs.Also(apis.ErrGeneric(`"id" is mutually exclusive, cannot be set with a combination of other fields in`))
+	} else if in.SSMParameter != "" && (len(in.Tags) > 0 || in.ID != "" || in.Name != "" || in.Owner != "") {
+		errs = errs.Also(apis.ErrGeneric(`"ssmParameter" is mutually exclusive, cannot be set with a combination of other fields in`))
+	}
+	if in.SSMParameter != "" {
+		if !strings.HasPrefix(in.SSMParameter, "/") {
+			errs = errs.Also(apis.ErrInvalidValue(in.SSMParameter, "ssmParameter", "expected a parameter name that starts with '/'"))
+		} else if _, err := template.New("ssmParameter").Parse(in.SSMParameter); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(in.SSMParameter, "ssmParameter", fmt.Sprintf("parsing template, %s", err)))
+		}
 	}
 	return errs
 }
@@ -267,6 +303,8 @@ func (in *EC2NodeClassSpec) validateAMIFamily() (errs *apis.FieldError) {
 	if in.AMIFamily == nil {
 		return nil
 	}
+	// AMI families aren't an enum of the CRD, since downstream builds can register their own
+	errs = errs.Also(in.validateStringEnum(*in.AMIFamily, apis.CurrentField, SupportedAMIFamilies))
 	if *in.AMIFamily == AMIFamilyCustom && len(in.AMISelectorTerms) == 0 {
 		errs = errs.Also(apis.ErrMissingField(amiSelectorTermsPath))
 	}
@@ -288,6 +326,210 @@ func (in *EC2NodeClassSpec) validateTags() (errs *apis.FieldError) {
 	return errs
 }
 
+func (in *EC2NodeClassSpec) validateInstanceTypeRanking() (errs *apis.FieldError) {
+	if in.InstanceTypeRanking == nil {
+		return nil
+	}
+	errs = errs.Also(in.validateStringEnum(in.InstanceTypeRanking.Policy, "policy", SupportedInstanceTypeRankingPolicies))
+	if in.InstanceTypeRanking.Policy == InstanceTypeRankingPolicyPricePerPerformanceScore && len(in.InstanceTypeRanking.PerformanceScores) == 0 {
+		errs = errs.Also(apis.ErrMissingField("performanceScores"))
+	}
+	for family, score := range in.InstanceTypeRanking.PerformanceScores {
+		if family == "" {
+			errs = errs.Also(apis.ErrInvalidKeyName(`""`, "performanceScores"))
+		}
+		if score <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(score, "performanceScores", "performance scores must be greater than zero").ViaKey(family))
+		}
+	}
+	return errs
+}
+
+func (in *EC2NodeClassSpec) validateInstanceStorePolicy() *apis.FieldError {
+	if in.InstanceStorePolicy == nil {
+		return nil
+	}
+	if err := in.validateStringEnum(*in.InstanceStorePolicy, instanceStorePolicyPath, SupportedInstanceStorePolicies); err != nil {
+		return err
+	}
+	// The bootstraps of Ubuntu and Windows don't set up the instance-store disks, while custom AMIs have to declare it
+	switch family := lo.FromPtr(in.AMIFamily); family {
+	case AMIFamilyUbuntu, AMIFamilyWindows2019, AMIFamilyWindows2022:
+		return apis.ErrGeneric(fmt.Sprintf("%s is not supported when %s is %s", instanceStorePolicyPath, amiFamilyPath, family), instanceStorePolicyPath)
+	case AMIFamilyCustom:
+		if in.CustomAMIFamily == nil || in.CustomAMIFamily.FeatureFlags == nil || !lo.FromPtr(in.CustomAMIFamily.FeatureFlags.SupportsInstanceStoreRAID0) {
+			return apis.ErrGeneric(fmt.Sprintf("%s requires %s.featureFlags.supportsInstanceStoreRAID0 when %s is %s", instanceStorePolicyPath, customAMIFamilyPath, amiFamilyPath, family), instanceStorePolicyPath)
+		}
+	}
+	return nil
+}
+
+func (in *EC2NodeClassSpec) validateCustomAMIFamily() (errs *apis.FieldError) {
+	if in.CustomAMIFamily == nil {
+		return nil
+	}
+	if family := lo.FromPtr(in.AMIFamily); family != AMIFamilyCustom {
+		return apis.ErrGeneric(fmt.Sprintf("%s is only supported when %s is %s", customAMIFamilyPath, amiFamilyPath, AMIFamilyCustom))
+	}
+	if device := in.CustomAMIFamily.EphemeralBlockDevice; device != nil && !deviceNameRegex.MatchString(*device) {
+		errs = errs.Also(apis.ErrInvalidValue(*device, "ephemeralBlockDevice", fmt.Sprintf("expected a device name matching %q", deviceNameRegex.String())))
+	}
+	if model := in.CustomAMIFamily.KubeReservedModel; model != nil {
+		errs = errs.Also(in.validateStringEnum(*model, "kubeReservedModel", SupportedKubeReservedModels))
+	}
+	for i, arch := range in.CustomAMIFamily.Architectures {
+		errs = errs.Also(in.validateStringEnum(arch, apis.CurrentField, WellKnownArchitectures.List()).ViaFieldIndex("architectures", i))
+	}
+	if os := in.CustomAMIFamily.OperatingSystem; os != nil {
+		errs = errs.Also(in.validateStringEnum(*os, "operatingSystem", SupportedOperatingSystems))
+	}
+	return errs
+}
+
+func (in *EC2NodeClassSpec) validateBottlerocket() (errs *apis.FieldError) {
+	if in.Bottlerocket == nil {
+		return nil
+	}
+	if family := lo.FromPtr(in.AMIFamily); family != AMIFamilyBottlerocket {
+		return apis.ErrGeneric(fmt.Sprintf("%s is only supported when %s is %s", bottlerocketPath, amiFamilyPath, AMIFamilyBottlerocket))
+	}
+	for name, container := range in.Bottlerocket.BootstrapContainers {
+		errs = errs.Also(validateContainerName(name).ViaField("bootstrapContainers"))
+		if container.Source == "" {
+			errs = errs.Also(apis.ErrMissingField("source").ViaKey(name).ViaField("bootstrapContainers"))
+		}
+		if container.Mode != nil {
+			errs = errs.Also(in.validateStringEnum(*container.Mode, "mode", SupportedBottlerocketContainerModes).ViaKey(name).ViaField("bootstrapContainers"))
+		}
+	}
+	for name, container := range in.Bottlerocket.HostContainers {
+		errs = errs.Also(validateContainerName(name).ViaField("hostContainers"))
+		// The admin and control host containers are built into Bottlerocket, other host containers need a source
+		if lo.FromPtr(container.Source) == "" && name != "admin" && name != "control" {
+			errs = errs.Also(apis.ErrMissingField("source").ViaKey(name).ViaField("hostContainers"))
+		}
+	}
+	if lockdown := in.Bottlerocket.KernelLockdown; lockdown != nil {
+		errs = errs.Also(in.validateStringEnum(*lockdown, "kernelLockdown", SupportedBottlerocketKernelLockdowns))
+	}
+	return errs
+}
+
+func validateContainerName(name string) *apis.FieldError {
+	if !containerNameRegex.MatchString(name) {
+		return apis.ErrInvalidKeyName(name, apis.CurrentField, fmt.Sprintf("expected a container name matching %q", containerNameRegex.String()))
+	}
+	return nil
+}
+
+// validateUserData validates the custom user data of the AMI families that merge it into a mime document, so that
+// user data that cloud-init can't parse is rejected rather than failing on boot
+func (in *EC2NodeClassSpec) validateUserData() *apis.FieldError {
+	if in.UserData == nil {
+		return nil
+	}
+	// An EC2NodeClass without an amiFamily uses AL2
+	switch lo.FromPtr(in.AMIFamily) {
+	case "", AMIFamilyAL2, AMIFamilyAL2023, AMIFamilyUbuntu:
+	default:
+		return nil
+	}
+	userData := strings.TrimSpace(*in.UserData)
+	if !strings.HasPrefix(userData, "MIME-Version:") && !strings.HasPrefix(userData, "Content-Type:") {
+		if strings.HasPrefix(userData, "#cloud-config") {
+			return validateCloudConfig(userData)
+		}
+		return nil
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(userData))
+	if err != nil {
+		return apis.ErrGeneric(fmt.Sprintf("parsing mime user data, %s", err), apis.CurrentField)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		return apis.ErrGeneric("mime user data must have a multipart content type", apis.CurrentField)
+	}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return apis.ErrGeneric(fmt.Sprintf("parsing mime user data, %s", err), apis.CurrentField)
+		}
+		body, err := io.ReadAll(part)
+		if err != nil {
+			return apis.ErrGeneric(fmt.Sprintf("parsing mime user data, %s", err), apis.CurrentField)
+		}
+		if partType, _, err := mime.ParseMediaType(part.Header.Get("Content-Type")); err != nil || partType != "text/cloud-config" {
+			continue
+		}
+		// Quoted-printable parts are already decoded by the multipart reader
+		switch strings.ToLower(strings.TrimSpace(part.Header.Get("Content-Transfer-Encoding"))) {
+		case "", "7bit", "8bit", "binary":
+		case "base64":
+			if body, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(body)), "")); err != nil {
+				return apis.ErrGeneric(fmt.Sprintf("decoding base64 cloud-config user data, %s", err), apis.CurrentField)
+			}
+		default:
+			continue
+		}
+		// Compressed parts are decompressed by cloud-init, so they're left to it
+		if bytes.HasPrefix(body, gzipMagic) {
+			continue
+		}
+		if err := validateCloudConfig(string(body)); err != nil {
+			return err
+		}
+	}
+}
+
+// gzipMagic are the leading bytes of gzip compressed data
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// validateCloudConfig validates that cloud-config user data is a yaml mapping
+func validateCloudConfig(cloudConfig string) *apis.FieldError {
+	if err := yaml.Unmarshal([]byte(cloudConfig), &map[string]interface{}{}); err != nil {
+		return apis.ErrGeneric(fmt.Sprintf("parsing cloud-config user data, %s", err), apis.CurrentField)
+	}
+	return nil
+}
+
+func (in *EC2NodeClassSpec) validateGPUSharing() (errs *apis.FieldError) {
+	if in.GPUSharing == nil {
+		return nil
+	}
+	// the GPUs are shared by the NVIDIA device plugin of Bottlerocket, which the bootstrap configures
+	if family := lo.FromPtr(in.AMIFamily); family != AMIFamilyBottlerocket {
+		return apis.ErrGeneric(fmt.Sprintf("%s is only supported when %s is %s", gpuSharingPath, amiFamilyPath, AMIFamilyBottlerocket))
+	}
+	errs = errs.Also(in.validateStringEnum(in.GPUSharing.Strategy, "strategy", SupportedGPUSharingStrategies))
+	switch in.GPUSharing.Strategy {
+	case GPUSharingStrategyMIG:
+		if in.GPUSharing.MIGProfile == nil {
+			errs = errs.Also(apis.ErrMissingField("migProfile"))
+		}
+		if in.GPUSharing.Replicas != nil {
+			errs = errs.Also(apis.ErrDisallowedFields("replicas"))
+		}
+	case GPUSharingStrategyTimeSlicing:
+		if in.GPUSharing.Replicas == nil {
+			errs = errs.Also(apis.ErrMissingField("replicas"))
+		}
+		if in.GPUSharing.MIGProfile != nil {
+			errs = errs.Also(apis.ErrDisallowedFields("migProfile"))
+		}
+	}
+	if in.GPUSharing.MIGProfile != nil && !migProfileRegex.MatchString(*in.GPUSharing.MIGProfile) {
+		errs = errs.Also(apis.ErrInvalidValue(*in.GPUSharing.MIGProfile, "migProfile", "expected a MIG profile such as 1g.10gb"))
+	}
+	if in.GPUSharing.Replicas != nil && (*in.GPUSharing.Replicas < 2 || *in.GPUSharing.Replicas > 64) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*in.GPUSharing.Replicas, 2, 64, "replicas"))
+	}
+	return errs
+}
+
 func (in *EC2NodeClassSpec) validateRoleImmutability(originalSpec *EC2NodeClassSpec) *apis.FieldError {
 	if in.Role != originalSpec.Role {
 		return &apis.FieldError{
@@ -15,6 +15,10 @@ limitations under the License.
 package v1beta1_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
 	"strings"
 
 	"github.com/Pallinder/go-randomdata"
@@ -61,6 +65,136 @@ var _ = Describe("Webhook/Validation", func() {
 		It("should succeed if user data is empty", func() {
 			Expect(nc.Validate(ctx)).To(Succeed())
 		})
+		It("should succeed for a shell script", func() {
+			nc.Spec.UserData = aws.String("#!/bin/bash\necho hello")
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should succeed for cloud-config", func() {
+			nc.Spec.UserData = aws.String("#cloud-config\npackages:\n  - htop\n")
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail for cloud-config that isn't a yaml mapping", func() {
+			nc.Spec.UserData = aws.String("#cloud-config\n- htop\n")
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail for a mime cloud-config part that isn't valid yaml", func() {
+			nc.Spec.UserData = aws.String(`MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="BOUNDARY"
+
+--BOUNDARY
+Content-Type: text/cloud-config; charset="us-ascii"
+
+#cloud-config
+packages: [htop
+
+--BOUNDARY--`)
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should succeed for mime user data with valid parts", func() {
+			nc.Spec.UserData = aws.String(`MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="BOUNDARY"
+
+--BOUNDARY
+Content-Type: text/cloud-config; charset="us-ascii"
+
+#cloud-config
+packages:
+  - htop
+
+--BOUNDARY
+Content-Type: text/x-shellscript; charset="us-ascii"
+
+#!/bin/bash
+echo hello
+
+--BOUNDARY--`)
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should succeed for a base64 encoded mime cloud-config part", func() {
+			nc.Spec.UserData = aws.String(fmt.Sprintf(`MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="BOUNDARY"
+
+--BOUNDARY
+Content-Type: text/cloud-config; charset="us-ascii"
+Content-Transfer-Encoding: base64
+
+%s
+
+--BOUNDARY--`, base64.StdEncoding.EncodeToString([]byte("#cloud-config\npackages:\n  - htop\n"))))
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail for a base64 encoded mime cloud-config part that isn't valid yaml", func() {
+			nc.Spec.UserData = aws.String(fmt.Sprintf(`MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="BOUNDARY"
+
+--BOUNDARY
+Content-Type: text/cloud-config; charset="us-ascii"
+Content-Transfer-Encoding: base64
+
+%s
+
+--BOUNDARY--`, base64.StdEncoding.EncodeToString([]byte("#cloud-config\npackages: [htop\n"))))
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should succeed for a gzip compressed mime cloud-config part", func() {
+			var compressed bytes.Buffer
+			writer := gzip.NewWriter(&compressed)
+			_, err := writer.Write([]byte("#cloud-config\npackages: [htop\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(writer.Close()).To(Succeed())
+			nc.Spec.UserData = aws.String(fmt.Sprintf(`MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="BOUNDARY"
+
+--BOUNDARY
+Content-Type: text/cloud-config; charset="us-ascii"
+Content-Transfer-Encoding: base64
+
+%s
+
+--BOUNDARY--`, base64.StdEncoding.EncodeToString(compressed.Bytes())))
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail for mime user data that isn't multipart", func() {
+			nc.Spec.UserData = aws.String("MIME-Version: 1.0\nContent-Type: text/plain\n\necho hello")
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should not validate the user data of Bottlerocket", func() {
+			nc.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
+			nc.Spec.UserData = aws.String("#cloud-config\n- htop\n")
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+	})
+	Context("AMIFamily", func() {
+		var supportedAMIFamilies []string
+		BeforeEach(func() {
+			supportedAMIFamilies = append([]string{}, v1beta1.SupportedAMIFamilies...)
+		})
+		AfterEach(func() {
+			// AMI families registered by tests are removed so that they don't pass validation in other tests
+			v1beta1.SupportedAMIFamilies = supportedAMIFamilies
+		})
+		It("should succeed for the built-in AMI families", func() {
+			for _, amiFamily := range []string{
+				v1beta1.AMIFamilyAL2,
+				v1beta1.AMIFamilyAL2023,
+				v1beta1.AMIFamilyBottlerocket,
+				v1beta1.AMIFamilyUbuntu,
+				v1beta1.AMIFamilyWindows2019,
+				v1beta1.AMIFamilyWindows2022,
+			} {
+				nc.Spec.AMIFamily = aws.String(amiFamily)
+				Expect(nc.Validate(ctx)).To(Succeed())
+			}
+		})
+		It("should fail for an AMI family that isn't registered", func() {
+			nc.Spec.AMIFamily = aws.String("Flatcar")
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should succeed for a registered AMI family", func() {
+			v1beta1.RegisterAMIFamily("RHEL")
+			nc.Spec.AMIFamily = aws.String("RHEL")
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
 	})
 	Context("Tags", func() {
 		It("should succeed when tags are empty", func() {
@@ -443,6 +577,263 @@ var _ = Describe("Webhook/Validation", func() {
 			}
 			Expect(nc.Validate(ctx)).ToNot(Succeed())
 		})
+		It("should succeed with a valid ami selector on ssmParameter", func() {
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
+				{
+					SSMParameter: "/golden-ami/{{.KubernetesVersion}}/{{.Arch}}/image_id",
+				},
+			}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail when specifying ssmParameter with tags", func() {
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
+				{
+					SSMParameter: "/golden-ami/image_id",
+					Tags: map[string]string{
+						"test": "testvalue",
+					},
+				},
+			}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail when specifying ssmParameter with owner", func() {
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
+				{
+					SSMParameter: "/golden-ami/image_id",
+					Owner:        "123456789",
+				},
+			}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail when ssmParameter isn't a fully qualified parameter name", func() {
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
+				{
+					SSMParameter: "golden-ami/image_id",
+				},
+			}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail when ssmParameter isn't a valid template", func() {
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
+				{
+					SSMParameter: "/golden-ami/{{.KubernetesVersion/image_id",
+				},
+			}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+	})
+	Context("InstanceTypeRanking", func() {
+		It("should succeed for each supported policy", func() {
+			for _, policy := range []string{
+				v1beta1.InstanceTypeRankingPolicyPrice,
+				v1beta1.InstanceTypeRankingPolicyPricePerVCPU,
+				v1beta1.InstanceTypeRankingPolicyPricePerGiB,
+			} {
+				nc.Spec.InstanceTypeRanking = &v1beta1.InstanceTypeRanking{Policy: policy}
+				Expect(nc.Validate(ctx)).To(Succeed())
+			}
+		})
+		It("should succeed for PricePerPerformanceScore with performance scores", func() {
+			nc.Spec.InstanceTypeRanking = &v1beta1.InstanceTypeRanking{
+				Policy:            v1beta1.InstanceTypeRankingPolicyPricePerPerformanceScore,
+				PerformanceScores: map[string]int64{"c7g": 130, "c6i": 100},
+			}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail for an unsupported policy", func() {
+			nc.Spec.InstanceTypeRanking = &v1beta1.InstanceTypeRanking{Policy: "PricePerNetworkBandwidth"}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail for PricePerPerformanceScore without performance scores", func() {
+			nc.Spec.InstanceTypeRanking = &v1beta1.InstanceTypeRanking{
+				Policy: v1beta1.InstanceTypeRankingPolicyPricePerPerformanceScore,
+			}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail when a performance score is not positive", func() {
+			nc.Spec.InstanceTypeRanking = &v1beta1.InstanceTypeRanking{
+				Policy:            v1beta1.InstanceTypeRankingPolicyPricePerPerformanceScore,
+				PerformanceScores: map[string]int64{"c7g": 0},
+			}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail when a performance score has an empty instance family", func() {
+			nc.Spec.InstanceTypeRanking = &v1beta1.InstanceTypeRanking{
+				Policy:            v1beta1.InstanceTypeRankingPolicyPricePerPerformanceScore,
+				PerformanceScores: map[string]int64{"": 100},
+			}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+	})
+	Context("InstanceStorePolicy", func() {
+		It("should succeed when the instance store policy is RAID0", func() {
+			nc.Spec.InstanceStorePolicy = aws.String(v1beta1.InstanceStorePolicyRAID0)
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail for an unsupported instance store policy", func() {
+			nc.Spec.InstanceStorePolicy = aws.String("RAID1")
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		DescribeTable("should fail when the AMI family doesn't set up the instance store", func(amiFamily string) {
+			nc.Spec.AMIFamily = aws.String(amiFamily)
+			nc.Spec.InstanceStorePolicy = aws.String(v1beta1.InstanceStorePolicyRAID0)
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		},
+			Entry("Ubuntu", v1beta1.AMIFamilyUbuntu),
+			Entry("Windows2019", v1beta1.AMIFamilyWindows2019),
+			Entry("Windows2022", v1beta1.AMIFamilyWindows2022),
+		)
+		It("should fail when the AMI family is Custom and RAID0 support isn't declared", func() {
+			nc.Spec.AMIFamily = &v1beta1.AMIFamilyCustom
+			nc.Spec.InstanceStorePolicy = aws.String(v1beta1.InstanceStorePolicyRAID0)
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+			nc.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{FeatureFlags: &v1beta1.CustomAMIFamilyFeatureFlags{SupportsInstanceStoreRAID0: aws.Bool(false)}}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should succeed when the AMI family is Custom and RAID0 support is declared", func() {
+			nc.Spec.AMIFamily = &v1beta1.AMIFamilyCustom
+			nc.Spec.InstanceStorePolicy = aws.String(v1beta1.InstanceStorePolicyRAID0)
+			nc.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{FeatureFlags: &v1beta1.CustomAMIFamilyFeatureFlags{SupportsInstanceStoreRAID0: aws.Bool(true)}}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+	})
+	Context("GPUSharing", func() {
+		BeforeEach(func() {
+			nc.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
+		})
+		It("should succeed when the strategy is MIG with a MIG profile", func() {
+			nc.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyMIG, MIGProfile: aws.String("1g.10gb")}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should succeed when the strategy is TimeSlicing with replicas", func() {
+			nc.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyTimeSlicing, Replicas: aws.Int32(4)}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail for an unsupported strategy", func() {
+			nc.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: "MPS", Replicas: aws.Int32(4)}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail when the strategy is MIG without a MIG profile", func() {
+			nc.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyMIG}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail when the strategy is MIG with replicas", func() {
+			nc.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyMIG, MIGProfile: aws.String("1g.10gb"), Replicas: aws.Int32(4)}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail for an invalid MIG profile", func() {
+			nc.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyMIG, MIGProfile: aws.String("8g.10gb")}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail when the strategy is TimeSlicing without replicas", func() {
+			nc.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyTimeSlicing}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail when the strategy is TimeSlicing with less than two replicas", func() {
+			nc.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyTimeSlicing, Replicas: aws.Int32(1)}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		DescribeTable("should fail when the AMI family isn't Bottlerocket", func(amiFamily string) {
+			nc.Spec.AMIFamily = aws.String(amiFamily)
+			nc.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyTimeSlicing, Replicas: aws.Int32(4)}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		},
+			Entry("AL2", v1beta1.AMIFamilyAL2),
+			Entry("AL2023", v1beta1.AMIFamilyAL2023),
+			Entry("Ubuntu", v1beta1.AMIFamilyUbuntu),
+			Entry("Windows2022", v1beta1.AMIFamilyWindows2022),
+		)
+	})
+	Context("CustomAMIFamily", func() {
+		BeforeEach(func() {
+			nc.Spec.AMIFamily = &v1beta1.AMIFamilyCustom
+		})
+		It("should succeed when the AMI family is Custom", func() {
+			nc.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{
+				FeatureFlags:         &v1beta1.CustomAMIFamilyFeatureFlags{SupportsENILimitedPodDensity: aws.Bool(false)},
+				EphemeralBlockDevice: aws.String("/dev/xvdb"),
+				KubeReservedModel:    aws.String(v1beta1.KubeReservedModelNone),
+				Architectures:        []string{"arm64"},
+				OperatingSystem:      aws.String("linux"),
+			}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail when the AMI family isn't Custom", func() {
+			nc.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
+			nc.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{EphemeralBlockDevice: aws.String("/dev/xvdb")}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail for an invalid ephemeral block device", func() {
+			nc.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{EphemeralBlockDevice: aws.String("xvdb")}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail for an unsupported kube-reserved model", func() {
+			nc.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{KubeReservedModel: aws.String("GKE")}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail for an unsupported architecture", func() {
+			nc.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{Architectures: []string{"amd64", "ppc64le"}}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail for an unsupported operating system", func() {
+			nc.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{OperatingSystem: aws.String("darwin")}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+	})
+	Context("Bottlerocket", func() {
+		BeforeEach(func() {
+			nc.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
+		})
+		It("should succeed when the AMI family is Bottlerocket", func() {
+			nc.Spec.Bottlerocket = &v1beta1.Bottlerocket{
+				BootstrapContainers: map[string]v1beta1.BottlerocketBootstrapContainer{
+					"setup": {Source: "public.ecr.aws/example/setup:latest", Mode: aws.String(v1beta1.BottlerocketContainerModeOnce)},
+				},
+				HostContainers: map[string]v1beta1.BottlerocketHostContainer{
+					"admin":   {Enabled: aws.Bool(true)},
+					"metrics": {Source: aws.String("public.ecr.aws/example/metrics:latest"), Enabled: aws.Bool(true)},
+				},
+				KernelLockdown: aws.String("integrity"),
+			}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail when the AMI family isn't Bottlerocket", func() {
+			nc.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
+			nc.Spec.Bottlerocket = &v1beta1.Bottlerocket{KernelLockdown: aws.String("integrity")}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail for a bootstrap container without a source", func() {
+			nc.Spec.Bottlerocket = &v1beta1.Bottlerocket{
+				BootstrapContainers: map[string]v1beta1.BottlerocketBootstrapContainer{"setup": {}},
+			}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail for an unsupported bootstrap container mode", func() {
+			nc.Spec.Bottlerocket = &v1beta1.Bottlerocket{
+				BootstrapContainers: map[string]v1beta1.BottlerocketBootstrapContainer{
+					"setup": {Source: "public.ecr.aws/example/setup:latest", Mode: aws.String("sometimes")},
+				},
+			}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail for an invalid container name", func() {
+			nc.Spec.Bottlerocket = &v1beta1.Bottlerocket{
+				BootstrapContainers: map[string]v1beta1.BottlerocketBootstrapContainer{
+					"set up": {Source: "public.ecr.aws/example/setup:latest"},
+				},
+			}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail for a host container without a source that isn't built into Bottlerocket", func() {
+			nc.Spec.Bottlerocket = &v1beta1.Bottlerocket{
+				HostContainers: map[string]v1beta1.BottlerocketHostContainer{"metrics": {Enabled: aws.Bool(true)}},
+			}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail for an unsupported kernel lockdown", func() {
+			nc.Spec.Bottlerocket = &v1beta1.Bottlerocket{KernelLockdown: aws.String("strict")}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
 	})
 	Context("EC2NodeClass Hash", func() {
 		var nodeClass *v1beta1.EC2NodeClass
@@ -486,6 +877,8 @@ var _ = Describe("Webhook/Validation", func() {
 			Entry("Context Drift", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{Context: aws.String("context-2")}}),
 			Entry("DetailedMonitoring Drift", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{DetailedMonitoring: aws.Bool(true)}}),
 			Entry("AMIFamily Drift", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{AMIFamily: aws.String(v1alpha1.AMIFamilyBottlerocket)}}),
+			Entry("InstanceStorePolicy Drift", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{InstanceStorePolicy: aws.String(v1beta1.InstanceStorePolicyRAID0)}}),
+			Entry("GPUSharing Drift", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{GPUSharing: &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyTimeSlicing, Replicas: aws.Int32(4)}}}),
 		)
 		DescribeTable("should not change hash when slices are re-ordered", func(changes v1beta1.EC2NodeClass) {
 			hash := nodeClass.Hash()
@@ -518,6 +911,11 @@ var _ = Describe("Webhook/Validation", func() {
 			updatedHash := nodeClass.Hash()
 			Expect(hash).To(Equal(updatedHash))
 		})
+		It("should not change hash when the instance type ranking is updated", func() {
+			hash := nodeClass.Hash()
+			nodeClass.Spec.InstanceTypeRanking = &v1beta1.InstanceTypeRanking{Policy: v1beta1.InstanceTypeRankingPolicyPricePerVCPU}
+			Expect(nodeClass.Hash()).To(Equal(hash))
+		})
 		It("should expect two provisioner with the same spec to have the same provisioner hash", func() {
 			otherNodeClass := test.EC2NodeClass(v1beta1.EC2NodeClass{
 				Spec: nodeClass.Spec,
@@ -19,6 +19,7 @@ import (
 	"regexp"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 
@@ -37,7 +38,11 @@ func init() {
 		LabelInstanceSize,
 		LabelInstanceLocalNVME,
 		LabelInstanceCPU,
+		LabelInstanceCPUManufacturer,
+		LabelInstanceCPUSustainedClockSpeedMhz,
 		LabelInstanceMemory,
+		LabelInstanceEBSBandwidth,
+		LabelInstanceENAExpress,
 		LabelInstanceNetworkBandwidth,
 		LabelInstancePods,
 		LabelInstanceGPUName,
@@ -47,6 +52,9 @@ func init() {
 		LabelInstanceAcceleratorName,
 		LabelInstanceAcceleratorManufacturer,
 		LabelInstanceAcceleratorCount,
+		LabelInstanceNeuronCoreCount,
+		LabelTopologyZoneID,
+		LabelTopologyZoneType,
 		v1.LabelWindowsBuild,
 	)
 }
@@ -80,6 +88,7 @@ var (
 	}
 	AMIFamilyBottlerocket = "Bottlerocket"
 	AMIFamilyAL2          = "AL2"
+	AMIFamilyAL2023       = "AL2023"
 	AMIFamilyUbuntu       = "Ubuntu"
 	AMIFamilyWindows2019  = "Windows2019"
 	AMIFamilyWindows2022  = "Windows2022"
@@ -87,25 +96,79 @@ var (
 	SupportedAMIFamilies  = []string{
 		AMIFamilyBottlerocket,
 		AMIFamilyAL2,
+		AMIFamilyAL2023,
 		AMIFamilyUbuntu,
 		AMIFamilyWindows2019,
 		AMIFamilyWindows2022,
 		AMIFamilyCustom,
 	}
+	SupportedOperatingSystems = []string{
+		string(v1.Linux),
+		string(v1.Windows),
+	}
+	KubeReservedModelEKS        = "EKS"
+	KubeReservedModelNone       = "None"
+	SupportedKubeReservedModels = []string{
+		KubeReservedModelEKS,
+		KubeReservedModelNone,
+	}
+	InstanceTypeRankingPolicyPrice                    = "Price"
+	InstanceTypeRankingPolicyPricePerVCPU             = "PricePerVCPU"
+	InstanceTypeRankingPolicyPricePerGiB              = "PricePerGiB"
+	InstanceTypeRankingPolicyPricePerPerformanceScore = "PricePerPerformanceScore"
+	SupportedInstanceTypeRankingPolicies              = []string{
+		InstanceTypeRankingPolicyPrice,
+		InstanceTypeRankingPolicyPricePerVCPU,
+		InstanceTypeRankingPolicyPricePerGiB,
+		InstanceTypeRankingPolicyPricePerPerformanceScore,
+	}
+	InstanceStorePolicyRAID0       = "RAID0"
+	SupportedInstanceStorePolicies = []string{
+		InstanceStorePolicyRAID0,
+	}
+	GPUSharingStrategyMIG         = "MIG"
+	GPUSharingStrategyTimeSlicing = "TimeSlicing"
+	SupportedGPUSharingStrategies = []string{
+		GPUSharingStrategyMIG,
+		GPUSharingStrategyTimeSlicing,
+	}
+	BottlerocketContainerModeOff        = "off"
+	BottlerocketContainerModeOnce       = "once"
+	BottlerocketContainerModeAlways     = "always"
+	SupportedBottlerocketContainerModes = []string{
+		BottlerocketContainerModeOff,
+		BottlerocketContainerModeOnce,
+		BottlerocketContainerModeAlways,
+	}
+	SupportedBottlerocketKernelLockdowns = []string{
+		"none",
+		"integrity",
+		"confidentiality",
+	}
+	ZoneTypeAvailabilityZone                   = "availability-zone"
+	ZoneTypeLocalZone                          = "local-zone"
+	ZoneTypeWavelengthZone                     = "wavelength-zone"
 	Windows2019                                = "2019"
 	Windows2022                                = "2022"
 	WindowsCore                                = "Core"
 	Windows2019Build                           = "10.0.17763"
 	Windows2022Build                           = "10.0.20348"
 	ResourceNVIDIAGPU          v1.ResourceName = "nvidia.com/gpu"
+	ResourceNVIDIAMIGPrefix                    = "nvidia.com/mig-"
 	ResourceAMDGPU             v1.ResourceName = "amd.com/gpu"
 	ResourceAWSNeuron          v1.ResourceName = "aws.amazon.com/neuron"
+	ResourceAWSNeuronCore      v1.ResourceName = "aws.amazon.com/neuroncore"
 	ResourceHabanaGaudi        v1.ResourceName = "habana.ai/gaudi"
 	ResourceAWSPodENI          v1.ResourceName = "vpc.amazonaws.com/pod-eni"
 	ResourcePrivateIPv4Address v1.ResourceName = "vpc.amazonaws.com/PrivateIPv4Address"
 
 	LabelNodeClass = Group + "/nodeclass"
 
+	// LabelTopologyZoneID and LabelTopologyZoneType are the zone ID, which is consistent across accounts, and the zone
+	// type (availability-zone, local-zone or wavelength-zone) of the zone that a node is launched into
+	LabelTopologyZoneID   = "topology.k8s.aws/zone-id"
+	LabelTopologyZoneType = "topology.k8s.aws/zone-type"
+
 	LabelInstanceHypervisor                   = Group + "/instance-hypervisor"
 	LabelInstanceEncryptionInTransitSupported = Group + "/instance-encryption-in-transit-supported"
 	LabelInstanceCategory                     = Group + "/instance-category"
@@ -114,8 +177,12 @@ var (
 	LabelInstanceLocalNVME                    = Group + "/instance-local-nvme"
 	LabelInstanceSize                         = Group + "/instance-size"
 	LabelInstanceCPU                          = Group + "/instance-cpu"
+	LabelInstanceCPUManufacturer              = Group + "/instance-cpu-manufacturer"
+	LabelInstanceCPUSustainedClockSpeedMhz    = Group + "/instance-cpu-sustained-clock-speed-mhz"
 	LabelInstanceMemory                       = Group + "/instance-memory"
+	LabelInstanceEBSBandwidth                 = Group + "/instance-ebs-bandwidth"
 	LabelInstanceNetworkBandwidth             = Group + "/instance-network-bandwidth"
+	LabelInstanceENAExpress                   = Group + "/instance-ena-express"
 	LabelInstancePods                         = Group + "/instance-pods"
 	LabelInstanceGPUName                      = Group + "/instance-gpu-name"
 	LabelInstanceGPUManufacturer              = Group + "/instance-gpu-manufacturer"
@@ -124,6 +191,20 @@ var (
 	LabelInstanceAcceleratorName              = Group + "/instance-accelerator-name"
 	LabelInstanceAcceleratorManufacturer      = Group + "/instance-accelerator-manufacturer"
 	LabelInstanceAcceleratorCount             = Group + "/instance-accelerator-count"
+	LabelInstanceNeuronCoreCount              = Group + "/instance-neuroncore-count"
 	AnnotationNodeClassHash                   = Group + "/nodeclass-hash"
 	AnnotationInstanceTagged                  = Group + "/tagged"
+	AnnotationAMIRolloutResume                = Group + "/ami-rollout-resume"
+
+	// TagAMIGPUSupport is the tag that marks whether an AMI that's selected by SSM parameter has GPU drivers, as "true"
+	// or "false". AMIs without the tag are used for all instance types.
+	TagAMIGPUSupport = Group + "/gpu-support"
 )
+
+// RegisterAMIFamily adds an AMI family to the families that EC2NodeClasses are validated against. AMI families are
+// registered through amifamily.Register, which also makes them available to the launch template resolver.
+func RegisterAMIFamily(name string) {
+	if !lo.Contains(SupportedAMIFamilies, name) {
+		SupportedAMIFamilies = append(SupportedAMIFamilies, name)
+	}
+}
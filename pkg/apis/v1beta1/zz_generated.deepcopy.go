@@ -20,12 +20,26 @@ package v1beta1
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AMI) DeepCopyInto(out *AMI) {
 	*out = *in
+	if in.DeprecationTime != nil {
+		in, out := &in.DeprecationTime, &out.DeprecationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EligibleTime != nil {
+		in, out := &in.EligibleTime, &out.EligibleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.BlockDeviceMappingMismatches != nil {
+		in, out := &in.BlockDeviceMappingMismatches, &out.BlockDeviceMappingMismatches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Requirements != nil {
 		in, out := &in.Requirements, &out.Requirements
 		*out = make([]v1.NodeSelectorRequirement, len(*in))
@@ -45,6 +59,43 @@ func (in *AMI) DeepCopy() *AMI {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMIRollout) DeepCopyInto(out *AMIRollout) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	out.StepDuration = in.StepDuration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMIRollout.
+func (in *AMIRollout) DeepCopy() *AMIRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(AMIRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMIRolloutStatus) DeepCopyInto(out *AMIRolloutStatus) {
+	*out = *in
+	in.StepStartTime.DeepCopyInto(&out.StepStartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMIRolloutStatus.
+func (in *AMIRolloutStatus) DeepCopy() *AMIRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AMIRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AMISelectorTerm) DeepCopyInto(out *AMISelectorTerm) {
 	*out = *in
@@ -147,6 +198,185 @@ func (in *BlockDeviceMapping) DeepCopy() *BlockDeviceMapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Bottlerocket) DeepCopyInto(out *Bottlerocket) {
+	*out = *in
+	if in.BootstrapContainers != nil {
+		in, out := &in.BootstrapContainers, &out.BootstrapContainers
+		*out = make(map[string]BottlerocketBootstrapContainer, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.HostContainers != nil {
+		in, out := &in.HostContainers, &out.HostContainers
+		*out = make(map[string]BottlerocketHostContainer, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.KernelLockdown != nil {
+		in, out := &in.KernelLockdown, &out.KernelLockdown
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Bottlerocket.
+func (in *Bottlerocket) DeepCopy() *Bottlerocket {
+	if in == nil {
+		return nil
+	}
+	out := new(Bottlerocket)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BottlerocketBootstrapContainer) DeepCopyInto(out *BottlerocketBootstrapContainer) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(string)
+		**out = **in
+	}
+	if in.Essential != nil {
+		in, out := &in.Essential, &out.Essential
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UserData != nil {
+		in, out := &in.UserData, &out.UserData
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BottlerocketBootstrapContainer.
+func (in *BottlerocketBootstrapContainer) DeepCopy() *BottlerocketBootstrapContainer {
+	if in == nil {
+		return nil
+	}
+	out := new(BottlerocketBootstrapContainer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BottlerocketHostContainer) DeepCopyInto(out *BottlerocketHostContainer) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(string)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Superpowered != nil {
+		in, out := &in.Superpowered, &out.Superpowered
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UserData != nil {
+		in, out := &in.UserData, &out.UserData
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BottlerocketHostContainer.
+func (in *BottlerocketHostContainer) DeepCopy() *BottlerocketHostContainer {
+	if in == nil {
+		return nil
+	}
+	out := new(BottlerocketHostContainer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomAMIFamily) DeepCopyInto(out *CustomAMIFamily) {
+	*out = *in
+	if in.FeatureFlags != nil {
+		in, out := &in.FeatureFlags, &out.FeatureFlags
+		*out = new(CustomAMIFamilyFeatureFlags)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EphemeralBlockDevice != nil {
+		in, out := &in.EphemeralBlockDevice, &out.EphemeralBlockDevice
+		*out = new(string)
+		**out = **in
+	}
+	if in.KubeReservedModel != nil {
+		in, out := &in.KubeReservedModel, &out.KubeReservedModel
+		*out = new(string)
+		**out = **in
+	}
+	if in.Architectures != nil {
+		in, out := &in.Architectures, &out.Architectures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OperatingSystem != nil {
+		in, out := &in.OperatingSystem, &out.OperatingSystem
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomAMIFamily.
+func (in *CustomAMIFamily) DeepCopy() *CustomAMIFamily {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomAMIFamily)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomAMIFamilyFeatureFlags) DeepCopyInto(out *CustomAMIFamilyFeatureFlags) {
+	*out = *in
+	if in.UsesENILimitedMemoryOverhead != nil {
+		in, out := &in.UsesENILimitedMemoryOverhead, &out.UsesENILimitedMemoryOverhead
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PodsPerCoreEnabled != nil {
+		in, out := &in.PodsPerCoreEnabled, &out.PodsPerCoreEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EvictionSoftEnabled != nil {
+		in, out := &in.EvictionSoftEnabled, &out.EvictionSoftEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SupportsENILimitedPodDensity != nil {
+		in, out := &in.SupportsENILimitedPodDensity, &out.SupportsENILimitedPodDensity
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SupportsInstanceStoreRAID0 != nil {
+		in, out := &in.SupportsInstanceStoreRAID0, &out.SupportsInstanceStoreRAID0
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomAMIFamilyFeatureFlags.
+func (in *CustomAMIFamilyFeatureFlags) DeepCopy() *CustomAMIFamilyFeatureFlags {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomAMIFamilyFeatureFlags)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EC2NodeClass) DeepCopyInto(out *EC2NodeClass) {
 	*out = *in
@@ -230,11 +460,26 @@ func (in *EC2NodeClassSpec) DeepCopyInto(out *EC2NodeClassSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AMISoakPeriod != nil {
+		in, out := &in.AMISoakPeriod, &out.AMISoakPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.AMIRollout != nil {
+		in, out := &in.AMIRollout, &out.AMIRollout
+		*out = new(AMIRollout)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AMIFamily != nil {
 		in, out := &in.AMIFamily, &out.AMIFamily
 		*out = new(string)
 		**out = **in
 	}
+	if in.CustomAMIFamily != nil {
+		in, out := &in.CustomAMIFamily, &out.CustomAMIFamily
+		*out = new(CustomAMIFamily)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.UserData != nil {
 		in, out := &in.UserData, &out.UserData
 		*out = new(string)
@@ -258,6 +503,21 @@ func (in *EC2NodeClassSpec) DeepCopyInto(out *EC2NodeClassSpec) {
 			}
 		}
 	}
+	if in.InstanceStorePolicy != nil {
+		in, out := &in.InstanceStorePolicy, &out.InstanceStorePolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.GPUSharing != nil {
+		in, out := &in.GPUSharing, &out.GPUSharing
+		*out = new(GPUSharing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bottlerocket != nil {
+		in, out := &in.Bottlerocket, &out.Bottlerocket
+		*out = new(Bottlerocket)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DetailedMonitoring != nil {
 		in, out := &in.DetailedMonitoring, &out.DetailedMonitoring
 		*out = new(bool)
@@ -268,6 +528,11 @@ func (in *EC2NodeClassSpec) DeepCopyInto(out *EC2NodeClassSpec) {
 		*out = new(MetadataOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.InstanceTypeRanking != nil {
+		in, out := &in.InstanceTypeRanking, &out.InstanceTypeRanking
+		*out = new(InstanceTypeRanking)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Context != nil {
 		in, out := &in.Context, &out.Context
 		*out = new(string)
@@ -336,6 +601,13 @@ func (in *EC2NodeClassStatus) DeepCopyInto(out *EC2NodeClassStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AMIRollouts != nil {
+		in, out := &in.AMIRollouts, &out.AMIRollouts
+		*out = make([]AMIRolloutStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EC2NodeClassStatus.
@@ -348,6 +620,53 @@ func (in *EC2NodeClassStatus) DeepCopy() *EC2NodeClassStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUSharing) DeepCopyInto(out *GPUSharing) {
+	*out = *in
+	if in.MIGProfile != nil {
+		in, out := &in.MIGProfile, &out.MIGProfile
+		*out = new(string)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUSharing.
+func (in *GPUSharing) DeepCopy() *GPUSharing {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUSharing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceTypeRanking) DeepCopyInto(out *InstanceTypeRanking) {
+	*out = *in
+	if in.PerformanceScores != nil {
+		in, out := &in.PerformanceScores, &out.PerformanceScores
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceTypeRanking.
+func (in *InstanceTypeRanking) DeepCopy() *InstanceTypeRanking {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceTypeRanking)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetadataOptions) DeepCopyInto(out *MetadataOptions) {
 	*out = *in
@@ -17,11 +17,13 @@ package cache
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync/atomic"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/patrickmn/go-cache"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"knative.dev/pkg/logging"
 )
 
@@ -47,6 +49,17 @@ func (u *UnavailableOfferings) IsUnavailable(instanceType, zone, capacityType st
 	return found
 }
 
+// InstanceTypes returns the instance types that have at least one offering in the cache
+func (u *UnavailableOfferings) InstanceTypes() sets.Set[string] {
+	instanceTypes := sets.New[string]()
+	for key := range u.cache.Items() {
+		if parts := strings.SplitN(key, ":", 3); len(parts) == 3 {
+			instanceTypes.Insert(parts[1])
+		}
+	}
+	return instanceTypes
+}
+
 // MarkUnavailable communicates recently observed temporary capacity shortages in the provided offerings
 func (u *UnavailableOfferings) MarkUnavailable(ctx context.Context, unavailableReason, instanceType, zone, capacityType string) {
 	// even if the key is already in the cache, we still need to call Set to extend the cached entry's TTL
@@ -114,7 +114,11 @@ func (c *CloudProvider) Create(ctx context.Context, nodeClaim *corev1beta1.NodeC
 	instanceType, _ := lo.Find(instanceTypes, func(i *cloudprovider.InstanceType) bool {
 		return i.Name == instance.Type
 	})
-	nc := c.instanceToNodeClaim(instance, instanceType)
+	zones, err := c.instanceTypeProvider.GetZones(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting zones, %w", err)
+	}
+	nc := c.instanceToNodeClaim(instance, instanceType, zones)
 	nc.Annotations = lo.Assign(nc.Annotations, nodeclassutil.HashAnnotation(nodeClass))
 	return nc, nil
 }
@@ -135,13 +139,17 @@ func (c *CloudProvider) List(ctx context.Context) ([]*corev1beta1.NodeClaim, err
 	if err != nil {
 		return nil, fmt.Errorf("listing instances, %w", err)
 	}
+	zones, err := c.instanceTypeProvider.GetZones(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting zones, %w", err)
+	}
 	var nodeClaims []*corev1beta1.NodeClaim
 	for _, instance := range instances {
 		instanceType, err := c.resolveInstanceTypeFromInstance(ctx, instance)
 		if err != nil {
 			return nil, fmt.Errorf("resolving instance type, %w", err)
 		}
-		nodeClaims = append(nodeClaims, c.instanceToNodeClaim(instance, instanceType))
+		nodeClaims = append(nodeClaims, c.instanceToNodeClaim(instance, instanceType, zones))
 	}
 	return nodeClaims, nil
 }
@@ -160,7 +168,11 @@ func (c *CloudProvider) Get(ctx context.Context, providerID string) (*corev1beta
 	if err != nil {
 		return nil, fmt.Errorf("resolving instance type, %w", err)
 	}
-	return c.instanceToNodeClaim(instance, instanceType), nil
+	zones, err := c.instanceTypeProvider.GetZones(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting zones, %w", err)
+	}
+	return c.instanceToNodeClaim(instance, instanceType, zones), nil
 }
 
 func (c *CloudProvider) LivenessProbe(req *http.Request) error {
@@ -345,7 +357,7 @@ func (c *CloudProvider) resolveNodePoolFromInstance(ctx context.Context, instanc
 	}
 }
 
-func (c *CloudProvider) instanceToNodeClaim(i *instance.Instance, instanceType *cloudprovider.InstanceType) *corev1beta1.NodeClaim {
+func (c *CloudProvider) instanceToNodeClaim(i *instance.Instance, instanceType *cloudprovider.InstanceType, zones map[string]instancetype.Zone) *corev1beta1.NodeClaim {
 	nodeClaim := &corev1beta1.NodeClaim{}
 	labels := map[string]string{}
 	annotations := map[string]string{}
@@ -360,6 +372,10 @@ func (c *CloudProvider) instanceToNodeClaim(i *instance.Instance, instanceType *
 		nodeClaim.Status.Allocatable = functional.FilterMap(instanceType.Allocatable(), func(_ v1.ResourceName, v resource.Quantity) bool { return !resources.IsZero(v) })
 	}
 	labels[v1.LabelTopologyZone] = i.Zone
+	if zone, ok := zones[i.Zone]; ok {
+		labels[v1beta1.LabelTopologyZoneID] = zone.ID
+		labels[v1beta1.LabelTopologyZoneType] = zone.Type
+	}
 	labels[corev1beta1.CapacityTypeLabelKey] = i.CapacityType
 	if v, ok := i.Tags[v1alpha5.ProvisionerNameLabelKey]; ok {
 		labels[v1alpha5.ProvisionerNameLabelKey] = v
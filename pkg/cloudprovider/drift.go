@@ -17,6 +17,7 @@ package cloudprovider
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
@@ -33,6 +34,7 @@ import (
 
 const (
 	AMIDrift           cloudprovider.DriftReason = "AMIDrift"
+	AMIDeprecatedDrift cloudprovider.DriftReason = "AMIDeprecated"
 	SubnetDrift        cloudprovider.DriftReason = "SubnetDrift"
 	SecurityGroupDrift cloudprovider.DriftReason = "SecurityGroupDrift"
 	NodeTemplateDrift  cloudprovider.DriftReason = "NodeTemplateDrift"
@@ -81,18 +83,33 @@ func (c *CloudProvider) isAMIDrifted(ctx context.Context, nodeClaim *corev1beta1
 	if nodeClass.Spec.LaunchTemplateName != nil {
 		return "", nil
 	}
-	amis, err := c.amiProvider.Get(ctx, nodeClass, &amifamily.Options{})
+	amis, err := c.amiProvider.GetWithPending(ctx, nodeClass, &amifamily.Options{})
 	if err != nil {
 		return "", fmt.Errorf("getting amis, %w", err)
 	}
+	// Nodes that were launched with an AMI that's still held back by the soak period, such as when the soak period was
+	// only added after they launched, aren't drifted back to the older AMI
+	now := time.Now()
+	if lo.ContainsBy(amis, func(a amifamily.AMI) bool { return a.AmiID == instance.ImageID && a.IsPending(now) }) {
+		return "", nil
+	}
+	amis = lo.Reject(amis, func(a amifamily.AMI, _ int) bool { return a.IsPending(now) })
 	if len(amis) == 0 {
 		return "", fmt.Errorf("no amis exist given constraints")
 	}
+	// Nodes are only drifted to an AMI that's being rolled out once they fall within the current step of its rollout,
+	// which is also how the AMI of their replacements is chosen
+	amis = amis.ForNodeClaim(nodeClass, nodeClaim.Name)
 	mappedAMIs := amis.MapToInstanceTypes([]*cloudprovider.InstanceType{nodeInstanceType}, nodeClaim.IsMachine)
 	if len(mappedAMIs) == 0 {
 		return "", fmt.Errorf("no instance types satisfy requirements of amis %v", amis)
 	}
 	if !lo.Contains(lo.Keys(mappedAMIs), instance.ImageID) {
+		// Deprecated AMIs are no longer selected unless the selector terms opt in to them, so distinguish an AMI that
+		// was deprecated from one that's no longer selected. Failing to tell them apart shouldn't block drift.
+		if deprecated, err := c.amiProvider.IsDeprecated(ctx, instance.ImageID); err == nil && deprecated {
+			return AMIDeprecatedDrift, nil
+		}
 		return AMIDrift, nil
 	}
 	return "", nil
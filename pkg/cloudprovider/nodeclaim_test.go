@@ -16,6 +16,7 @@ package cloudprovider_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/imdario/mergo"
 	"github.com/samber/lo"
@@ -213,6 +214,115 @@ var _ = Describe("NodeClaim/CloudProvider", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(isDrifted).To(Equal(cloudprovider.AMIDrift))
 		})
+		It("should return drifted if the AMI has been deprecated", func() {
+			deprecatedAMI := fake.ImageID()
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{
+				Images: []*ec2.Image{
+					{
+						Name:         aws.String(coretest.RandomName()),
+						ImageId:      aws.String(validAMI),
+						Architecture: aws.String("arm64"),
+						CreationDate: aws.String("2022-08-15T12:00:00Z"),
+					},
+					{
+						Name:            aws.String(coretest.RandomName()),
+						ImageId:         aws.String(deprecatedAMI),
+						Architecture:    aws.String("arm64"),
+						CreationDate:    aws.String("2021-08-15T12:00:00Z"),
+						DeprecationTime: aws.String("2023-08-15T12:00:00Z"),
+					},
+				},
+			})
+			instance.ImageId = aws.String(deprecatedAMI)
+			isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(isDrifted).To(Equal(cloudprovider.AMIDeprecatedDrift))
+		})
+		Context("AMI Soak Period", func() {
+			var soakingAMI string
+			BeforeEach(func() {
+				soakingAMI = fake.ImageID()
+				awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{
+					Images: []*ec2.Image{
+						{
+							Name:         aws.String(coretest.RandomName()),
+							ImageId:      aws.String(validAMI),
+							Architecture: aws.String("arm64"),
+							CreationDate: aws.String("2022-08-15T12:00:00Z"),
+							Tags:         []*ec2.Tag{{Key: aws.String("foo"), Value: aws.String("bar")}},
+						},
+						{
+							Name:         aws.String(coretest.RandomName()),
+							ImageId:      aws.String(soakingAMI),
+							Architecture: aws.String("arm64"),
+							CreationDate: aws.String(time.Now().Add(-time.Hour).Format(time.RFC3339)),
+							Tags:         []*ec2.Tag{{Key: aws.String("foo"), Value: aws.String("bar")}},
+						},
+					},
+				})
+				nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Tags: map[string]string{"foo": "bar"}}}
+				nodeClass.Spec.AMISoakPeriod = &metav1.Duration{Duration: 24 * time.Hour}
+				ExpectApplied(ctx, env.Client, nodeClass)
+			})
+			It("should not return drifted while a newer AMI is soaking", func() {
+				isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(BeEmpty())
+			})
+			It("should not return drifted if the instance was launched with the AMI that's soaking", func() {
+				instance.ImageId = aws.String(soakingAMI)
+				isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(BeEmpty())
+			})
+			It("should return drifted once the newer AMI has soaked", func() {
+				nodeClass.Spec.AMISoakPeriod = &metav1.Duration{Duration: 30 * time.Minute}
+				ExpectApplied(ctx, env.Client, nodeClass)
+				isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(Equal(cloudprovider.AMIDrift))
+			})
+		})
+		Context("AMI Rollout", func() {
+			var previousAMI string
+			BeforeEach(func() {
+				previousAMI = fake.ImageID()
+				nodeClass.Spec.AMIRollout = &v1beta1.AMIRollout{
+					Steps:        []int32{10, 50},
+					StepDuration: metav1.Duration{Duration: time.Hour},
+				}
+				nodeClass.Status.AMIs = []v1beta1.AMI{
+					{
+						ID: validAMI,
+						Requirements: []v1.NodeSelectorRequirement{
+							{Key: v1.LabelArchStable, Operator: v1.NodeSelectorOpIn, Values: []string{corev1beta1.ArchitectureArm64}},
+						},
+					},
+				}
+				nodeClass.Status.AMIRollouts = []v1beta1.AMIRolloutStatus{
+					{ID: validAMI, PreviousID: previousAMI, StepStartTime: metav1.Now(), Halted: true},
+				}
+				ExpectApplied(ctx, env.Client, nodeClass)
+			})
+			It("should not return drifted if the instance was launched with the previous AMI while the rollout is halted", func() {
+				instance.ImageId = aws.String(previousAMI)
+				isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(BeEmpty())
+			})
+			It("should return drifted if the instance was launched with the AMI while the rollout is halted", func() {
+				isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(Equal(cloudprovider.AMIDrift))
+			})
+			It("should not return drifted if the instance was launched with the AMI once the rollout has completed", func() {
+				nodeClass.Status.AMIRollouts = nil
+				ExpectApplied(ctx, env.Client, nodeClass)
+				isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(BeEmpty())
+			})
+		})
 		It("should return drifted if there are multiple drift reasons", func() {
 			// Instance is a reference to what we return in the GetInstances call
 			instance.ImageId = aws.String(fake.ImageID())
@@ -328,6 +438,7 @@ var _ = Describe("NodeClaim/CloudProvider", func() {
 				Entry("Context Drift", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{Context: aws.String("context-2")}}),
 				Entry("DetailedMonitoring Drift", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{DetailedMonitoring: aws.Bool(true)}}),
 				Entry("AMIFamily Drift", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{AMIFamily: aws.String(v1beta1.AMIFamilyBottlerocket)}}),
+				Entry("CustomAMIFamily Drift", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{CustomAMIFamily: &v1beta1.CustomAMIFamily{EphemeralBlockDevice: aws.String("/dev/xvdb")}}}),
 			)
 			DescribeTable("should not return drifted if dynamic fields are updated",
 				func(changes v1beta1.EC2NodeClass) {
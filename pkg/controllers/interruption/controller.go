@@ -38,6 +38,7 @@ import (
 	interruptionevents "github.com/aws/karpenter/pkg/controllers/interruption/events"
 	"github.com/aws/karpenter/pkg/controllers/interruption/messages"
 	"github.com/aws/karpenter/pkg/controllers/interruption/messages/statechange"
+	"github.com/aws/karpenter/pkg/providers/instance"
 	"github.com/aws/karpenter/pkg/utils"
 
 	"github.com/aws/karpenter-core/pkg/events"
@@ -61,12 +62,13 @@ type Controller struct {
 	recorder                  events.Recorder
 	sqsProvider               *SQSProvider
 	unavailableOfferingsCache *cache.UnavailableOfferings
+	instanceProvider          *instance.Provider
 	parser                    *EventParser
 	cm                        *pretty.ChangeMonitor
 }
 
 func NewController(kubeClient client.Client, clk clock.Clock, recorder events.Recorder,
-	sqsProvider *SQSProvider, unavailableOfferingsCache *cache.UnavailableOfferings) *Controller {
+	sqsProvider *SQSProvider, unavailableOfferingsCache *cache.UnavailableOfferings, instanceProvider *instance.Provider) *Controller {
 
 	return &Controller{
 		kubeClient:                kubeClient,
@@ -74,6 +76,7 @@ func NewController(kubeClient client.Client, clk clock.Clock, recorder events.Re
 		recorder:                  recorder,
 		sqsProvider:               sqsProvider,
 		unavailableOfferingsCache: unavailableOfferingsCache,
+		instanceProvider:          instanceProvider,
 		parser:                    NewEventParser(DefaultParsers...),
 		cm:                        pretty.NewChangeMonitor(),
 	}
@@ -148,6 +151,11 @@ func (c *Controller) handleMessage(ctx context.Context, nodeClaimInstanceIDMap m
 	if msg.Kind() == messages.NoOpKind {
 		return nil
 	}
+	// Keep the instance inventory current, including for instances that no longer have a NodeClaim
+	if msg.Kind() == messages.StateChangeKind {
+		typed := msg.(statechange.Message)
+		c.instanceProvider.UpdateInstanceState(typed.Detail.InstanceID, typed.Detail.State)
+	}
 	for _, instanceID := range msg.EC2InstanceIDs() {
 		nodeClaim, ok := nodeClaimInstanceIDMap[instanceID]
 		if !ok {
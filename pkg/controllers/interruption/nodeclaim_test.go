@@ -16,8 +16,10 @@ package interruption_test
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -32,6 +34,7 @@ import (
 	corev1beta1 "github.com/aws/karpenter-core/pkg/apis/v1beta1"
 	coretest "github.com/aws/karpenter-core/pkg/test"
 	. "github.com/aws/karpenter-core/pkg/test/expectations"
+	"github.com/aws/karpenter/pkg/apis/settings"
 	"github.com/aws/karpenter/pkg/fake"
 	"github.com/aws/karpenter/pkg/utils"
 )
@@ -95,6 +98,30 @@ var _ = Describe("NodeClaim/InterruptionHandling", func() {
 			ExpectNotFound(ctx, env.Client, lo.Map(nodeClaims, func(nc *corev1beta1.NodeClaim, _ int) client.Object { return nc })...)
 			Expect(sqsapi.DeleteMessageBehavior.SuccessfulCalls()).To(Equal(4))
 		})
+		It("should update the instance inventory when receiving a state change message for an instance without a NodeClaim", func() {
+			instanceID := fake.InstanceID()
+			awsEnv.EC2API.Instances.Store(instanceID, &ec2.Instance{
+				State: &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNameRunning)},
+				Tags: []*ec2.Tag{
+					{Key: aws.String(fmt.Sprintf("kubernetes.io/cluster/%s", settings.FromContext(ctx).ClusterName)), Value: aws.String("owned")},
+					{Key: aws.String(corev1beta1.NodePoolLabelKey), Value: aws.String("default")},
+				},
+				Placement:    &ec2.Placement{AvailabilityZone: aws.String(fake.DefaultRegion)},
+				InstanceId:   aws.String(instanceID),
+				InstanceType: aws.String("m5.large"),
+			})
+			instances, err := awsEnv.InstanceProvider.List(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instances).To(HaveLen(1))
+
+			ExpectMessagesCreated(stateChangeMessage(instanceID, "terminated"))
+			ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+			Expect(sqsapi.DeleteMessageBehavior.SuccessfulCalls()).To(Equal(1))
+
+			instances, err = awsEnv.InstanceProvider.List(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instances).To(BeEmpty())
+		})
 		It("should handle multiple messages that cause nodeClaim deletion", func() {
 			var nodeClaims []*corev1beta1.NodeClaim
 			var instanceIDs []string
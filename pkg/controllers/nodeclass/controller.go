@@ -23,6 +23,7 @@ import (
 	"go.uber.org/multierr"
 	"golang.org/x/time/rate"
 	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	controllerruntime "sigs.k8s.io/controller-runtime"
@@ -51,6 +52,10 @@ import (
 	nodeclassutil "github.com/aws/karpenter/pkg/utils/nodeclass"
 )
 
+// AMIDeprecationWarningPeriod is how long before an AMI is deprecated that an event is published for it, so that the
+// AMI selector terms can be updated before the AMI stops being selected
+const AMIDeprecationWarningPeriod = 30 * 24 * time.Hour
+
 type Controller struct {
 	kubeClient              client.Client
 	recorder                events.Recorder
@@ -58,10 +63,11 @@ type Controller struct {
 	securityGroupProvider   *securitygroup.Provider
 	amiProvider             *amifamily.Provider
 	instanceProfileProvider *instanceprofile.Provider
+	clusterCIDR             *string
 }
 
 func NewController(kubeClient client.Client, recorder events.Recorder, subnetProvider *subnet.Provider, securityGroupProvider *securitygroup.Provider,
-	amiProvider *amifamily.Provider, instanceProfileProvider *instanceprofile.Provider) *Controller {
+	amiProvider *amifamily.Provider, instanceProfileProvider *instanceprofile.Provider, clusterCIDR *string) *Controller {
 	return &Controller{
 		kubeClient:              kubeClient,
 		recorder:                recorder,
@@ -69,6 +75,7 @@ func NewController(kubeClient client.Client, recorder events.Recorder, subnetPro
 		securityGroupProvider:   securityGroupProvider,
 		amiProvider:             amiProvider,
 		instanceProfileProvider: instanceProfileProvider,
+		clusterCIDR:             clusterCIDR,
 	}
 }
 
@@ -83,6 +90,7 @@ func (c *Controller) Reconcile(ctx context.Context, nodeClass *v1beta1.EC2NodeCl
 		c.resolveSecurityGroups(ctx, nodeClass),
 		c.resolveAMIs(ctx, nodeClass),
 		c.resolveInstanceProfile(ctx, nodeClass),
+		c.validateClusterCIDR(nodeClass),
 	)
 	if !equality.Semantic.DeepEqual(stored, nodeClass) {
 		statusCopy := nodeClass.DeepCopy()
@@ -141,8 +149,9 @@ func (c *Controller) resolveSubnets(ctx context.Context, nodeClass *v1beta1.EC2N
 	})
 	nodeClass.Status.Subnets = lo.Map(subnets, func(ec2subnet *ec2.Subnet, _ int) v1beta1.Subnet {
 		return v1beta1.Subnet{
-			ID:   *ec2subnet.SubnetId,
-			Zone: *ec2subnet.AvailabilityZone,
+			ID:     *ec2subnet.SubnetId,
+			Zone:   *ec2subnet.AvailabilityZone,
+			ZoneID: lo.FromPtr(ec2subnet.AvailabilityZoneId),
 		}
 	})
 	return nil
@@ -170,7 +179,8 @@ func (c *Controller) resolveSecurityGroups(ctx context.Context, nodeClass *v1bet
 }
 
 func (c *Controller) resolveAMIs(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) error {
-	amis, err := c.amiProvider.Get(ctx, nodeClass, &amifamily.Options{})
+	// AMIs that are held back by the soak period are included so that it's visible when they'll start being used
+	amis, err := c.amiProvider.GetWithPending(ctx, nodeClass, &amifamily.Options{})
 	if err != nil {
 		return err
 	}
@@ -178,6 +188,11 @@ func (c *Controller) resolveAMIs(ctx context.Context, nodeClass *v1beta1.EC2Node
 		nodeClass.Status.AMIs = nil
 		return fmt.Errorf("no amis exist given constraints")
 	}
+	previous := nodeClass.Status.AMIs
+	amiFamily, err := amifamily.GetAMIFamily(nodeClass.Spec.AMIFamily, &amifamily.Options{CustomAMIFamily: nodeClass.Spec.CustomAMIFamily})
+	if err != nil {
+		return err
+	}
 	nodeClass.Status.AMIs = lo.Map(amis, func(ami amifamily.AMI, _ int) v1beta1.AMI {
 		reqs := ami.Requirements.NodeSelectorRequirements()
 		sort.Slice(reqs, func(i, j int) bool {
@@ -187,13 +202,31 @@ func (c *Controller) resolveAMIs(ctx context.Context, nodeClass *v1beta1.EC2Node
 			return reqs[i].Key < reqs[j].Key
 		})
 		return v1beta1.AMI{
-			Name:         ami.Name,
-			ID:           ami.AmiID,
-			Requirements: reqs,
+			Name:            ami.Name,
+			ID:              ami.AmiID,
+			DeprecationTime: deprecationTime(ami),
+			EligibleTime:    lo.Ternary(ami.EligibleTime.IsZero(), nil, &metav1.Time{Time: ami.EligibleTime}),
+			RootDeviceName:  ami.RootDeviceName,
+			// Mismatches aren't fatal since the root volume is retargeted at the root device of the AMI, but they're
+			// surfaced so that the blockDeviceMappings can be corrected
+			BlockDeviceMappingMismatches: ami.BlockDeviceMappingMismatches(amiFamily, nodeClass.Spec.BlockDeviceMappings),
+			Requirements:                 reqs,
 		}
 	})
+	for _, ami := range amis {
+		if t := deprecationTime(ami); t != nil && time.Until(t.Time) < AMIDeprecationWarningPeriod {
+			c.recorder.Publish(AMIDeprecationEvent(nodeClass, ami.AmiID, t.Time))
+		}
+	}
+	return c.reconcileAMIRollouts(ctx, nodeClass, previous)
+}
 
-	return nil
+func deprecationTime(ami amifamily.AMI) *metav1.Time {
+	t, err := time.Parse(time.RFC3339, ami.DeprecationTime)
+	if err != nil {
+		return nil
+	}
+	return &metav1.Time{Time: t}
 }
 
 func (c *Controller) resolveInstanceProfile(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) error {
@@ -208,6 +241,16 @@ func (c *Controller) resolveInstanceProfile(ctx context.Context, nodeClass *v1be
 	return nil
 }
 
+// validateClusterCIDR surfaces that nodes of AMI families that bootstrap with nodeadm can't be launched when the
+// cluster CIDR couldn't be resolved at startup, since nodeadm refuses to join nodes to the cluster without it
+func (c *Controller) validateClusterCIDR(nodeClass *v1beta1.EC2NodeClass) error {
+	if lo.FromPtr(nodeClass.Spec.AMIFamily) != v1beta1.AMIFamilyAL2023 || c.clusterCIDR != nil {
+		return nil
+	}
+	c.recorder.Publish(ClusterCIDRUnresolvedEvent(nodeClass))
+	return fmt.Errorf("cluster cidr is unresolved, set aws.clusterCIDR to launch nodes of the %s ami family", v1beta1.AMIFamilyAL2023)
+}
+
 var _ corecontroller.FinalizingTypedController[*v1beta1.EC2NodeClass] = (*NodeClassController)(nil)
 
 //nolint:revive
@@ -216,9 +259,9 @@ type NodeClassController struct {
 }
 
 func NewNodeClassController(kubeClient client.Client, recorder events.Recorder, subnetProvider *subnet.Provider, securityGroupProvider *securitygroup.Provider,
-	amiProvider *amifamily.Provider, instanceProfileProvider *instanceprofile.Provider) corecontroller.Controller {
+	amiProvider *amifamily.Provider, instanceProfileProvider *instanceprofile.Provider, clusterCIDR *string) corecontroller.Controller {
 	return corecontroller.Typed[*v1beta1.EC2NodeClass](kubeClient, &NodeClassController{
-		Controller: NewController(kubeClient, recorder, subnetProvider, securityGroupProvider, amiProvider, instanceProfileProvider),
+		Controller: NewController(kubeClient, recorder, subnetProvider, securityGroupProvider, amiProvider, instanceProfileProvider, clusterCIDR),
 	})
 }
 
@@ -262,9 +305,9 @@ type NodeTemplateController struct {
 }
 
 func NewNodeTemplateController(kubeClient client.Client, recorder events.Recorder, subnetProvider *subnet.Provider, securityGroupProvider *securitygroup.Provider,
-	amiProvider *amifamily.Provider, instanceProfileProvider *instanceprofile.Provider) corecontroller.Controller {
+	amiProvider *amifamily.Provider, instanceProfileProvider *instanceprofile.Provider, clusterCIDR *string) corecontroller.Controller {
 	return corecontroller.Typed[*v1alpha1.AWSNodeTemplate](kubeClient, &NodeTemplateController{
-		Controller: NewController(kubeClient, recorder, subnetProvider, securityGroupProvider, amiProvider, instanceProfileProvider),
+		Controller: NewController(kubeClient, recorder, subnetProvider, securityGroupProvider, amiProvider, instanceProfileProvider, clusterCIDR),
 	})
 }
 
@@ -16,6 +16,7 @@ package nodeclass
 
 import (
 	"fmt"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 
@@ -33,3 +34,52 @@ func WaitingOnNodeClaimTerminationEvent(nodeClass *v1beta1.EC2NodeClass, names [
 		DedupeValues:   []string{string(nodeClass.UID)},
 	}
 }
+
+func ClusterCIDRUnresolvedEvent(nodeClass *v1beta1.EC2NodeClass) events.Event {
+	return events.Event{
+		InvolvedObject: nodeClass,
+		Type:           v1.EventTypeWarning,
+		Reason:         "ClusterCIDRUnresolved",
+		Message:        "Cluster CIDR couldn't be resolved, nodes can't be bootstrapped with nodeadm until aws.clusterCIDR is set",
+		DedupeValues:   []string{string(nodeClass.UID)},
+	}
+}
+
+func AMIDeprecationEvent(nodeClass *v1beta1.EC2NodeClass, amiID string, deprecationTime time.Time) events.Event {
+	if deprecationTime.Before(time.Now()) {
+		return events.Event{
+			InvolvedObject: nodeClass,
+			Type:           v1.EventTypeWarning,
+			Reason:         "AMIDeprecated",
+			Message:        fmt.Sprintf("AMI %s was deprecated at %s", amiID, deprecationTime.Format(time.RFC3339)),
+			DedupeValues:   []string{string(nodeClass.UID), amiID},
+		}
+	}
+	return events.Event{
+		InvolvedObject: nodeClass,
+		Type:           v1.EventTypeWarning,
+		Reason:         "AMIDeprecationUpcoming",
+		Message:        fmt.Sprintf("AMI %s will be deprecated at %s", amiID, deprecationTime.Format(time.RFC3339)),
+		DedupeValues:   []string{string(nodeClass.UID), amiID},
+	}
+}
+
+func AMIRolloutHaltedEvent(nodeClass *v1beta1.EC2NodeClass, amiID string) events.Event {
+	return events.Event{
+		InvolvedObject: nodeClass,
+		Type:           v1.EventTypeWarning,
+		Reason:         "AMIRolloutHalted",
+		Message:        fmt.Sprintf("Halted rollout of AMI %s, nodes launched with it failed to register or aren't ready", amiID),
+		DedupeValues:   []string{string(nodeClass.UID), amiID},
+	}
+}
+
+func AMIRolloutCompletedEvent(nodeClass *v1beta1.EC2NodeClass, amiID string) events.Event {
+	return events.Event{
+		InvolvedObject: nodeClass,
+		Type:           v1.EventTypeNormal,
+		Reason:         "AMIRolloutCompleted",
+		Message:        fmt.Sprintf("Completed rollout of AMI %s", amiID),
+		DedupeValues:   []string{string(nodeClass.UID), amiID},
+	}
+}
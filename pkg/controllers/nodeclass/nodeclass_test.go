@@ -26,13 +26,19 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	_ "knative.dev/pkg/system/testing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	corev1beta1 "github.com/aws/karpenter-core/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-core/pkg/events"
+	corecontroller "github.com/aws/karpenter-core/pkg/operator/controller"
 	coretest "github.com/aws/karpenter-core/pkg/test"
 	. "github.com/aws/karpenter-core/pkg/test/expectations"
 	"github.com/aws/karpenter/pkg/apis/v1beta1"
+	"github.com/aws/karpenter/pkg/controllers/nodeclass"
 	"github.com/aws/karpenter/pkg/fake"
 	"github.com/aws/karpenter/pkg/providers/instanceprofile"
 	"github.com/aws/karpenter/pkg/test"
@@ -105,6 +111,27 @@ var _ = Describe("NodeClassController", func() {
 				},
 			}))
 		})
+		It("Should include the zone ID of the Subnets", func() {
+			awsEnv.EC2API.DescribeSubnetsOutput.Set(&ec2.DescribeSubnetsOutput{Subnets: []*ec2.Subnet{
+				{SubnetId: aws.String("subnet-test1"), AvailabilityZone: aws.String("test-zone-1a"), AvailabilityZoneId: aws.String("testzone1a"), AvailableIpAddressCount: aws.Int64(100)},
+				{SubnetId: aws.String("subnet-test2"), AvailabilityZone: aws.String("test-zone-1-lz1a"), AvailabilityZoneId: aws.String("testzone1-lz1a"), AvailableIpAddressCount: aws.Int64(50)},
+			}})
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.Subnets).To(Equal([]v1beta1.Subnet{
+				{
+					ID:     "subnet-test1",
+					Zone:   "test-zone-1a",
+					ZoneID: "testzone1a",
+				},
+				{
+					ID:     "subnet-test2",
+					Zone:   "test-zone-1-lz1a",
+					ZoneID: "testzone1-lz1a",
+				},
+			}))
+		})
 		It("Should resolve a valid selectors for Subnet by tags", func() {
 			nodeClass.Spec.SubnetSelectorTerms = []v1beta1.SubnetSelectorTerm{
 				{
@@ -691,6 +718,358 @@ var _ = Describe("NodeClassController", func() {
 				},
 			))
 		})
+		It("should resolve the deprecation time of AMIs into status", func() {
+			deprecationTime := time.Now().Add(time.Hour).Truncate(time.Second)
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{
+				Images: []*ec2.Image{
+					{
+						Name:            aws.String("test-ami-1"),
+						ImageId:         aws.String("ami-test1"),
+						CreationDate:    aws.String(time.Now().Format(time.RFC3339)),
+						DeprecationTime: aws.String(deprecationTime.Format(time.RFC3339)),
+						Architecture:    aws.String("x86_64"),
+						Tags: []*ec2.Tag{
+							{Key: aws.String("Name"), Value: aws.String("test-ami-1")},
+						},
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIs).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIs[0].DeprecationTime).ToNot(BeNil())
+			Expect(nodeClass.Status.AMIs[0].DeprecationTime.Time.Equal(deprecationTime)).To(BeTrue())
+		})
+		It("should resolve AMIs that are still soaking into status with the time they become eligible", func() {
+			creationTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{
+				Images: []*ec2.Image{
+					{
+						Name:         aws.String("test-ami-1"),
+						ImageId:      aws.String("ami-test1"),
+						CreationDate: aws.String(time.Now().Add(-72 * time.Hour).Format(time.RFC3339)),
+						Architecture: aws.String("x86_64"),
+						Tags:         []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-ami-1")}},
+					},
+					{
+						Name:         aws.String("test-ami-2"),
+						ImageId:      aws.String("ami-test2"),
+						CreationDate: aws.String(creationTime.Format(time.RFC3339)),
+						Architecture: aws.String("x86_64"),
+						Tags:         []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-ami-2")}},
+					},
+				},
+			})
+			nodeClass.Spec.AMISoakPeriod = &metav1.Duration{Duration: 24 * time.Hour}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIs).To(HaveLen(2))
+			Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-test2"))
+			Expect(nodeClass.Status.AMIs[0].EligibleTime).ToNot(BeNil())
+			Expect(nodeClass.Status.AMIs[0].EligibleTime.Time.Equal(creationTime.Add(24 * time.Hour))).To(BeTrue())
+			Expect(nodeClass.Status.AMIs[1].ID).To(Equal("ami-test1"))
+			Expect(nodeClass.Status.AMIs[1].EligibleTime).To(BeNil())
+		})
+		It("should resolve the root device of AMIs into status", func() {
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{
+				Images: []*ec2.Image{
+					{
+						Name:           aws.String("test-ami-1"),
+						ImageId:        aws.String("ami-test1"),
+						CreationDate:   aws.String(time.Now().Format(time.RFC3339)),
+						Architecture:   aws.String("x86_64"),
+						RootDeviceName: aws.String("/dev/xvda"),
+						Tags:           []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-ami-1")}},
+					},
+				},
+			})
+			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIs).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIs[0].RootDeviceName).To(Equal("/dev/xvda"))
+			Expect(nodeClass.Status.AMIs[0].BlockDeviceMappingMismatches).To(BeEmpty())
+		})
+		It("should resolve block device mappings that don't match the root device of AMIs into status", func() {
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{
+				Images: []*ec2.Image{
+					{
+						Name:           aws.String("test-ami-1"),
+						ImageId:        aws.String("ami-test1"),
+						CreationDate:   aws.String(time.Now().Format(time.RFC3339)),
+						Architecture:   aws.String("x86_64"),
+						RootDeviceName: aws.String("/dev/sda1"),
+						BlockDeviceMappings: []*ec2.BlockDeviceMapping{
+							{DeviceName: aws.String("/dev/sda1"), Ebs: &ec2.EbsBlockDevice{VolumeSize: aws.Int64(50)}},
+						},
+						Tags: []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-ami-1")}},
+					},
+				},
+			})
+			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
+			nodeClass.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{
+				{
+					DeviceName: aws.String("/dev/xvda"),
+					EBS:        &v1beta1.BlockDevice{VolumeSize: lo.ToPtr(resource.MustParse("20Gi"))},
+					RootVolume: true,
+				},
+			}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIs).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIs[0].RootDeviceName).To(Equal("/dev/sda1"))
+			Expect(nodeClass.Status.AMIs[0].BlockDeviceMappingMismatches).To(ConsistOf(
+				"root volume /dev/xvda is launched as the root device /dev/sda1 of the AMI",
+				"root device /dev/sda1 is smaller than the 50Gi snapshot of the AMI",
+			))
+		})
+		It("should resolve block device mappings that launch an additional volume into status", func() {
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{
+				Images: []*ec2.Image{
+					{
+						Name:           aws.String("test-ami-1"),
+						ImageId:        aws.String("ami-test1"),
+						CreationDate:   aws.String(time.Now().Format(time.RFC3339)),
+						Architecture:   aws.String("x86_64"),
+						RootDeviceName: aws.String("/dev/sda1"),
+						Tags:           []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-ami-1")}},
+					},
+				},
+			})
+			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
+			nodeClass.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{
+				{
+					DeviceName: aws.String("/dev/xvda"),
+					EBS:        &v1beta1.BlockDevice{VolumeSize: lo.ToPtr(resource.MustParse("20Gi"))},
+				},
+			}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIs).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIs[0].BlockDeviceMappingMismatches).To(ConsistOf(
+				"/dev/xvda isn't the root device /dev/sda1 of the AMI and is launched as an additional volume",
+			))
+		})
+	})
+	Context("AMI Rollout", func() {
+		var amd64Requirements []v1.NodeSelectorRequirement
+		var image1, image2 *ec2.Image
+		BeforeEach(func() {
+			amd64Requirements = []v1.NodeSelectorRequirement{{Key: v1.LabelArchStable, Operator: v1.NodeSelectorOpIn, Values: []string{corev1beta1.ArchitectureAmd64}}}
+			image1 = &ec2.Image{
+				Name:           aws.String("test-ami-1"),
+				ImageId:        aws.String("ami-test1"),
+				CreationDate:   aws.String(time.Now().Add(-time.Hour).Format(time.RFC3339)),
+				Architecture:   aws.String("x86_64"),
+				RootDeviceName: aws.String("/dev/sda1"),
+				Tags:           []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-ami-1")}},
+			}
+			image2 = &ec2.Image{
+				Name:         aws.String("test-ami-2"),
+				ImageId:      aws.String("ami-test2"),
+				CreationDate: aws.String(time.Now().Format(time.RFC3339)),
+				Architecture: aws.String("x86_64"),
+				Tags:         []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-ami-2")}},
+			}
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{image1, image2}})
+			nodeClass.Spec.AMIRollout = &v1beta1.AMIRollout{
+				Steps:        []int32{10, 50},
+				StepDuration: metav1.Duration{Duration: time.Hour},
+			}
+		})
+		It("should start a rollout when a newly resolved AMI replaces an AMI", func() {
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{image1}})
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIs).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIRollouts).To(BeEmpty())
+
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{image1, image2}})
+			awsEnv.EC2Cache.Flush()
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIs).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-test2"))
+			Expect(nodeClass.Status.AMIRollouts).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIRollouts[0].ID).To(Equal("ami-test2"))
+			Expect(nodeClass.Status.AMIRollouts[0].PreviousID).To(Equal("ami-test1"))
+			Expect(nodeClass.Status.AMIRollouts[0].PreviousRootDeviceName).To(Equal("/dev/sda1"))
+			Expect(nodeClass.Status.AMIRollouts[0].Step).To(BeNumerically("==", 0))
+			Expect(nodeClass.Status.AMIRollouts[0].Halted).To(BeFalse())
+		})
+		It("should not start a rollout without a previously resolved AMI", func() {
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIs).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIRollouts).To(BeEmpty())
+		})
+		It("should move on to the next step once the step duration has passed", func() {
+			nodeClass.Status.AMIs = []v1beta1.AMI{{ID: "ami-test2", Name: "test-ami-2", Requirements: amd64Requirements}}
+			nodeClass.Status.AMIRollouts = []v1beta1.AMIRolloutStatus{
+				{ID: "ami-test2", PreviousID: "ami-test1", Step: 0, StepStartTime: metav1.NewTime(time.Now().Add(-2 * time.Hour))},
+			}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIRollouts).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIRollouts[0].Step).To(BeNumerically("==", 1))
+			Expect(time.Since(nodeClass.Status.AMIRollouts[0].StepStartTime.Time)).To(BeNumerically("<", time.Minute))
+		})
+		It("should not move on to the next step before the step duration has passed", func() {
+			nodeClass.Status.AMIs = []v1beta1.AMI{{ID: "ami-test2", Name: "test-ami-2", Requirements: amd64Requirements}}
+			nodeClass.Status.AMIRollouts = []v1beta1.AMIRolloutStatus{
+				{ID: "ami-test2", PreviousID: "ami-test1", Step: 0, StepStartTime: metav1.NewTime(time.Now().Add(-time.Minute))},
+			}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIRollouts).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIRollouts[0].Step).To(BeNumerically("==", 0))
+		})
+		It("should complete the rollout after the last step", func() {
+			nodeClass.Status.AMIs = []v1beta1.AMI{{ID: "ami-test2", Name: "test-ami-2", Requirements: amd64Requirements}}
+			nodeClass.Status.AMIRollouts = []v1beta1.AMIRolloutStatus{
+				{ID: "ami-test2", PreviousID: "ami-test1", Step: 1, StepStartTime: metav1.NewTime(time.Now().Add(-2 * time.Hour))},
+			}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIRollouts).To(BeEmpty())
+		})
+		It("should halt the rollout when nodes launched with the AMI aren't ready", func() {
+			nodeClass.Status.AMIs = []v1beta1.AMI{{ID: "ami-test2", Name: "test-ami-2", Requirements: amd64Requirements}}
+			nodeClass.Status.AMIRollouts = []v1beta1.AMIRolloutStatus{
+				{ID: "ami-test2", PreviousID: "ami-test1", Step: 0, StepStartTime: metav1.NewTime(time.Now().Add(-2 * time.Hour))},
+			}
+			node := coretest.Node()
+			node.Status.Conditions = []v1.NodeCondition{
+				{Type: v1.NodeReady, Status: v1.ConditionFalse, LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour))},
+			}
+			nodeClaim := coretest.NodeClaim(corev1beta1.NodeClaim{
+				Spec: corev1beta1.NodeClaimSpec{
+					NodeClassRef: &corev1beta1.NodeClassReference{Name: nodeClass.Name},
+				},
+				Status: corev1beta1.NodeClaimStatus{
+					NodeName: node.Name,
+					ImageID:  "ami-test2",
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim, node)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIRollouts).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIRollouts[0].Halted).To(BeTrue())
+			Expect(nodeClass.Status.AMIRollouts[0].Step).To(BeNumerically("==", 0))
+		})
+		It("should keep a rollout halted after the nodes launched with the AMI are drifted back", func() {
+			nodeClass.Status.AMIs = []v1beta1.AMI{{ID: "ami-test2", Name: "test-ami-2", Requirements: amd64Requirements}}
+			nodeClass.Status.AMIRollouts = []v1beta1.AMIRolloutStatus{
+				{ID: "ami-test2", PreviousID: "ami-test1", Step: 0, StepStartTime: metav1.NewTime(time.Now().Add(-2 * time.Hour))},
+			}
+			node := coretest.Node()
+			node.Status.Conditions = []v1.NodeCondition{
+				{Type: v1.NodeReady, Status: v1.ConditionFalse, LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour))},
+			}
+			nodeClaim := coretest.NodeClaim(corev1beta1.NodeClaim{
+				Spec: corev1beta1.NodeClaimSpec{
+					NodeClassRef: &corev1beta1.NodeClassReference{Name: nodeClass.Name},
+				},
+				Status: corev1beta1.NodeClaimStatus{
+					NodeName: node.Name,
+					ImageID:  "ami-test2",
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim, node)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIRollouts).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIRollouts[0].Halted).To(BeTrue())
+
+			// The unhealthy node is drifted back to the previous AMI, so nothing is left that uses the halted AMI
+			ExpectDeleted(ctx, env.Client, nodeClaim, node)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIRollouts).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIRollouts[0].Halted).To(BeTrue())
+			Expect(nodeClass.Status.AMIRollouts[0].Step).To(BeNumerically("==", 0))
+		})
+		It("should resume a halted rollout when the resume annotation is set to the AMI", func() {
+			nodeClass.Annotations = map[string]string{v1beta1.AnnotationAMIRolloutResume: "ami-test2"}
+			nodeClass.Status.AMIs = []v1beta1.AMI{{ID: "ami-test2", Name: "test-ami-2", Requirements: amd64Requirements}}
+			nodeClass.Status.AMIRollouts = []v1beta1.AMIRolloutStatus{
+				{ID: "ami-test2", PreviousID: "ami-test1", Step: 0, StepStartTime: metav1.NewTime(time.Now().Add(-2 * time.Hour)), Halted: true},
+			}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIRollouts).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIRollouts[0].Halted).To(BeFalse())
+			Expect(nodeClass.Status.AMIRollouts[0].Step).To(BeNumerically("==", 0))
+			Expect(time.Since(nodeClass.Status.AMIRollouts[0].StepStartTime.Time)).To(BeNumerically("<", time.Minute))
+			Expect(nodeClass.Annotations).ToNot(HaveKey(v1beta1.AnnotationAMIRolloutResume))
+		})
+		It("should not resume a halted rollout when the resume annotation is set to another AMI", func() {
+			nodeClass.Annotations = map[string]string{v1beta1.AnnotationAMIRolloutResume: "ami-test3"}
+			nodeClass.Status.AMIs = []v1beta1.AMI{{ID: "ami-test2", Name: "test-ami-2", Requirements: amd64Requirements}}
+			nodeClass.Status.AMIRollouts = []v1beta1.AMIRolloutStatus{
+				{ID: "ami-test2", PreviousID: "ami-test1", Step: 0, StepStartTime: metav1.NewTime(time.Now().Add(-2 * time.Hour)), Halted: true},
+			}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIRollouts).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIRollouts[0].Halted).To(BeTrue())
+			Expect(nodeClass.Annotations).ToNot(HaveKey(v1beta1.AnnotationAMIRolloutResume))
+		})
+		It("should replace a halted rollout when a newer AMI is resolved", func() {
+			image3 := &ec2.Image{
+				Name:         aws.String("test-ami-3"),
+				ImageId:      aws.String("ami-test3"),
+				CreationDate: aws.String(time.Now().Add(time.Minute).Format(time.RFC3339)),
+				Architecture: aws.String("x86_64"),
+				Tags:         []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-ami-3")}},
+			}
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{image1, image2, image3}})
+			nodeClass.Status.AMIs = []v1beta1.AMI{{ID: "ami-test2", Name: "test-ami-2", Requirements: amd64Requirements}}
+			nodeClass.Status.AMIRollouts = []v1beta1.AMIRolloutStatus{
+				{ID: "ami-test2", PreviousID: "ami-test1", Step: 1, StepStartTime: metav1.NewTime(time.Now().Add(-2 * time.Hour)), Halted: true},
+			}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectReconcileSucceeded(ctx, nodeClassController, client.ObjectKeyFromObject(nodeClass))
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIRollouts).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIRollouts[0].ID).To(Equal("ami-test3"))
+			Expect(nodeClass.Status.AMIRollouts[0].PreviousID).To(Equal("ami-test1"))
+			Expect(nodeClass.Status.AMIRollouts[0].Step).To(BeNumerically("==", 0))
+			Expect(nodeClass.Status.AMIRollouts[0].Halted).To(BeFalse())
+		})
+	})
+	Context("Cluster CIDR", func() {
+		var recorder *record.FakeRecorder
+		var controller corecontroller.Controller
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(10)
+			controller = nodeclass.NewNodeClassController(env.Client, events.NewRecorder(recorder), awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, nil)
+		})
+		It("should fail to reconcile AL2023 node classes when the cluster cidr is unresolved", func() {
+			nodeClass.Spec.AMIFamily = lo.ToPtr(v1beta1.AMIFamilyAL2023)
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectReconcileFailed(ctx, controller, client.ObjectKeyFromObject(nodeClass))
+			Expect(recorder.Events).To(Receive(ContainSubstring("ClusterCIDRUnresolved")))
+		})
+		It("should reconcile node classes of other AMI families when the cluster cidr is unresolved", func() {
+			nodeClass.Spec.AMIFamily = lo.ToPtr(v1beta1.AMIFamilyAL2)
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectReconcileSucceeded(ctx, controller, client.ObjectKeyFromObject(nodeClass))
+			Expect(recorder.Events).ToNot(Receive())
+		})
 	})
 	Context("Static Drift Hash", func() {
 		DescribeTable("should update the static drift hash when static field is updated", func(changes *v1beta1.EC2NodeClass) {
@@ -719,6 +1098,7 @@ var _ = Describe("NodeClassController", func() {
 			Entry("DetailedMonitoring Drift", &v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{DetailedMonitoring: aws.Bool(true)}}),
 			Entry("MetadataOptions Drift", &v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{MetadataOptions: &v1beta1.MetadataOptions{HTTPEndpoint: aws.String("disabled")}}}),
 			Entry("Context Drift", &v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{Context: aws.String("context-2")}}),
+			Entry("CustomAMIFamily Drift", &v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{CustomAMIFamily: &v1beta1.CustomAMIFamily{EphemeralBlockDevice: aws.String("/dev/xvdb")}}}),
 		)
 		It("should not update the static drift hash when dynamic field is updated", func() {
 			ExpectApplied(ctx, env.Client, nodeClass)
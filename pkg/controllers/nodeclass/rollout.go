@@ -0,0 +1,153 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeclass
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	"go.uber.org/multierr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/logging"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1beta1 "github.com/aws/karpenter-core/pkg/apis/v1beta1"
+	"github.com/aws/karpenter/pkg/apis/v1beta1"
+)
+
+// AMIRolloutHealthTimeout is how long a node that was launched with an AMI that's being rolled out has to register and
+// become ready before the rollout is halted
+const AMIRolloutHealthTimeout = 15 * time.Minute
+
+// reconcileAMIRollouts starts a rollout for each newly resolved AMI that replaces a previously resolved AMI with the
+// same requirements, and moves the rollouts that are in progress on to their next step while the nodes that were
+// launched with the new AMI are healthy
+func (c *Controller) reconcileAMIRollouts(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, previous []v1beta1.AMI) error {
+	if nodeClass.Spec.AMIRollout == nil {
+		nodeClass.Status.AMIRollouts = nil
+		return nil
+	}
+	var rollouts []v1beta1.AMIRolloutStatus
+	for _, ami := range nodeClass.Status.AMIs {
+		// AMIs that are still held back by the soak period aren't rolled out yet
+		if ami.EligibleTime != nil {
+			continue
+		}
+		if rollout, ok := lo.Find(nodeClass.Status.AMIRollouts, func(r v1beta1.AMIRolloutStatus) bool { return r.ID == ami.ID }); ok {
+			rollouts = append(rollouts, rollout)
+			continue
+		}
+		if lo.ContainsBy(previous, func(p v1beta1.AMI) bool { return p.ID == ami.ID }) {
+			continue
+		}
+		replaced, ok := lo.Find(previous, func(p v1beta1.AMI) bool {
+			return p.EligibleTime == nil && equality.Semantic.DeepEqual(p.Requirements, ami.Requirements)
+		})
+		if !ok {
+			continue
+		}
+		previousID, previousRootDeviceName := replaced.ID, replaced.RootDeviceName
+		// If the replaced AMI was still being rolled out, the new AMI replaces the AMI that it was replacing instead
+		if rollout, ok := lo.Find(nodeClass.Status.AMIRollouts, func(r v1beta1.AMIRolloutStatus) bool { return r.ID == replaced.ID }); ok {
+			previousID, previousRootDeviceName = rollout.PreviousID, rollout.PreviousRootDeviceName
+		}
+		logging.FromContext(ctx).With("ami", ami.ID, "previous-ami", previousID).Infof("starting ami rollout")
+		rollouts = append(rollouts, v1beta1.AMIRolloutStatus{
+			ID:                     ami.ID,
+			PreviousID:             previousID,
+			PreviousRootDeviceName: previousRootDeviceName,
+			StepStartTime:          metav1.Now(),
+		})
+	}
+
+	var errs error
+	now := time.Now()
+	nodeClass.Status.AMIRollouts = nil
+	for _, rollout := range rollouts {
+		// Halted rollouts stay halted until they're replaced by a newer AMI or they're resumed through the annotation,
+		// since the nodes that were launched with the AMI are drifted back and can't tell whether it's healthy again
+		if rollout.Halted && nodeClass.Annotations[v1beta1.AnnotationAMIRolloutResume] == rollout.ID {
+			logging.FromContext(ctx).With("ami", rollout.ID).Infof("resuming ami rollout")
+			rollout.Halted = false
+			rollout.StepStartTime = metav1.NewTime(now)
+		}
+		if rollout.Halted {
+			nodeClass.Status.AMIRollouts = append(nodeClass.Status.AMIRollouts, rollout)
+			continue
+		}
+		healthy, err := c.isAMIHealthy(ctx, nodeClass, rollout.ID)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("checking health of ami %s, %w", rollout.ID, err))
+			nodeClass.Status.AMIRollouts = append(nodeClass.Status.AMIRollouts, rollout)
+			continue
+		}
+		switch {
+		case !healthy:
+			c.recorder.Publish(AMIRolloutHaltedEvent(nodeClass, rollout.ID))
+			rollout.Halted = true
+		case now.Sub(rollout.StepStartTime.Time) >= nodeClass.Spec.AMIRollout.StepDuration.Duration:
+			rollout.Step++
+			rollout.StepStartTime = metav1.NewTime(now)
+		}
+		if int(rollout.Step) >= len(nodeClass.Spec.AMIRollout.Steps) {
+			c.recorder.Publish(AMIRolloutCompletedEvent(nodeClass, rollout.ID))
+			continue
+		}
+		nodeClass.Status.AMIRollouts = append(nodeClass.Status.AMIRollouts, rollout)
+	}
+	// The resume annotation only applies to the rollouts that are halted when it's set
+	delete(nodeClass.Annotations, v1beta1.AnnotationAMIRolloutResume)
+	return errs
+}
+
+// isAMIHealthy returns whether the nodes that were launched with the AMI registered and became ready in time
+func (c *Controller) isAMIHealthy(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, amiID string) (bool, error) {
+	nodeClaimList := &corev1beta1.NodeClaimList{}
+	if err := c.kubeClient.List(ctx, nodeClaimList, client.MatchingFields{"spec.nodeClass.name": nodeClass.Name}); err != nil {
+		return false, fmt.Errorf("listing nodeclaims that are using nodeclass, %w", err)
+	}
+	for i := range nodeClaimList.Items {
+		nodeClaim := &nodeClaimList.Items[i]
+		if nodeClaim.Status.ImageID != amiID || !nodeClaim.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if nodeClaim.Status.NodeName == "" {
+			if time.Since(nodeClaim.CreationTimestamp.Time) > AMIRolloutHealthTimeout {
+				return false, nil
+			}
+			continue
+		}
+		node := &v1.Node{}
+		if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: nodeClaim.Status.NodeName}, node); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return false, fmt.Errorf("getting node, %w", err)
+		}
+		// Nodes aren't ready right after registering, so they're only unhealthy once they haven't been ready for a while
+		ready, ok := lo.Find(node.Status.Conditions, func(c v1.NodeCondition) bool { return c.Type == v1.NodeReady })
+		notReadySince := lo.Ternary(ok, ready.LastTransitionTime.Time, node.CreationTimestamp.Time)
+		if (!ok || ready.Status != v1.ConditionTrue) && time.Since(notReadySince) > AMIRolloutHealthTimeout {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,170 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overhead
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/logging"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1beta1 "github.com/aws/karpenter-core/pkg/apis/v1beta1"
+	corecontroller "github.com/aws/karpenter-core/pkg/operator/controller"
+	"github.com/aws/karpenter/pkg/apis/v1beta1"
+	"github.com/aws/karpenter/pkg/providers/instancetype"
+)
+
+// Controller learns the VM memory overhead of instance types per AMI family from the memory capacity that registered
+// nodes report, and persists the learned overheads so that they survive restarts
+type Controller struct {
+	kubeClient           client.Client
+	instanceTypeProvider *instancetype.Provider
+	overheads            *instancetype.OverheadStore
+}
+
+func NewController(kubeClient client.Client, instanceTypeProvider *instancetype.Provider, overheads *instancetype.OverheadStore) *Controller {
+	return &Controller{
+		kubeClient:           kubeClient,
+		instanceTypeProvider: instanceTypeProvider,
+		overheads:            overheads,
+	}
+}
+
+func (c *Controller) Name() string {
+	return "instancetype.overhead"
+}
+
+func (c *Controller) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	nodeList := &v1.NodeList{}
+	if err := c.kubeClient.List(ctx, nodeList, client.HasLabels{corev1beta1.NodePoolLabelKey, v1.LabelInstanceTypeStable}); err != nil {
+		return reconcile.Result{}, fmt.Errorf("listing nodes, %w", err)
+	}
+	nodes := lo.Filter(nodeList.Items, func(n v1.Node, _ int) bool {
+		return !n.Status.Capacity.Memory().IsZero()
+	})
+	if len(nodes) == 0 {
+		return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+	}
+	instanceTypes, err := c.instanceTypeProvider.GetInstanceTypes(ctx)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("getting instance types, %w", err)
+	}
+	infos := lo.SliceToMap(instanceTypes, func(i *ec2.InstanceTypeInfo) (string, *ec2.InstanceTypeInfo) {
+		return aws.StringValue(i.InstanceType), i
+	})
+	// Observe nodes from oldest to newest so that every node that is newer than the last observation is counted
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].CreationTimestamp.Before(&nodes[j].CreationTimestamp)
+	})
+	amiFamilies := map[string]string{}
+	observed := false
+	for i := range nodes {
+		info, ok := infos[nodes[i].Labels[v1.LabelInstanceTypeStable]]
+		if !ok {
+			continue
+		}
+		nodePoolName := nodes[i].Labels[corev1beta1.NodePoolLabelKey]
+		if _, ok := amiFamilies[nodePoolName]; !ok {
+			amiFamily, err := c.resolveAMIFamily(ctx, nodePoolName)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			amiFamilies[nodePoolName] = amiFamily
+		}
+		if amiFamilies[nodePoolName] == "" {
+			continue
+		}
+		observed = c.observe(ctx, &nodes[i], info, amiFamilies[nodePoolName]) || observed
+	}
+	if observed {
+		if err := c.persist(ctx); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+}
+
+func (c *Controller) Builder(_ context.Context, m manager.Manager) corecontroller.Builder {
+	return corecontroller.NewSingletonManagedBy(m)
+}
+
+// resolveAMIFamily returns the AMI family of the EC2NodeClass that the NodePool references, or an empty string if the
+// NodePool or EC2NodeClass no longer exists
+func (c *Controller) resolveAMIFamily(ctx context.Context, nodePoolName string) (string, error) {
+	nodePool := &corev1beta1.NodePool{}
+	if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: nodePoolName}, nodePool); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("getting nodepool, %w", err)
+	}
+	if nodePool.Spec.Template.Spec.NodeClassRef == nil {
+		return "", nil
+	}
+	nodeClass := &v1beta1.EC2NodeClass{}
+	if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: nodePool.Spec.Template.Spec.NodeClassRef.Name}, nodeClass); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("getting ec2nodeclass, %w", err)
+	}
+	return instancetype.AMIFamilyName(nodeClass), nil
+}
+
+// observe records the prediction error for the node's memory capacity and adds the node's VM memory overhead to the
+// learned overheads. It returns true if the learned overheads changed.
+func (c *Controller) observe(ctx context.Context, node *v1.Node, info *ec2.InstanceTypeInfo, amiFamily string) bool {
+	// Nodes that were already observed are skipped so that the prediction error reflects the newest node
+	if overhead, ok := c.overheads.Get(aws.StringValue(info.InstanceType), amiFamily); ok && overhead.Includes(node.Name, node.CreationTimestamp.Time) {
+		return false
+	}
+	actual := node.Status.Capacity.Memory()
+	predicted := instancetype.MemoryCapacity(ctx, info, amiFamily, c.overheads)
+	instancetype.MemoryCapacityPredictionError.With(prometheus.Labels{
+		instancetype.InstanceTypeLabel: aws.StringValue(info.InstanceType),
+		instancetype.AMIFamilyLabel:    amiFamily,
+	}).Set(float64(predicted.Value() - actual.Value()))
+
+	overheadMiB := lo.Max([]int64{0, instancetype.VMMemoryMiB(info) - actual.Value()/1024/1024})
+	if !c.overheads.Observe(aws.StringValue(info.InstanceType), amiFamily, node.Name, overheadMiB, node.CreationTimestamp.Time) {
+		return false
+	}
+	logging.FromContext(ctx).With("instance-type", aws.StringValue(info.InstanceType), "ami-family", amiFamily, "node", node.Name, "overhead-mib", overheadMiB).Debugf("observed instance type memory overhead")
+	return true
+}
+
+// persist stores the learned overheads so that they can be hydrated by the next Karpenter process to start
+func (c *Controller) persist(ctx context.Context) error {
+	cm, err := c.overheads.ConfigMap()
+	if err != nil {
+		return err
+	}
+	if err := c.kubeClient.Patch(ctx, cm, client.Apply, client.ForceOwnership, client.FieldOwner(c.Name())); err != nil {
+		return fmt.Errorf("persisting instance type overheads, %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overhead_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/system"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "knative.dev/pkg/logging/testing"
+	_ "knative.dev/pkg/system/testing"
+
+	coresettings "github.com/aws/karpenter-core/pkg/apis/settings"
+	corev1beta1 "github.com/aws/karpenter-core/pkg/apis/v1beta1"
+	corecontroller "github.com/aws/karpenter-core/pkg/operator/controller"
+	"github.com/aws/karpenter-core/pkg/operator/scheme"
+	coretest "github.com/aws/karpenter-core/pkg/test"
+	. "github.com/aws/karpenter-core/pkg/test/expectations"
+	"github.com/aws/karpenter/pkg/apis"
+	"github.com/aws/karpenter/pkg/apis/settings"
+	"github.com/aws/karpenter/pkg/apis/v1beta1"
+	"github.com/aws/karpenter/pkg/controllers/overhead"
+	"github.com/aws/karpenter/pkg/providers/instancetype"
+	"github.com/aws/karpenter/pkg/test"
+)
+
+var ctx context.Context
+var env *coretest.Environment
+var awsEnv *test.Environment
+var controller corecontroller.Controller
+
+func TestAPIs(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Overhead")
+}
+
+var _ = BeforeSuite(func() {
+	env = coretest.NewEnvironment(scheme.Scheme, coretest.WithCRDs(apis.CRDs...))
+	ctx = coresettings.ToContext(ctx, coretest.Settings())
+	ctx = settings.ToContext(ctx, test.Settings())
+	awsEnv = test.NewEnvironment(ctx, env)
+	controller = overhead.NewController(env.Client, awsEnv.InstanceTypesProvider, awsEnv.OverheadStore)
+	ExpectApplied(ctx, env.Client, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: system.Namespace()}})
+})
+
+var _ = AfterSuite(func() {
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+var _ = BeforeEach(func() {
+	awsEnv.Reset()
+})
+
+var _ = AfterEach(func() {
+	ExpectCleanedUp(ctx, env.Client)
+	Expect(client.IgnoreNotFound(env.Client.Delete(ctx, &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: instancetype.OverheadConfigMapName, Namespace: system.Namespace()}}))).To(Succeed())
+})
+
+var _ = Describe("Overhead", func() {
+	var nodeClass *v1beta1.EC2NodeClass
+	var nodePool *corev1beta1.NodePool
+	var info *ec2.InstanceTypeInfo
+
+	// node returns a node of the NodePool that reports the m5.large VM memory minus the overhead as its capacity
+	node := func(overheadMiB int64) *v1.Node {
+		return coretest.Node(coretest.NodeOptions{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					corev1beta1.NodePoolLabelKey:     nodePool.Name,
+					v1.LabelInstanceTypeStable:       "m5.large",
+					v1beta1.LabelNodeClass:           nodeClass.Name,
+					corev1beta1.CapacityTypeLabelKey: corev1beta1.CapacityTypeOnDemand,
+				},
+			},
+			Capacity: v1.ResourceList{
+				v1.ResourceMemory: resource.MustParse(fmt.Sprintf("%dMi", instancetype.VMMemoryMiB(info)-overheadMiB)),
+			},
+		})
+	}
+
+	BeforeEach(func() {
+		nodeClass = test.EC2NodeClass()
+		nodePool = coretest.NodePool(corev1beta1.NodePool{
+			Spec: corev1beta1.NodePoolSpec{
+				Template: corev1beta1.NodeClaimTemplate{
+					Spec: corev1beta1.NodeClaimSpec{
+						NodeClassRef: &corev1beta1.NodeClassReference{
+							Name: nodeClass.Name,
+						},
+					},
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+		infos, err := awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		info = lo.FindOrElse(infos, nil, func(i *ec2.InstanceTypeInfo) bool { return aws.StringValue(i.InstanceType) == "m5.large" })
+		Expect(info).ToNot(BeNil())
+	})
+	It("should not use the learned overhead before enough nodes are observed", func() {
+		for i := 0; i < instancetype.MinOverheadSamples-1; i++ {
+			ExpectApplied(ctx, env.Client, node(300))
+		}
+		ExpectReconcileSucceeded(ctx, controller, client.ObjectKey{})
+
+		_, ok := awsEnv.OverheadStore.MemoryOverhead("m5.large", v1beta1.AMIFamilyAL2)
+		Expect(ok).To(BeFalse())
+		overhead, ok := awsEnv.OverheadStore.Get("m5.large", v1beta1.AMIFamilyAL2)
+		Expect(ok).To(BeTrue())
+		Expect(overhead.Samples).To(Equal(instancetype.MinOverheadSamples - 1))
+	})
+	It("should use the largest learned overhead once enough nodes are observed", func() {
+		ExpectApplied(ctx, env.Client, node(300), node(350), node(320))
+		ExpectReconcileSucceeded(ctx, controller, client.ObjectKey{})
+
+		overheadMiB, ok := awsEnv.OverheadStore.MemoryOverhead("m5.large", v1beta1.AMIFamilyAL2)
+		Expect(ok).To(BeTrue())
+		Expect(overheadMiB).To(BeNumerically("==", 350))
+		memory := instancetype.MemoryCapacity(ctx, info, v1beta1.AMIFamilyAL2, awsEnv.OverheadStore)
+		Expect(memory.Value()).To(BeNumerically("==", (instancetype.VMMemoryMiB(info)-350)*1024*1024))
+	})
+	It("should not count nodes that were already observed", func() {
+		ExpectApplied(ctx, env.Client, node(300), node(300))
+		ExpectReconcileSucceeded(ctx, controller, client.ObjectKey{})
+		ExpectReconcileSucceeded(ctx, controller, client.ObjectKey{})
+
+		overhead, ok := awsEnv.OverheadStore.Get("m5.large", v1beta1.AMIFamilyAL2)
+		Expect(ok).To(BeTrue())
+		Expect(overhead.Samples).To(Equal(2))
+	})
+	It("should learn overheads per AMI family", func() {
+		nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
+		ExpectApplied(ctx, env.Client, nodeClass, node(300), node(300), node(300))
+		ExpectReconcileSucceeded(ctx, controller, client.ObjectKey{})
+
+		_, ok := awsEnv.OverheadStore.MemoryOverhead("m5.large", v1beta1.AMIFamilyAL2)
+		Expect(ok).To(BeFalse())
+		_, ok = awsEnv.OverheadStore.MemoryOverhead("m5.large", v1beta1.AMIFamilyBottlerocket)
+		Expect(ok).To(BeTrue())
+	})
+	It("should ignore nodes whose NodePool no longer exists", func() {
+		ExpectApplied(ctx, env.Client, node(300))
+		ExpectDeleted(ctx, env.Client, nodePool)
+		ExpectReconcileSucceeded(ctx, controller, client.ObjectKey{})
+
+		_, ok := awsEnv.OverheadStore.Get("m5.large", v1beta1.AMIFamilyAL2)
+		Expect(ok).To(BeFalse())
+	})
+	It("should persist the learned overheads so that they can be hydrated", func() {
+		ExpectApplied(ctx, env.Client, node(300), node(300), node(300))
+		ExpectReconcileSucceeded(ctx, controller, client.ObjectKey{})
+
+		cm := &v1.ConfigMap{}
+		Expect(env.Client.Get(ctx, client.ObjectKey{Name: instancetype.OverheadConfigMapName, Namespace: system.Namespace()}, cm)).To(Succeed())
+		store := instancetype.NewOverheadStore()
+		Expect(store.Hydrate(cm)).To(Succeed())
+		overheadMiB, ok := store.MemoryOverhead("m5.large", v1beta1.AMIFamilyAL2)
+		Expect(ok).To(BeTrue())
+		Expect(overheadMiB).To(BeNumerically("==", 300))
+	})
+	It("should expose the memory capacity prediction error", func() {
+		n := node(300)
+		ExpectApplied(ctx, env.Client, n)
+		predicted := instancetype.MemoryCapacity(ctx, info, v1beta1.AMIFamilyAL2, nil)
+		ExpectReconcileSucceeded(ctx, controller, client.ObjectKey{})
+
+		metric, ok := FindMetricWithLabelValues("karpenter_cloudprovider_instance_type_memory_capacity_prediction_error_bytes", map[string]string{
+			"instance_type": "m5.large",
+			"ami_family":    v1beta1.AMIFamilyAL2,
+		})
+		Expect(ok).To(BeTrue())
+		Expect(metric.GetGauge().GetValue()).To(BeNumerically("==", predicted.Value()-n.Status.Capacity.Memory().Value()))
+	})
+})
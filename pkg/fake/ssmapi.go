@@ -17,6 +17,7 @@ package fake
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/mitchellh/hashstructure/v2"
@@ -30,6 +31,7 @@ import (
 type SSMAPI struct {
 	ssmiface.SSMAPI
 	Parameters         map[string]string
+	ParameterHistory   map[string][]*ssm.ParameterHistory
 	GetParameterOutput *ssm.GetParameterOutput
 	WantErr            error
 }
@@ -60,8 +62,31 @@ func (a SSMAPI) GetParameterWithContext(_ context.Context, input *ssm.GetParamet
 	}, nil
 }
 
+// GetParameterHistoryPagesWithContext returns the ParameterHistory of the parameter if it's set, and otherwise a single
+// version with the value of the parameter that was published long ago
+func (a SSMAPI) GetParameterHistoryPagesWithContext(ctx context.Context, input *ssm.GetParameterHistoryInput, fn func(*ssm.GetParameterHistoryOutput, bool) bool, _ ...request.Option) error {
+	if a.WantErr != nil {
+		return a.WantErr
+	}
+	if history, ok := a.ParameterHistory[*input.Name]; ok {
+		fn(&ssm.GetParameterHistoryOutput{Parameters: history}, true)
+		return nil
+	}
+	output, err := a.GetParameterWithContext(ctx, &ssm.GetParameterInput{Name: input.Name})
+	if err != nil {
+		return err
+	}
+	fn(&ssm.GetParameterHistoryOutput{Parameters: []*ssm.ParameterHistory{{
+		Name:             input.Name,
+		Value:            output.Parameter.Value,
+		LastModifiedDate: aws.Time(time.Time{}),
+	}}}, true)
+	return nil
+}
+
 func (a *SSMAPI) Reset() {
 	a.GetParameterOutput = nil
 	a.Parameters = nil
+	a.ParameterHistory = nil
 	a.WantErr = nil
 }
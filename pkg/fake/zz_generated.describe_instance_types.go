@@ -35,7 +35,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
 			ProcessorInfo: &ec2.ProcessorInfo{
-				SupportedArchitectures: aws.StringSlice([]string{"arm64"}),
+				Manufacturer:             aws.String("AWS"),
+				SupportedArchitectures:   aws.StringSlice([]string{"arm64"}),
+				SustainedClockSpeedInGhz: aws.Float64(2.5),
 			},
 			VCpuInfo: &ec2.VCpuInfo{
 				DefaultCores: aws.Int64(2),
@@ -44,10 +46,16 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			MemoryInfo: &ec2.MemoryInfo{
 				SizeInMiB: aws.Int64(4096),
 			},
+			EbsInfo: &ec2.EbsInfo{
+				EbsOptimizedInfo: &ec2.EbsOptimizedInfo{
+					MaximumBandwidthInMbps: aws.Int64(4750),
+				},
+			},
 			NetworkInfo: &ec2.NetworkInfo{
 				MaximumNetworkInterfaces:     aws.Int64(3),
 				Ipv4AddressesPerInterface:    aws.Int64(10),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSrdSupported:              aws.Bool(false),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -65,7 +73,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
 			ProcessorInfo: &ec2.ProcessorInfo{
-				SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+				Manufacturer:             aws.String("Intel"),
+				SupportedArchitectures:   aws.StringSlice([]string{"x86_64"}),
+				SustainedClockSpeedInGhz: aws.Float64(3),
 			},
 			VCpuInfo: &ec2.VCpuInfo{
 				DefaultCores: aws.Int64(48),
@@ -89,10 +99,16 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceStorageInfo: &ec2.InstanceStorageInfo{NvmeSupport: aws.String("required"),
 				TotalSizeInGB: aws.Int64(4000),
 			},
+			EbsInfo: &ec2.EbsInfo{
+				EbsOptimizedInfo: &ec2.EbsOptimizedInfo{
+					MaximumBandwidthInMbps: aws.Int64(19000),
+				},
+			},
 			NetworkInfo: &ec2.NetworkInfo{
 				MaximumNetworkInterfaces:     aws.Int64(60),
 				Ipv4AddressesPerInterface:    aws.Int64(50),
 				EncryptionInTransitSupported: aws.Bool(true),
+				EnaSrdSupported:              aws.Bool(false),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -122,7 +138,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
 			ProcessorInfo: &ec2.ProcessorInfo{
-				SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+				Manufacturer:             aws.String("Intel"),
+				SupportedArchitectures:   aws.StringSlice([]string{"x86_64"}),
+				SustainedClockSpeedInGhz: aws.Float64(2.5),
 			},
 			VCpuInfo: &ec2.VCpuInfo{
 				DefaultCores: aws.Int64(16),
@@ -146,10 +164,16 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceStorageInfo: &ec2.InstanceStorageInfo{NvmeSupport: aws.String("required"),
 				TotalSizeInGB: aws.Int64(900),
 			},
+			EbsInfo: &ec2.EbsInfo{
+				EbsOptimizedInfo: &ec2.EbsOptimizedInfo{
+					MaximumBandwidthInMbps: aws.Int64(9500),
+				},
+			},
 			NetworkInfo: &ec2.NetworkInfo{
 				MaximumNetworkInterfaces:     aws.Int64(4),
 				Ipv4AddressesPerInterface:    aws.Int64(15),
 				EncryptionInTransitSupported: aws.Bool(true),
+				EnaSrdSupported:              aws.Bool(false),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -167,7 +191,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
 			ProcessorInfo: &ec2.ProcessorInfo{
-				SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+				Manufacturer:             aws.String("Intel"),
+				SupportedArchitectures:   aws.StringSlice([]string{"x86_64"}),
+				SustainedClockSpeedInGhz: aws.Float64(3),
 			},
 			VCpuInfo: &ec2.VCpuInfo{
 				DefaultCores: aws.Int64(4),
@@ -185,10 +211,16 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 					},
 				},
 			},
+			EbsInfo: &ec2.EbsInfo{
+				EbsOptimizedInfo: &ec2.EbsOptimizedInfo{
+					MaximumBandwidthInMbps: aws.Int64(4750),
+				},
+			},
 			NetworkInfo: &ec2.NetworkInfo{
 				MaximumNetworkInterfaces:     aws.Int64(4),
 				Ipv4AddressesPerInterface:    aws.Int64(10),
 				EncryptionInTransitSupported: aws.Bool(true),
+				EnaSrdSupported:              aws.Bool(false),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -206,7 +238,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
 			ProcessorInfo: &ec2.ProcessorInfo{
-				SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+				Manufacturer:             aws.String("Intel"),
+				SupportedArchitectures:   aws.StringSlice([]string{"x86_64"}),
+				SustainedClockSpeedInGhz: aws.Float64(3),
 			},
 			VCpuInfo: &ec2.VCpuInfo{
 				DefaultCores: aws.Int64(12),
@@ -224,10 +258,16 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 					},
 				},
 			},
+			EbsInfo: &ec2.EbsInfo{
+				EbsOptimizedInfo: &ec2.EbsOptimizedInfo{
+					MaximumBandwidthInMbps: aws.Int64(4750),
+				},
+			},
 			NetworkInfo: &ec2.NetworkInfo{
 				MaximumNetworkInterfaces:     aws.Int64(8),
 				Ipv4AddressesPerInterface:    aws.Int64(30),
 				EncryptionInTransitSupported: aws.Bool(true),
+				EnaSrdSupported:              aws.Bool(false),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -245,7 +285,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
 			ProcessorInfo: &ec2.ProcessorInfo{
-				SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+				Manufacturer:             aws.String("Intel"),
+				SupportedArchitectures:   aws.StringSlice([]string{"x86_64"}),
+				SustainedClockSpeedInGhz: aws.Float64(3.1),
 			},
 			VCpuInfo: &ec2.VCpuInfo{
 				DefaultCores: aws.Int64(1),
@@ -254,10 +296,16 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			MemoryInfo: &ec2.MemoryInfo{
 				SizeInMiB: aws.Int64(8192),
 			},
+			EbsInfo: &ec2.EbsInfo{
+				EbsOptimizedInfo: &ec2.EbsOptimizedInfo{
+					MaximumBandwidthInMbps: aws.Int64(4750),
+				},
+			},
 			NetworkInfo: &ec2.NetworkInfo{
 				MaximumNetworkInterfaces:     aws.Int64(3),
 				Ipv4AddressesPerInterface:    aws.Int64(10),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSrdSupported:              aws.Bool(false),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -275,7 +323,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			BareMetal:                     aws.Bool(true),
 			Hypervisor:                    aws.String(""),
 			ProcessorInfo: &ec2.ProcessorInfo{
-				SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+				Manufacturer:             aws.String("Intel"),
+				SupportedArchitectures:   aws.StringSlice([]string{"x86_64"}),
+				SustainedClockSpeedInGhz: aws.Float64(3.1),
 			},
 			VCpuInfo: &ec2.VCpuInfo{
 				DefaultCores: aws.Int64(48),
@@ -284,10 +334,16 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			MemoryInfo: &ec2.MemoryInfo{
 				SizeInMiB: aws.Int64(393216),
 			},
+			EbsInfo: &ec2.EbsInfo{
+				EbsOptimizedInfo: &ec2.EbsOptimizedInfo{
+					MaximumBandwidthInMbps: aws.Int64(19000),
+				},
+			},
 			NetworkInfo: &ec2.NetworkInfo{
 				MaximumNetworkInterfaces:     aws.Int64(15),
 				Ipv4AddressesPerInterface:    aws.Int64(50),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSrdSupported:              aws.Bool(false),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -305,7 +361,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
 			ProcessorInfo: &ec2.ProcessorInfo{
-				SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+				Manufacturer:             aws.String("Intel"),
+				SupportedArchitectures:   aws.StringSlice([]string{"x86_64"}),
+				SustainedClockSpeedInGhz: aws.Float64(3.1),
 			},
 			VCpuInfo: &ec2.VCpuInfo{
 				DefaultCores: aws.Int64(2),
@@ -314,10 +372,16 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			MemoryInfo: &ec2.MemoryInfo{
 				SizeInMiB: aws.Int64(16384),
 			},
+			EbsInfo: &ec2.EbsInfo{
+				EbsOptimizedInfo: &ec2.EbsOptimizedInfo{
+					MaximumBandwidthInMbps: aws.Int64(4750),
+				},
+			},
 			NetworkInfo: &ec2.NetworkInfo{
 				MaximumNetworkInterfaces:     aws.Int64(4),
 				Ipv4AddressesPerInterface:    aws.Int64(15),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSrdSupported:              aws.Bool(false),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -335,7 +399,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
 			ProcessorInfo: &ec2.ProcessorInfo{
-				SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+				Manufacturer:             aws.String("Intel"),
+				SupportedArchitectures:   aws.StringSlice([]string{"x86_64"}),
+				SustainedClockSpeedInGhz: aws.Float64(3.5),
 			},
 			VCpuInfo: &ec2.VCpuInfo{
 				DefaultCores: aws.Int64(64),
@@ -347,10 +413,16 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceStorageInfo: &ec2.InstanceStorageInfo{NvmeSupport: aws.String("required"),
 				TotalSizeInGB: aws.Int64(7600),
 			},
+			EbsInfo: &ec2.EbsInfo{
+				EbsOptimizedInfo: &ec2.EbsOptimizedInfo{
+					MaximumBandwidthInMbps: aws.Int64(100000),
+				},
+			},
 			NetworkInfo: &ec2.NetworkInfo{
 				MaximumNetworkInterfaces:     aws.Int64(14),
 				Ipv4AddressesPerInterface:    aws.Int64(50),
 				EncryptionInTransitSupported: aws.Bool(true),
+				EnaSrdSupported:              aws.Bool(true),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -372,7 +444,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("xen"),
 			ProcessorInfo: &ec2.ProcessorInfo{
-				SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+				Manufacturer:             aws.String("Intel"),
+				SupportedArchitectures:   aws.StringSlice([]string{"x86_64"}),
+				SustainedClockSpeedInGhz: aws.Float64(2.3),
 			},
 			VCpuInfo: &ec2.VCpuInfo{
 				DefaultCores: aws.Int64(16),
@@ -393,10 +467,16 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 					},
 				},
 			},
+			EbsInfo: &ec2.EbsInfo{
+				EbsOptimizedInfo: &ec2.EbsOptimizedInfo{
+					MaximumBandwidthInMbps: aws.Int64(7000),
+				},
+			},
 			NetworkInfo: &ec2.NetworkInfo{
 				MaximumNetworkInterfaces:     aws.Int64(8),
 				Ipv4AddressesPerInterface:    aws.Int64(30),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSrdSupported:              aws.Bool(false),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -414,7 +494,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
 			ProcessorInfo: &ec2.ProcessorInfo{
-				SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+				Manufacturer:             aws.String("Intel"),
+				SupportedArchitectures:   aws.StringSlice([]string{"x86_64"}),
+				SustainedClockSpeedInGhz: aws.Float64(2.5),
 			},
 			VCpuInfo: &ec2.VCpuInfo{
 				DefaultCores: aws.Int64(1),
@@ -423,10 +505,16 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			MemoryInfo: &ec2.MemoryInfo{
 				SizeInMiB: aws.Int64(8192),
 			},
+			EbsInfo: &ec2.EbsInfo{
+				EbsOptimizedInfo: &ec2.EbsOptimizedInfo{
+					MaximumBandwidthInMbps: aws.Int64(2780),
+				},
+			},
 			NetworkInfo: &ec2.NetworkInfo{
 				MaximumNetworkInterfaces:     aws.Int64(3),
 				Ipv4AddressesPerInterface:    aws.Int64(12),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSrdSupported:              aws.Bool(false),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -444,7 +532,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
 			ProcessorInfo: &ec2.ProcessorInfo{
-				SupportedArchitectures: aws.StringSlice([]string{"arm64"}),
+				Manufacturer:             aws.String("AWS"),
+				SupportedArchitectures:   aws.StringSlice([]string{"arm64"}),
+				SustainedClockSpeedInGhz: aws.Float64(2.5),
 			},
 			VCpuInfo: &ec2.VCpuInfo{
 				DefaultCores: aws.Int64(2),
@@ -453,10 +543,16 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			MemoryInfo: &ec2.MemoryInfo{
 				SizeInMiB: aws.Int64(4096),
 			},
+			EbsInfo: &ec2.EbsInfo{
+				EbsOptimizedInfo: &ec2.EbsOptimizedInfo{
+					MaximumBandwidthInMbps: aws.Int64(2085),
+				},
+			},
 			NetworkInfo: &ec2.NetworkInfo{
 				MaximumNetworkInterfaces:     aws.Int64(3),
 				Ipv4AddressesPerInterface:    aws.Int64(6),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSrdSupported:              aws.Bool(false),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -474,7 +570,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
 			ProcessorInfo: &ec2.ProcessorInfo{
-				SupportedArchitectures: aws.StringSlice([]string{"arm64"}),
+				Manufacturer:             aws.String("AWS"),
+				SupportedArchitectures:   aws.StringSlice([]string{"arm64"}),
+				SustainedClockSpeedInGhz: aws.Float64(2.5),
 			},
 			VCpuInfo: &ec2.VCpuInfo{
 				DefaultCores: aws.Int64(2),
@@ -483,10 +581,16 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			MemoryInfo: &ec2.MemoryInfo{
 				SizeInMiB: aws.Int64(2048),
 			},
+			EbsInfo: &ec2.EbsInfo{
+				EbsOptimizedInfo: &ec2.EbsOptimizedInfo{
+					MaximumBandwidthInMbps: aws.Int64(2085),
+				},
+			},
 			NetworkInfo: &ec2.NetworkInfo{
 				MaximumNetworkInterfaces:     aws.Int64(3),
 				Ipv4AddressesPerInterface:    aws.Int64(4),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSrdSupported:              aws.Bool(false),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -504,7 +608,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
 			ProcessorInfo: &ec2.ProcessorInfo{
-				SupportedArchitectures: aws.StringSlice([]string{"arm64"}),
+				Manufacturer:             aws.String("AWS"),
+				SupportedArchitectures:   aws.StringSlice([]string{"arm64"}),
+				SustainedClockSpeedInGhz: aws.Float64(2.5),
 			},
 			VCpuInfo: &ec2.VCpuInfo{
 				DefaultCores: aws.Int64(4),
@@ -513,10 +619,16 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			MemoryInfo: &ec2.MemoryInfo{
 				SizeInMiB: aws.Int64(16384),
 			},
+			EbsInfo: &ec2.EbsInfo{
+				EbsOptimizedInfo: &ec2.EbsOptimizedInfo{
+					MaximumBandwidthInMbps: aws.Int64(2780),
+				},
+			},
 			NetworkInfo: &ec2.NetworkInfo{
 				MaximumNetworkInterfaces:     aws.Int64(4),
 				Ipv4AddressesPerInterface:    aws.Int64(15),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSrdSupported:              aws.Bool(false),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -534,7 +646,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
 			ProcessorInfo: &ec2.ProcessorInfo{
-				SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+				Manufacturer:             aws.String("Intel"),
+				SupportedArchitectures:   aws.StringSlice([]string{"x86_64"}),
+				SustainedClockSpeedInGhz: aws.Float64(3.5),
 			},
 			VCpuInfo: &ec2.VCpuInfo{
 				DefaultCores: aws.Int64(4),
@@ -546,10 +660,16 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceStorageInfo: &ec2.InstanceStorageInfo{NvmeSupport: aws.String("required"),
 				TotalSizeInGB: aws.Int64(474),
 			},
+			EbsInfo: &ec2.EbsInfo{
+				EbsOptimizedInfo: &ec2.EbsOptimizedInfo{
+					MaximumBandwidthInMbps: aws.Int64(20000),
+				},
+			},
 			NetworkInfo: &ec2.NetworkInfo{
 				MaximumNetworkInterfaces:     aws.Int64(4),
 				Ipv4AddressesPerInterface:    aws.Int64(15),
 				EncryptionInTransitSupported: aws.Bool(true),
+				EnaSrdSupported:              aws.Bool(false),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
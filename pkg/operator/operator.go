@@ -77,7 +77,9 @@ type Operator struct {
 	PricingProvider           *pricing.Provider
 	VersionProvider           *version.Provider
 	InstanceTypesProvider     *instancetype.Provider
+	OverheadStore             *instancetype.OverheadStore
 	InstanceProvider          *instance.Provider
+	WarmUp                    *WarmUp
 }
 
 func NewOperator(ctx context.Context, operator *operator.Operator) (context.Context, *Operator) {
@@ -107,12 +109,21 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 		logging.FromContext(ctx).Fatalf("Checking EC2 API connectivity, %s", err)
 	}
 	logging.FromContext(ctx).With("region", *sess.Config.Region).Debugf("discovered region")
-	clusterEndpoint, err := ResolveClusterEndpoint(ctx, eks.New(sess))
+	eksapi := eks.New(sess)
+	clusterEndpoint, err := ResolveClusterEndpoint(ctx, eksapi)
 	if err != nil {
 		logging.FromContext(ctx).Fatalf("unable to detect the cluster endpoint, %s", err)
 	} else {
 		logging.FromContext(ctx).With("cluster-endpoint", clusterEndpoint).Debugf("discovered cluster endpoint")
 	}
+	// We perform best-effort on resolving the cluster CIDR, since only nodes that bootstrap with nodeadm need it. EC2NodeClasses
+	// of those AMI families surface that it couldn't be resolved.
+	clusterCIDR, err := ResolveClusterCIDR(ctx, eksapi)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("unable to detect the cluster cidr, nodes can't be bootstrapped with nodeadm until aws.clusterCIDR is set, %s", err)
+	} else {
+		logging.FromContext(ctx).With("cluster-cidr", *clusterCIDR).Debugf("discovered cluster cidr")
+	}
 	// We perform best-effort on resolving the kube-dns IP
 	kubeDNSIP, err := kubeDNSIP(ctx, operator.KubernetesInterface)
 	if err != nil {
@@ -133,6 +144,14 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 		ec2api,
 		*sess.Config.Region,
 	)
+	// Hydrate the last persisted pricing data in the background so that startup isn't blocked on the API server
+	go func() {
+		snapshot, err := pricing.GetSnapshot(ctx, operator.KubernetesInterface)
+		if err != nil {
+			logging.FromContext(ctx).Errorf("unable to load the persisted pricing snapshot, %s", err)
+		}
+		pricingProvider.Hydrate(ctx, snapshot)
+	}()
 	versionProvider := version.NewProvider(operator.KubernetesInterface, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
 	amiProvider := amifamily.NewProvider(versionProvider, ssm.New(sess), ec2api, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
 	amiResolver := amifamily.New(amiProvider)
@@ -148,7 +167,15 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 		operator.Elected(),
 		kubeDNSIP,
 		clusterEndpoint,
+		clusterCIDR,
 	)
+	overheadStore := instancetype.NewOverheadStore()
+	// Hydrate the learned instance type overheads in the background so that startup isn't blocked on the API server
+	go func() {
+		if err := instancetype.HydrateOverheadStore(ctx, operator.KubernetesInterface, overheadStore); err != nil {
+			logging.FromContext(ctx).Errorf("unable to load the persisted instance type overheads, %s", err)
+		}
+	}()
 	instanceTypeProvider := instancetype.NewProvider(
 		*sess.Config.Region,
 		cache.New(awscache.InstanceTypesAndZonesTTL, awscache.DefaultCleanupInterval),
@@ -156,7 +183,16 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 		subnetProvider,
 		unavailableOfferingsCache,
 		pricingProvider,
+		overheadStore,
 	)
+	// Hydrate the last persisted instance type catalog in the background so that it can be used if the EC2 API is unavailable
+	go func() {
+		snapshot, err := instancetype.GetSnapshot(ctx, operator.KubernetesInterface)
+		if err != nil {
+			logging.FromContext(ctx).Errorf("unable to load the persisted instance type snapshot, %s", err)
+		}
+		instanceTypeProvider.Hydrate(ctx, snapshot)
+	}()
 	instanceProvider := instance.NewProvider(
 		ctx,
 		aws.StringValue(sess.Config.Region),
@@ -166,6 +202,16 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 		subnetProvider,
 		launchTemplateProvider,
 	)
+	warmUp := NewWarmUp(
+		operator.GetClient(),
+		instanceTypeProvider,
+		subnetProvider,
+		securityGroupProvider,
+		amiProvider,
+		launchTemplateProvider,
+		instanceProvider,
+	)
+	warmUp.Start(ctx, operator.Elected())
 
 	return ctx, &Operator{
 		Operator:                  operator,
@@ -181,7 +227,9 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 		LaunchTemplateProvider:    launchTemplateProvider,
 		PricingProvider:           pricingProvider,
 		InstanceTypesProvider:     instanceTypeProvider,
+		OverheadStore:             overheadStore,
 		InstanceProvider:          instanceProvider,
+		WarmUp:                    warmUp,
 	}
 }
 
@@ -217,6 +265,28 @@ func ResolveClusterEndpoint(ctx context.Context, eksAPI eksiface.EKSAPI) (string
 	return *out.Cluster.Endpoint, nil
 }
 
+// ResolveClusterCIDR returns the service CIDR of the cluster, which nodes that bootstrap with nodeadm need to join it
+func ResolveClusterCIDR(ctx context.Context, eksAPI eksiface.EKSAPI) (*string, error) {
+	if clusterCIDRFromSettings := settings.FromContext(ctx).ClusterCIDR; clusterCIDRFromSettings != "" {
+		return lo.ToPtr(clusterCIDRFromSettings), nil // cluster cidr is explicitly set
+	}
+	out, err := eksAPI.DescribeClusterWithContext(ctx, &eks.DescribeClusterInput{
+		Name: aws.String(settings.FromContext(ctx).ClusterName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve cluster cidr, %w", err)
+	}
+	if config := out.Cluster.KubernetesNetworkConfig; config != nil {
+		if config.ServiceIpv4Cidr != nil {
+			return config.ServiceIpv4Cidr, nil
+		}
+		if config.ServiceIpv6Cidr != nil {
+			return config.ServiceIpv6Cidr, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to resolve cluster cidr, cluster has no service cidr")
+}
+
 func getCABundle(ctx context.Context, restConfig *rest.Config) (*string, error) {
 	// Discover CA Bundle from the REST client. We could alternatively
 	// have used the simpler client-go InClusterConfig() method.
@@ -104,4 +104,46 @@ var _ = Describe("Operator", func() {
 		_, err := awscontext.ResolveClusterEndpoint(ctx, fakeEKSAPI)
 		Expect(err).To(HaveOccurred())
 	})
+	It("should not resolve the cidr via the API if set", func() {
+		ctx = settings.ToContext(ctx, test.Settings(test.SettingOptions{
+			ClusterCIDR: lo.ToPtr("10.200.0.0/16"),
+		}))
+		cidr, err := awscontext.ResolveClusterCIDR(ctx, fakeEKSAPI)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lo.FromPtr(cidr)).To(Equal("10.200.0.0/16"))
+		Expect(fakeEKSAPI.DescribeClusterBehavior.Calls()).To(BeZero())
+	})
+	It("should resolve the ipv4 service cidr of the cluster", func() {
+		fakeEKSAPI.DescribeClusterBehavior.Output.Set(
+			&eks.DescribeClusterOutput{
+				Cluster: &eks.Cluster{
+					KubernetesNetworkConfig: &eks.KubernetesNetworkConfigResponse{
+						ServiceIpv4Cidr: lo.ToPtr("10.100.0.0/16"),
+					},
+				},
+			},
+		)
+		cidr, err := awscontext.ResolveClusterCIDR(ctx, fakeEKSAPI)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lo.FromPtr(cidr)).To(Equal("10.100.0.0/16"))
+	})
+	It("should resolve the ipv6 service cidr of the cluster", func() {
+		fakeEKSAPI.DescribeClusterBehavior.Output.Set(
+			&eks.DescribeClusterOutput{
+				Cluster: &eks.Cluster{
+					KubernetesNetworkConfig: &eks.KubernetesNetworkConfigResponse{
+						ServiceIpv6Cidr: lo.ToPtr("fd30:1c53:5f8a::/108"),
+					},
+				},
+			},
+		)
+		cidr, err := awscontext.ResolveClusterCIDR(ctx, fakeEKSAPI)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lo.FromPtr(cidr)).To(Equal("fd30:1c53:5f8a::/108"))
+	})
+	It("should fail to resolve the cidr if the cluster has no network config", func() {
+		fakeEKSAPI.DescribeClusterBehavior.Output.Set(&eks.DescribeClusterOutput{Cluster: &eks.Cluster{}})
+		_, err := awscontext.ResolveClusterCIDR(ctx, fakeEKSAPI)
+		Expect(err).To(HaveOccurred())
+	})
 })
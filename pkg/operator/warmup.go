@@ -0,0 +1,231 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/samber/lo"
+	"go.uber.org/multierr"
+	"knative.dev/pkg/logging"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1beta1 "github.com/aws/karpenter-core/pkg/apis/v1beta1"
+	"github.com/aws/karpenter/pkg/apis/settings"
+	"github.com/aws/karpenter/pkg/apis/v1beta1"
+	"github.com/aws/karpenter/pkg/providers/amifamily"
+	"github.com/aws/karpenter/pkg/providers/instance"
+	"github.com/aws/karpenter/pkg/providers/instancetype"
+	"github.com/aws/karpenter/pkg/providers/launchtemplate"
+	"github.com/aws/karpenter/pkg/providers/securitygroup"
+	"github.com/aws/karpenter/pkg/providers/subnet"
+)
+
+// WarmUpTimeout bounds how long the caches are warmed up for, and how long readiness is held back by the warm-up
+const WarmUpTimeout = 2 * time.Minute
+
+// WarmUp prefetches the instance types, offerings, AMIs, subnets, security groups, launch templates and instances of
+// every EC2NodeClass once elected, so that the first provisioning loop after a rollout or failover doesn't have to
+// resolve them lazily
+type WarmUp struct {
+	kubeClient             client.Client
+	instanceTypeProvider   *instancetype.Provider
+	subnetProvider         *subnet.Provider
+	securityGroupProvider  *securitygroup.Provider
+	amiProvider            *amifamily.Provider
+	launchTemplateProvider *launchtemplate.Provider
+	instanceProvider       *instance.Provider
+
+	// started is closed, after startedAt is set, once the warm-up has started
+	started   chan struct{}
+	startedAt time.Time
+	done      chan struct{}
+}
+
+func NewWarmUp(kubeClient client.Client, instanceTypeProvider *instancetype.Provider, subnetProvider *subnet.Provider,
+	securityGroupProvider *securitygroup.Provider, amiProvider *amifamily.Provider, launchTemplateProvider *launchtemplate.Provider,
+	instanceProvider *instance.Provider) *WarmUp {
+	return &WarmUp{
+		kubeClient:             kubeClient,
+		instanceTypeProvider:   instanceTypeProvider,
+		subnetProvider:         subnetProvider,
+		securityGroupProvider:  securityGroupProvider,
+		amiProvider:            amiProvider,
+		launchTemplateProvider: launchTemplateProvider,
+		instanceProvider:       instanceProvider,
+		started:                make(chan struct{}),
+		done:                   make(chan struct{}),
+	}
+}
+
+// Start warms up the caches in the background once startAsync is closed
+func (w *WarmUp) Start(ctx context.Context, startAsync <-chan struct{}) {
+	go func() {
+		// only warm up the caches once elected leader
+		select {
+		case <-startAsync:
+		case <-ctx.Done():
+			return
+		}
+		if err := w.Run(ctx); err != nil {
+			logging.FromContext(ctx).Errorf("warming up caches, %s", err)
+		}
+	}()
+}
+
+// Run warms up the caches concurrently and marks the warm-up as done, even if some of the caches failed to warm up
+// since they're resolved lazily on the next use anyway
+func (w *WarmUp) Run(ctx context.Context) error {
+	defer close(w.done)
+	start := time.Now()
+	w.startedAt = start
+	close(w.started)
+	ctx, cancel := context.WithTimeout(ctx, WarmUpTimeout)
+	defer cancel()
+
+	nodeClassList := &v1beta1.EC2NodeClassList{}
+	if err := w.kubeClient.List(ctx, nodeClassList); err != nil {
+		return fmt.Errorf("listing node classes, %w", err)
+	}
+	nodePoolList := &corev1beta1.NodePoolList{}
+	if err := w.kubeClient.List(ctx, nodePoolList); err != nil {
+		return fmt.Errorf("listing node pools, %w", err)
+	}
+	nodeClasses := lo.Map(nodeClassList.Items, func(nc v1beta1.EC2NodeClass, _ int) *v1beta1.EC2NodeClass { return nc.DeepCopy() })
+
+	caches := map[string]func(context.Context) error{
+		"instance_types": func(ctx context.Context) error {
+			return w.warmUpInstanceTypes(ctx, nodeClasses, nodePoolList.Items)
+		},
+		"subnets": func(ctx context.Context) error {
+			return forEachNodeClass(nodeClasses, func(nodeClass *v1beta1.EC2NodeClass) error {
+				_, err := w.subnetProvider.List(ctx, nodeClass)
+				return err
+			})
+		},
+		"security_groups": func(ctx context.Context) error {
+			return forEachNodeClass(nodeClasses, func(nodeClass *v1beta1.EC2NodeClass) error {
+				_, err := w.securityGroupProvider.List(ctx, nodeClass)
+				return err
+			})
+		},
+		"amis": func(ctx context.Context) error {
+			return forEachNodeClass(nodeClasses, func(nodeClass *v1beta1.EC2NodeClass) error {
+				options, err := w.launchTemplateProvider.AMIOptions(ctx, nodeClass)
+				if err != nil {
+					return err
+				}
+				_, err = w.amiProvider.Get(ctx, nodeClass, options)
+				return err
+			})
+		},
+		"launch_templates": func(ctx context.Context) error {
+			select {
+			case <-w.launchTemplateProvider.Hydrated():
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+	// the instance inventory is only used when it's kept current by the interruption queue
+	if settings.FromContext(ctx).InterruptionQueueName != "" {
+		caches["instances"] = func(ctx context.Context) error {
+			_, err := w.instanceProvider.List(ctx)
+			return err
+		}
+	}
+
+	var mu sync.Mutex
+	var errs error
+	wg := sync.WaitGroup{}
+	for name, warmUp := range caches {
+		wg.Add(1)
+		go func(name string, warmUp func(context.Context) error) {
+			defer wg.Done()
+			cacheStart := time.Now()
+			err := warmUp(ctx)
+			WarmUpDuration.With(prometheus.Labels{cacheLabel: name}).Observe(time.Since(cacheStart).Seconds())
+			if err != nil {
+				mu.Lock()
+				errs = multierr.Append(errs, fmt.Errorf("warming up %s, %w", name, err))
+				mu.Unlock()
+			}
+		}(name, warmUp)
+	}
+	wg.Wait()
+	WarmUpDuration.With(prometheus.Labels{cacheLabel: "all"}).Observe(time.Since(start).Seconds())
+	logging.FromContext(ctx).With("node-class-count", len(nodeClasses), "duration", time.Since(start)).Debugf("warmed up caches")
+	return errs
+}
+
+// warmUpInstanceTypes lists the instance types of every NodePool's EC2NodeClass with the kubelet configuration of the
+// NodePool, since that's what the instance types are cached by, as well as those of EC2NodeClasses without NodePools
+func (w *WarmUp) warmUpInstanceTypes(ctx context.Context, nodeClasses []*v1beta1.EC2NodeClass, nodePools []corev1beta1.NodePool) error {
+	nodeClassesByName := lo.SliceToMap(nodeClasses, func(nc *v1beta1.EC2NodeClass) (string, *v1beta1.EC2NodeClass) { return nc.Name, nc })
+	referenced := map[string]bool{}
+	var errs error
+	for i := range nodePools {
+		if nodePools[i].Spec.Template.Spec.NodeClassRef == nil {
+			continue
+		}
+		nodeClass, ok := nodeClassesByName[nodePools[i].Spec.Template.Spec.NodeClassRef.Name]
+		if !ok {
+			continue
+		}
+		referenced[nodeClass.Name] = true
+		if _, err := w.instanceTypeProvider.List(ctx, nodePools[i].Spec.Template.Spec.Kubelet, nodeClass); err != nil {
+			errs = multierr.Append(errs, err)
+		}
+	}
+	return multierr.Append(errs, forEachNodeClass(lo.Reject(nodeClasses, func(nc *v1beta1.EC2NodeClass, _ int) bool { return referenced[nc.Name] }),
+		func(nodeClass *v1beta1.EC2NodeClass) error {
+			_, err := w.instanceTypeProvider.List(ctx, &corev1beta1.KubeletConfiguration{}, nodeClass)
+			return err
+		}))
+}
+
+// ReadinessProbe holds back readiness while the caches are being warmed up. Replicas that aren't elected never warm up
+// and are ready straight away, and a warm-up that's stuck stops holding back readiness once the warm-up timeout has passed.
+func (w *WarmUp) ReadinessProbe(_ *http.Request) error {
+	select {
+	case <-w.started:
+	default:
+		return nil
+	}
+	select {
+	case <-w.done:
+		return nil
+	default:
+	}
+	if time.Since(w.startedAt) > WarmUpTimeout {
+		return nil
+	}
+	return fmt.Errorf("caches not yet warmed up")
+}
+
+func forEachNodeClass(nodeClasses []*v1beta1.EC2NodeClass, f func(*v1beta1.EC2NodeClass) error) (errs error) {
+	for _, nodeClass := range nodeClasses {
+		if err := f(nodeClass); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("node class %q, %w", nodeClass.Name, err))
+		}
+	}
+	return errs
+}
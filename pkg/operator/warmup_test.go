@@ -0,0 +1,147 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator_test
+
+import (
+	"fmt"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickmn/go-cache"
+	"github.com/samber/lo"
+
+	corev1beta1 "github.com/aws/karpenter-core/pkg/apis/v1beta1"
+	coretest "github.com/aws/karpenter-core/pkg/test"
+	. "github.com/aws/karpenter-core/pkg/test/expectations"
+	"github.com/aws/karpenter/pkg/apis/v1beta1"
+	awscache "github.com/aws/karpenter/pkg/cache"
+	awscontext "github.com/aws/karpenter/pkg/operator"
+	"github.com/aws/karpenter/pkg/providers/amifamily"
+	"github.com/aws/karpenter/pkg/providers/launchtemplate"
+	"github.com/aws/karpenter/pkg/test"
+)
+
+var _ = Describe("WarmUp", func() {
+	var awsEnv *test.Environment
+	var warmUp *awscontext.WarmUp
+	var nodeClass *v1beta1.EC2NodeClass
+	var nodePool *corev1beta1.NodePool
+
+	BeforeEach(func() {
+		awsEnv = test.NewEnvironment(ctx, env)
+		// the launch template cache is hydrated immediately rather than once elected
+		elected := make(chan struct{})
+		close(elected)
+		launchTemplateProvider := launchtemplate.NewProvider(
+			ctx,
+			cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval),
+			awsEnv.EC2API,
+			awsEnv.AMIResolver,
+			awsEnv.SecurityGroupProvider,
+			awsEnv.SubnetProvider,
+			awsEnv.InstanceProfileProvider,
+			lo.ToPtr("ca-bundle"),
+			elected,
+			net.ParseIP("10.0.100.10"),
+			"https://test-cluster",
+			lo.ToPtr("10.100.0.0/16"),
+		)
+		Eventually(launchTemplateProvider.Hydrated()).Should(BeClosed())
+		warmUp = awscontext.NewWarmUp(env.Client, awsEnv.InstanceTypesProvider, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider,
+			awsEnv.AMIProvider, launchTemplateProvider, awsEnv.InstanceProvider)
+		nodeClass = test.EC2NodeClass()
+		nodePool = coretest.NodePool(corev1beta1.NodePool{
+			Spec: corev1beta1.NodePoolSpec{
+				Template: corev1beta1.NodeClaimTemplate{
+					Spec: corev1beta1.NodeClaimSpec{
+						NodeClassRef: &corev1beta1.NodeClassReference{Name: nodeClass.Name},
+					},
+				},
+			},
+		})
+	})
+	It("should warm up the caches of every node class", func() {
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		Expect(warmUp.Run(ctx)).To(Succeed())
+
+		// the EC2 API isn't called again, so the next error is never returned
+		awsEnv.EC2API.NextError.Set(fmt.Errorf("failed"))
+		_, err := awsEnv.SubnetProvider.List(ctx, nodeClass)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = awsEnv.SecurityGroupProvider.List(ctx, nodeClass)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = awsEnv.AMIProvider.Get(ctx, nodeClass, &amifamily.Options{})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(awsEnv.EC2API.NextError.IsNil()).To(BeFalse())
+	})
+	It("should warm up the instance types of node classes without a node pool", func() {
+		ExpectApplied(ctx, env.Client, nodeClass)
+		Expect(warmUp.Run(ctx)).To(Succeed())
+
+		awsEnv.EC2API.NextError.Set(fmt.Errorf("failed"))
+		_, err := awsEnv.InstanceTypesProvider.List(ctx, &corev1beta1.KubeletConfiguration{}, nodeClass)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(awsEnv.EC2API.NextError.IsNil()).To(BeFalse())
+	})
+	It("should report ready on replicas that aren't elected", func() {
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		warmUp.Start(ctx, make(chan struct{}))
+		Consistently(func() error { return warmUp.ReadinessProbe(nil) }).Should(Succeed())
+	})
+	It("should only report ready once the caches are warmed up", func() {
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		// the launch template cache isn't hydrated until elected, which holds back the warm-up
+		hydrate := make(chan struct{})
+		launchTemplateProvider := launchtemplate.NewProvider(
+			ctx,
+			cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval),
+			awsEnv.EC2API,
+			awsEnv.AMIResolver,
+			awsEnv.SecurityGroupProvider,
+			awsEnv.SubnetProvider,
+			awsEnv.InstanceProfileProvider,
+			lo.ToPtr("ca-bundle"),
+			hydrate,
+			net.ParseIP("10.0.100.10"),
+			"https://test-cluster",
+			lo.ToPtr("10.100.0.0/16"),
+		)
+		warmUp = awscontext.NewWarmUp(env.Client, awsEnv.InstanceTypesProvider, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider,
+			awsEnv.AMIProvider, launchTemplateProvider, awsEnv.InstanceProvider)
+		elected := make(chan struct{})
+		warmUp.Start(ctx, elected)
+		close(elected)
+		Eventually(func() error { return warmUp.ReadinessProbe(nil) }).ShouldNot(Succeed())
+		close(hydrate)
+		Eventually(func() error { return warmUp.ReadinessProbe(nil) }).Should(Succeed())
+	})
+	It("should report ready once warmed up even if a cache failed to warm up", func() {
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		awsEnv.EC2API.NextError.Set(fmt.Errorf("failed"))
+		Expect(warmUp.Run(ctx)).ToNot(Succeed())
+		Expect(warmUp.ReadinessProbe(nil)).To(Succeed())
+	})
+	It("should record the warm-up duration", func() {
+		Expect(warmUp.Run(ctx)).To(Succeed())
+		for _, name := range []string{"all", "instance_types", "subnets", "security_groups", "amis", "launch_templates"} {
+			metric, ok := FindMetricWithLabelValues("karpenter_cloudprovider_cache_warm_up_duration_seconds", map[string]string{"cache": name})
+			Expect(ok).To(BeTrue())
+			Expect(metric.GetHistogram().GetSampleCount()).To(BeNumerically(">", 0))
+		}
+	})
+})
@@ -30,6 +30,10 @@ import (
 	"github.com/aws/karpenter/pkg/providers/amifamily/bootstrap"
 )
 
+func init() {
+	Register(v1beta1.AMIFamilyAL2, func(options *Options) AMIFamily { return &AL2{Options: options} })
+}
+
 type AL2 struct {
 	DefaultFamily
 	*Options
@@ -91,6 +95,7 @@ func (a AL2) UserData(kubeletConfig *corev1beta1.KubeletConfiguration, taints []
 			Labels:                  labels,
 			CABundle:                caBundle,
 			CustomUserData:          customUserData,
+			InstanceStorePolicy:     a.Options.InstanceStorePolicy,
 		},
 	}
 }
@@ -106,3 +111,7 @@ func (a AL2) DefaultBlockDeviceMappings() []*v1beta1.BlockDeviceMapping {
 func (a AL2) EphemeralBlockDevice() *string {
 	return aws.String("/dev/xvda")
 }
+
+func (a AL2) RootBlockDevice() *string {
+	return a.EphemeralBlockDevice()
+}
@@ -15,10 +15,13 @@ limitations under the License.
 package amifamily
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -30,11 +33,14 @@ import (
 	"github.com/patrickmn/go-cache"
 	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"knative.dev/pkg/logging"
 
 	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
 	corev1beta1 "github.com/aws/karpenter-core/pkg/apis/v1beta1"
+	"github.com/aws/karpenter/pkg/apis/v1alpha1"
 	"github.com/aws/karpenter/pkg/apis/v1beta1"
+	awserrors "github.com/aws/karpenter/pkg/errors"
 	"github.com/aws/karpenter/pkg/providers/version"
 
 	"github.com/aws/karpenter-core/pkg/cloudprovider"
@@ -52,10 +58,104 @@ type Provider struct {
 }
 
 type AMI struct {
-	Name         string
-	AmiID        string
-	CreationDate string
-	Requirements scheduling.Requirements
+	Name            string
+	AmiID           string
+	CreationDate    string
+	DeprecationTime string
+	// EligibleTime is set while the AMI is held back by the soak period of the EC2NodeClass
+	EligibleTime time.Time
+	// RootDeviceName is the device name of the root volume of the AMI, e.g. /dev/xvda
+	RootDeviceName string
+	// ImageBlockDeviceMappings are the block device mappings that the AMI itself launches with
+	ImageBlockDeviceMappings []*ec2.BlockDeviceMapping
+	Requirements             scheduling.Requirements
+}
+
+// IsPending returns whether the AMI is still held back by the soak period at the passed time
+func (a AMI) IsPending(now time.Time) bool {
+	return a.EligibleTime.After(now)
+}
+
+// IsDeprecated returns whether the AMI has been deprecated at the passed time
+func (a AMI) IsDeprecated(now time.Time) bool {
+	return isDeprecated(a.DeprecationTime, now)
+}
+
+func isDeprecated(deprecationTime string, now time.Time) bool {
+	if deprecationTime == "" {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339, deprecationTime)
+	return err == nil && !t.After(now)
+}
+
+// BlockDeviceMappings returns the block device mappings to launch the AMI with. The root volume mapping, whether it's
+// defaulted by the AMI family or configured with rootVolume, is retargeted at the root device of the AMI, so that AMIs
+// with a differently named root device don't launch with an additional, unused volume.
+func (a AMI) BlockDeviceMappings(amiFamily AMIFamily, blockDeviceMappings []*v1beta1.BlockDeviceMapping) []*v1beta1.BlockDeviceMapping {
+	isRoot := func(bdm *v1beta1.BlockDeviceMapping) bool { return isRootVolume(amiFamily, bdm) }
+	if len(blockDeviceMappings) == 0 {
+		blockDeviceMappings = amiFamily.DefaultBlockDeviceMappings()
+		isRoot = func(bdm *v1beta1.BlockDeviceMapping) bool {
+			return amiFamily.RootBlockDevice() != nil && lo.FromPtr(bdm.DeviceName) == lo.FromPtr(amiFamily.RootBlockDevice())
+		}
+	}
+	// The mappings are left as they are if the root device is unknown or something already targets it
+	if a.RootDeviceName == "" || lo.ContainsBy(blockDeviceMappings, func(bdm *v1beta1.BlockDeviceMapping) bool {
+		return lo.FromPtr(bdm.DeviceName) == a.RootDeviceName
+	}) {
+		return blockDeviceMappings
+	}
+	return lo.Map(blockDeviceMappings, func(bdm *v1beta1.BlockDeviceMapping, _ int) *v1beta1.BlockDeviceMapping {
+		if !isRoot(bdm) {
+			return bdm
+		}
+		retargeted := *bdm
+		retargeted.DeviceName = aws.String(a.RootDeviceName)
+		return &retargeted
+	})
+}
+
+// BlockDeviceMappingMismatches returns the ways in which the block device mappings of an EC2NodeClass don't match the
+// root device of the AMI
+func (a AMI) BlockDeviceMappingMismatches(amiFamily AMIFamily, blockDeviceMappings []*v1beta1.BlockDeviceMapping) []string {
+	if a.RootDeviceName == "" {
+		return nil
+	}
+	var mismatches []string
+	targetsRoot := lo.ContainsBy(blockDeviceMappings, func(bdm *v1beta1.BlockDeviceMapping) bool {
+		return lo.FromPtr(bdm.DeviceName) == a.RootDeviceName
+	})
+	for _, bdm := range blockDeviceMappings {
+		if lo.FromPtr(bdm.DeviceName) == a.RootDeviceName {
+			continue
+		}
+		switch {
+		case isRootVolume(amiFamily, bdm) && !targetsRoot:
+			mismatches = append(mismatches, fmt.Sprintf("root volume %s is launched as the root device %s of the AMI", lo.FromPtr(bdm.DeviceName), a.RootDeviceName))
+		case amiFamily.RootBlockDevice() != nil && lo.FromPtr(bdm.DeviceName) == lo.FromPtr(amiFamily.RootBlockDevice()):
+			mismatches = append(mismatches, fmt.Sprintf("%s isn't the root device %s of the AMI and is launched as an additional volume", lo.FromPtr(bdm.DeviceName), a.RootDeviceName))
+		}
+	}
+	// The root volume can't be smaller than the snapshot that the AMI launches it from
+	root, ok := lo.Find(a.BlockDeviceMappings(amiFamily, blockDeviceMappings), func(bdm *v1beta1.BlockDeviceMapping) bool {
+		return lo.FromPtr(bdm.DeviceName) == a.RootDeviceName
+	})
+	image, imageOK := lo.Find(a.ImageBlockDeviceMappings, func(bdm *ec2.BlockDeviceMapping) bool {
+		return aws.StringValue(bdm.DeviceName) == a.RootDeviceName
+	})
+	if ok && imageOK && root.EBS != nil && root.EBS.VolumeSize != nil && image.Ebs != nil && image.Ebs.VolumeSize != nil {
+		if snapshotSize := resource.NewQuantity(aws.Int64Value(image.Ebs.VolumeSize)<<30, resource.BinarySI); root.EBS.VolumeSize.Cmp(*snapshotSize) < 0 {
+			mismatches = append(mismatches, fmt.Sprintf("root device %s is smaller than the %s snapshot of the AMI", a.RootDeviceName, snapshotSize.String()))
+		}
+	}
+	return mismatches
+}
+
+// isRootVolume returns whether the block device mapping is for the root device of the AMI. A rootVolume mapping is
+// only the root device of the AMI when the AMI family keeps the kubelet root dir on it.
+func isRootVolume(amiFamily AMIFamily, bdm *v1beta1.BlockDeviceMapping) bool {
+	return bdm.RootVolume && lo.FromPtr(amiFamily.EphemeralBlockDevice()) == lo.FromPtr(amiFamily.RootBlockDevice())
 }
 
 type AMIs []AMI
@@ -114,8 +214,20 @@ func NewProvider(versionProvider *version.Provider, ssm ssmiface.SSMAPI, ec2api
 	}
 }
 
-// Get Returning a list of AMIs with its associated requirements
+// Get Returning a list of AMIs with its associated requirements, excluding the AMIs that are still held back by the
+// soak period of the EC2NodeClass
 func (p *Provider) Get(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, options *Options) (AMIs, error) {
+	amis, err := p.GetWithPending(ctx, nodeClass, options)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return lo.Reject(amis, func(a AMI, _ int) bool { return a.IsPending(now) }), nil
+}
+
+// GetWithPending returns the AMIs of the EC2NodeClass, including the newer AMIs that are still held back by its soak
+// period, which are returned with the time from which they're eligible
+func (p *Provider) GetWithPending(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, options *Options) (AMIs, error) {
 	var err error
 	var amis AMIs
 	if len(nodeClass.Spec.AMISelectorTerms) == 0 {
@@ -124,7 +236,7 @@ func (p *Provider) Get(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, opt
 			return nil, err
 		}
 	} else {
-		amis, err = p.getAMIs(ctx, nodeClass.Spec.AMISelectorTerms, nodeClass.IsNodeTemplate)
+		amis, err = p.getAMIs(ctx, nodeClass.Spec.AMISelectorTerms, nodeClass.IsNodeTemplate, soakPeriod(nodeClass))
 		if err != nil {
 			return nil, err
 		}
@@ -137,16 +249,38 @@ func (p *Provider) Get(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, opt
 }
 
 func (p *Provider) getDefaultAMIs(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, options *Options) (res AMIs, err error) {
-	if images, ok := p.cache.Get(lo.FromPtr(nodeClass.Spec.AMIFamily)); ok {
+	soakPeriod := soakPeriod(nodeClass)
+	key := lo.FromPtr(nodeClass.Spec.AMIFamily)
+	if soakPeriod > 0 {
+		key = fmt.Sprintf("%s/%s", key, soakPeriod)
+	}
+	if images, ok := p.cache.Get(key); ok {
 		return images.(AMIs), nil
 	}
-	amiFamily := GetAMIFamily(nodeClass.Spec.AMIFamily, options)
+	amiFamily, err := GetAMIFamily(nodeClass.Spec.AMIFamily, options)
+	if err != nil {
+		return nil, err
+	}
 	kubernetesVersion, err := p.versionProvider.Get(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("getting kubernetes version %w", err)
 	}
 	defaultAMIs := amiFamily.DefaultAMIs(kubernetesVersion, nodeClass.IsNodeTemplate)
 	for _, ami := range defaultAMIs {
+		if soakPeriod > 0 {
+			eligibleID, pendingID, eligibleTime, err := p.resolveSoakedSSMParameter(ctx, ami.Query, soakPeriod)
+			if err != nil {
+				logging.FromContext(ctx).With("query", ami.Query).Errorf("discovering amis from ssm, %s", err)
+				continue
+			}
+			if eligibleID != "" {
+				res = append(res, AMI{AmiID: eligibleID, Requirements: ami.Requirements})
+			}
+			if pendingID != "" {
+				res = append(res, AMI{AmiID: pendingID, EligibleTime: eligibleTime, Requirements: ami.Requirements})
+			}
+			continue
+		}
 		if id, err := p.resolveSSMParameter(ctx, ami.Query); err != nil {
 			logging.FromContext(ctx).With("query", ami.Query).Errorf("discovering amis from ssm, %s", err)
 		} else {
@@ -163,6 +297,9 @@ func (p *Provider) getDefaultAMIs(ctx context.Context, nodeClass *v1beta1.EC2Nod
 				if res[j].AmiID == aws.StringValue(page.Images[i].ImageId) {
 					res[j].Name = aws.StringValue(page.Images[i].Name)
 					res[j].CreationDate = aws.StringValue(page.Images[i].CreationDate)
+					res[j].DeprecationTime = aws.StringValue(page.Images[i].DeprecationTime)
+					res[j].RootDeviceName = aws.StringValue(page.Images[i].RootDeviceName)
+					res[j].ImageBlockDeviceMappings = page.Images[i].BlockDeviceMappings
 				}
 			}
 		}
@@ -170,7 +307,7 @@ func (p *Provider) getDefaultAMIs(ctx context.Context, nodeClass *v1beta1.EC2Nod
 	}); err != nil {
 		return nil, fmt.Errorf("describing images, %w", err)
 	}
-	p.cache.SetDefault(lo.FromPtr(nodeClass.Spec.AMIFamily), res)
+	p.cache.SetDefault(key, res)
 	return res, nil
 }
 
@@ -183,70 +320,302 @@ func (p *Provider) resolveSSMParameter(ctx context.Context, ssmQuery string) (st
 	return ami, nil
 }
 
-func (p *Provider) getAMIs(ctx context.Context, terms []v1beta1.AMISelectorTerm, isNodeTemplate bool) (AMIs, error) {
+// resolveSoakedSSMParameter returns the latest value of the SSM parameter that was published at least soakPeriod ago,
+// as well as the latest value and the time from which it's eligible if it was published more recently than that
+func (p *Provider) resolveSoakedSSMParameter(ctx context.Context, ssmQuery string, soakPeriod time.Duration) (eligible string, pending string, eligibleTime time.Time, err error) {
+	var history []*ssm.ParameterHistory
+	if err = p.ssm.GetParameterHistoryPagesWithContext(ctx, &ssm.GetParameterHistoryInput{Name: aws.String(ssmQuery)}, func(page *ssm.GetParameterHistoryOutput, _ bool) bool {
+		history = append(history, page.Parameters...)
+		return true
+	}); err != nil {
+		return "", "", time.Time{}, fmt.Errorf("getting ssm parameter history %q, %w", ssmQuery, err)
+	}
+	if len(history) == 0 {
+		return "", "", time.Time{}, fmt.Errorf("ssm parameter %q has no history", ssmQuery)
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return aws.TimeValue(history[i].LastModifiedDate).Before(aws.TimeValue(history[j].LastModifiedDate))
+	})
+	now := time.Now()
+	for _, version := range history {
+		if aws.TimeValue(version.LastModifiedDate).Add(soakPeriod).After(now) {
+			break
+		}
+		eligible = aws.StringValue(version.Value)
+	}
+	latest := history[len(history)-1]
+	if aws.StringValue(latest.Value) != eligible && aws.TimeValue(latest.LastModifiedDate).Add(soakPeriod).After(now) {
+		return eligible, aws.StringValue(latest.Value), aws.TimeValue(latest.LastModifiedDate).Add(soakPeriod), nil
+	}
+	return eligible, "", time.Time{}, nil
+}
+
+func (p *Provider) getAMIs(ctx context.Context, terms []v1beta1.AMISelectorTerm, isNodeTemplate bool, soakPeriod time.Duration) (AMIs, error) {
 	filterAndOwnerSets := GetFilterAndOwnerSets(terms)
+	ssmParameters, err := p.renderSSMParameters(ctx, terms)
+	if err != nil {
+		return nil, err
+	}
 	hash, err := hashstructure.Hash(filterAndOwnerSets, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
 	if err != nil {
 		return nil, err
 	}
-	if images, ok := p.cache.Get(fmt.Sprintf("%t/%d", isNodeTemplate, hash)); ok {
+	key := fmt.Sprintf("%t/%d", isNodeTemplate, hash)
+	if len(ssmParameters) > 0 {
+		key = fmt.Sprintf("%s/%d", key, lo.Must(hashstructure.Hash(ssmParameters, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})))
+	}
+	if soakPeriod > 0 {
+		key = fmt.Sprintf("%s/%s", key, soakPeriod)
+	}
+	if images, ok := p.cache.Get(key); ok {
 		return images.(AMIs), nil
 	}
+	// AMIs that are selected by SSM parameter are looked up by the ids that the parameters resolve to
+	resolved, err := p.resolveSSMParameters(ctx, ssmParameters, soakPeriod)
+	if err != nil {
+		return nil, err
+	}
+	filterAndOwnerSets = append(filterAndOwnerSets, resolved.filterAndOwnerSets()...)
+	// The newest eligible AMI and the newest AMI that's still held back by the soak period are tracked for each set of requirements
 	images := map[uint64]AMI{}
+	pendingImages := map[uint64]AMI{}
+	now := time.Now()
 	for _, filtersAndOwners := range filterAndOwnerSets {
+		// AMIs that are selected by id are pinned, so they aren't held back by the soak period
+		pinned := lo.ContainsBy(filtersAndOwners.Filters, func(f *ec2.Filter) bool { return aws.StringValue(f.Name) == "image-id" })
 		if err = p.ec2api.DescribeImagesPagesWithContext(ctx, &ec2.DescribeImagesInput{
 			// Don't include filters in the Describe Images call as EC2 API doesn't allow empty filters.
-			Filters:    lo.Ternary(len(filtersAndOwners.Filters) > 0, filtersAndOwners.Filters, nil),
-			Owners:     lo.Ternary(len(filtersAndOwners.Owners) > 0, aws.StringSlice(filtersAndOwners.Owners), nil),
-			MaxResults: aws.Int64(500),
+			Filters:           lo.Ternary(len(filtersAndOwners.Filters) > 0, filtersAndOwners.Filters, nil),
+			Owners:            lo.Ternary(len(filtersAndOwners.Owners) > 0, aws.StringSlice(filtersAndOwners.Owners), nil),
+			IncludeDeprecated: aws.Bool(filtersAndOwners.IncludeDeprecated),
+			MaxResults:        aws.Int64(500),
 		}, func(page *ec2.DescribeImagesOutput, _ bool) bool {
 			for i := range page.Images {
+				if !isUsable(page.Images[i], filtersAndOwners.IncludeDeprecated, now) {
+					continue
+				}
 				reqs := p.getRequirementsFromImage(page.Images[i], isNodeTemplate)
 				if !v1beta1.WellKnownArchitectures.Has(reqs.Get(v1.LabelArchStable).Any()) {
 					continue
 				}
+				resolvedAMI, fromSSM := resolved[lo.FromPtr(page.Images[i].ImageId)]
+				if gpuRequirement, ok := getGPURequirement(page.Images[i], isNodeTemplate); fromSSM && ok {
+					reqs.Add(gpuRequirement)
+				}
 				reqsHash := lo.Must(hashstructure.Hash(reqs.NodeSelectorRequirements(), hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true}))
-				// If the proposed image is newer, store it so that we can return it
-				if v, ok := images[reqsHash]; ok {
-					candidateCreationTime, _ := time.Parse(time.RFC3339, lo.FromPtr(page.Images[i].CreationDate))
-					existingCreationTime, _ := time.Parse(time.RFC3339, v.CreationDate)
-					if existingCreationTime == candidateCreationTime && lo.FromPtr(page.Images[i].Name) < v.Name {
-						continue
-					}
-					if candidateCreationTime.Unix() < existingCreationTime.Unix() {
-						continue
-					}
+				ami := AMI{
+					Name:                     lo.FromPtr(page.Images[i].Name),
+					AmiID:                    lo.FromPtr(page.Images[i].ImageId),
+					CreationDate:             lo.FromPtr(page.Images[i].CreationDate),
+					DeprecationTime:          lo.FromPtr(page.Images[i].DeprecationTime),
+					RootDeviceName:           lo.FromPtr(page.Images[i].RootDeviceName),
+					ImageBlockDeviceMappings: page.Images[i].BlockDeviceMappings,
+					Requirements:             reqs,
+				}
+				if creationTime, err := time.Parse(time.RFC3339, ami.CreationDate); err == nil && !pinned && soakPeriod > 0 {
+					ami.EligibleTime = creationTime.Add(soakPeriod)
 				}
-				images[reqsHash] = AMI{
-					Name:         lo.FromPtr(page.Images[i].Name),
-					AmiID:        lo.FromPtr(page.Images[i].ImageId),
-					CreationDate: lo.FromPtr(page.Images[i].CreationDate),
-					Requirements: reqs,
+				// AMIs that are selected by SSM parameter are held back from when the parameter was updated
+				if fromSSM {
+					ami.EligibleTime = resolvedAMI.EligibleTime
 				}
+				target := lo.Ternary(ami.IsPending(now), pendingImages, images)
+				// If the proposed image is newer, store it so that we can return it
+				if v, ok := target[reqsHash]; ok && !isNewer(ami, v) {
+					continue
+				}
+				target[reqsHash] = ami
 			}
 			return true
 		}); err != nil {
 			return nil, fmt.Errorf("describing images, %w", err)
 		}
 	}
-	p.cache.SetDefault(fmt.Sprintf("%t/%d", isNodeTemplate, hash), AMIs(lo.Values(images)))
-	return lo.Values(images), nil
+	res := AMIs(lo.Values(images))
+	for reqsHash, pending := range pendingImages {
+		if v, ok := images[reqsHash]; !ok || isNewer(pending, v) {
+			res = append(res, pending)
+		}
+	}
+	p.cache.SetDefault(key, res)
+	return res, nil
+}
+
+// ssmParameter is the name of an SSM parameter that's rendered from an AMISelectorTerm
+type ssmParameter struct {
+	Name              string
+	IncludeDeprecated bool
+}
+
+// renderSSMParameters renders the SSM parameter names of the AMISelectorTerms for each well known architecture.
+// Terms that don't use {{.Arch}} render to the same name for each architecture, so the names are deduplicated.
+func (p *Provider) renderSSMParameters(ctx context.Context, terms []v1beta1.AMISelectorTerm) ([]ssmParameter, error) {
+	if !lo.ContainsBy(terms, func(t v1beta1.AMISelectorTerm) bool { return t.SSMParameter != "" }) {
+		return nil, nil
+	}
+	kubernetesVersion, err := p.versionProvider.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting kubernetes version %w", err)
+	}
+	var res []ssmParameter
+	for _, term := range terms {
+		if term.SSMParameter == "" {
+			continue
+		}
+		tmpl, err := template.New("ssmParameter").Parse(term.SSMParameter)
+		if err != nil {
+			return nil, fmt.Errorf("parsing ssm parameter %q, %w", term.SSMParameter, err)
+		}
+		for _, arch := range v1beta1.WellKnownArchitectures.List() {
+			name := &bytes.Buffer{}
+			if err = tmpl.Execute(name, struct{ KubernetesVersion, Arch string }{KubernetesVersion: kubernetesVersion, Arch: arch}); err != nil {
+				return nil, fmt.Errorf("rendering ssm parameter %q, %w", term.SSMParameter, err)
+			}
+			res = append(res, ssmParameter{Name: name.String(), IncludeDeprecated: term.IncludeDeprecated})
+		}
+	}
+	return lo.Uniq(res), nil
+}
+
+type ssmAMI struct {
+	IncludeDeprecated bool
+	EligibleTime      time.Time
+}
+
+// ssmAMIs are the AMIs that SSM parameters resolve to, by id
+type ssmAMIs map[string]ssmAMI
+
+// resolveSSMParameters returns the AMIs that the SSM parameters resolve to. Parameters that don't exist are skipped,
+// since a parameter that's rendered for each architecture is commonly only published for some of them.
+func (p *Provider) resolveSSMParameters(ctx context.Context, parameters []ssmParameter, soakPeriod time.Duration) (ssmAMIs, error) {
+	res := ssmAMIs{}
+	for _, parameter := range parameters {
+		if soakPeriod > 0 {
+			eligibleID, pendingID, eligibleTime, err := p.resolveSoakedSSMParameter(ctx, parameter.Name, soakPeriod)
+			if awserrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if eligibleID != "" {
+				res[eligibleID] = ssmAMI{IncludeDeprecated: parameter.IncludeDeprecated}
+			}
+			if pendingID != "" {
+				res[pendingID] = ssmAMI{IncludeDeprecated: parameter.IncludeDeprecated, EligibleTime: eligibleTime}
+			}
+			continue
+		}
+		id, err := p.resolveSSMParameter(ctx, parameter.Name)
+		if awserrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		res[id] = ssmAMI{IncludeDeprecated: parameter.IncludeDeprecated}
+	}
+	return res, nil
+}
+
+// filterAndOwnerSets returns the filters that select the AMIs by id, separating the AMIs that may be deprecated
+func (s ssmAMIs) filterAndOwnerSets() (res []FiltersAndOwners) {
+	for _, includeDeprecated := range []bool{false, true} {
+		ids := lo.Keys(lo.PickBy(s, func(_ string, a ssmAMI) bool { return a.IncludeDeprecated == includeDeprecated }))
+		if len(ids) == 0 {
+			continue
+		}
+		sort.Strings(ids)
+		res = append(res, FiltersAndOwners{
+			Filters:           []*ec2.Filter{{Name: aws.String("image-id"), Values: aws.StringSlice(ids)}},
+			IncludeDeprecated: includeDeprecated,
+		})
+	}
+	return res
+}
+
+// getGPURequirement returns the GPU requirement of an AMI that's selected by SSM parameter from its gpu-support tag,
+// so that GPU instance types are only launched with AMIs that have GPU drivers. AMIs without the tag aren't constrained.
+func getGPURequirement(ec2Image *ec2.Image, isNodeTemplate bool) (*scheduling.Requirement, bool) {
+	tag, ok := lo.Find(ec2Image.Tags, func(t *ec2.Tag) bool { return aws.StringValue(t.Key) == v1beta1.TagAMIGPUSupport })
+	if !ok {
+		return nil, false
+	}
+	gpu, err := strconv.ParseBool(aws.StringValue(tag.Value))
+	if err != nil {
+		return nil, false
+	}
+	return scheduling.NewRequirement(lo.Ternary(isNodeTemplate, v1alpha1.LabelInstanceGPUCount, v1beta1.LabelInstanceGPUCount), lo.Ternary(gpu, v1.NodeSelectorOpExists, v1.NodeSelectorOpDoesNotExist)), true
+}
+
+// isNewer returns whether the candidate AMI was created after the existing AMI, using the name as a tie-breaker
+func isNewer(candidate, existing AMI) bool {
+	candidateCreationTime, _ := time.Parse(time.RFC3339, candidate.CreationDate)
+	existingCreationTime, _ := time.Parse(time.RFC3339, existing.CreationDate)
+	if existingCreationTime == candidateCreationTime && candidate.Name < existing.Name {
+		return false
+	}
+	return candidateCreationTime.Unix() >= existingCreationTime.Unix()
+}
+
+func soakPeriod(nodeClass *v1beta1.EC2NodeClass) time.Duration {
+	if nodeClass.Spec.AMISoakPeriod == nil {
+		return 0
+	}
+	return nodeClass.Spec.AMISoakPeriod.Duration
+}
+
+// isUsable returns whether an image can be launched, which it can't be once it's being deregistered. Deprecated
+// images can still be launched, but are only used if the selector term opted in to them.
+func isUsable(image *ec2.Image, includeDeprecated bool, now time.Time) bool {
+	if image.State != nil && aws.StringValue(image.State) != ec2.ImageStateAvailable {
+		return false
+	}
+	return includeDeprecated || !isDeprecated(aws.StringValue(image.DeprecationTime), now)
+}
+
+// IsDeprecated returns whether the image with the passed id has been deprecated. Deprecated images are no longer
+// selected by default, so this is used to explain why an image that was previously selected no longer is.
+func (p *Provider) IsDeprecated(ctx context.Context, id string) (bool, error) {
+	key := fmt.Sprintf("deprecated/%s", id)
+	if deprecated, ok := p.cache.Get(key); ok {
+		return deprecated.(bool), nil
+	}
+	out, err := p.ec2api.DescribeImagesWithContext(ctx, &ec2.DescribeImagesInput{
+		Filters:           []*ec2.Filter{{Name: aws.String("image-id"), Values: aws.StringSlice([]string{id})}},
+		IncludeDeprecated: aws.Bool(true),
+	})
+	if err != nil {
+		return false, fmt.Errorf("describing image %q, %w", id, err)
+	}
+	image, ok := lo.Find(out.Images, func(i *ec2.Image) bool { return aws.StringValue(i.ImageId) == id })
+	deprecated := ok && isDeprecated(aws.StringValue(image.DeprecationTime), time.Now())
+	p.cache.SetDefault(key, deprecated)
+	return deprecated, nil
 }
 
 type FiltersAndOwners struct {
-	Filters []*ec2.Filter
-	Owners  []string
+	Filters           []*ec2.Filter
+	Owners            []string
+	IncludeDeprecated bool
 }
 
 func GetFilterAndOwnerSets(terms []v1beta1.AMISelectorTerm) (res []FiltersAndOwners) {
 	idFilter := &ec2.Filter{Name: aws.String("image-id")}
+	includeDeprecatedIDFilter := &ec2.Filter{Name: aws.String("image-id")}
 	for _, term := range terms {
 		switch {
+		case term.SSMParameter != "":
+			// AMIs that are selected by SSM parameter are resolved to ids separately
+			continue
+		case term.ID != "" && term.IncludeDeprecated:
+			includeDeprecatedIDFilter.Values = append(includeDeprecatedIDFilter.Values, aws.String(term.ID))
 		case term.ID != "":
 			idFilter.Values = append(idFilter.Values, aws.String(term.ID))
 		default:
 			elem := FiltersAndOwners{
-				Owners: lo.Ternary(term.Owner != "", []string{term.Owner}, []string{"self", "amazon"}),
+				Owners:            lo.Ternary(term.Owner != "", []string{term.Owner}, []string{"self", "amazon"}),
+				IncludeDeprecated: term.IncludeDeprecated,
 			}
 			if term.Name != "" {
 				elem.Filters = append(elem.Filters, &ec2.Filter{
@@ -273,6 +642,9 @@ func GetFilterAndOwnerSets(terms []v1beta1.AMISelectorTerm) (res []FiltersAndOwn
 	if len(idFilter.Values) > 0 {
 		res = append(res, FiltersAndOwners{Filters: []*ec2.Filter{idFilter}})
 	}
+	if len(includeDeprecatedIDFilter.Values) > 0 {
+		res = append(res, FiltersAndOwners{Filters: []*ec2.Filter{includeDeprecatedIDFilter}, IncludeDeprecated: true})
+	}
 	return res
 }
 
@@ -23,10 +23,12 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ssm"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	. "knative.dev/pkg/logging/testing"
 
 	coresettings "github.com/aws/karpenter-core/pkg/apis/settings"
@@ -284,7 +286,346 @@ var _ = Describe("AMIProvider", func() {
 			}))
 		})
 	})
+	Context("SSM Parameter Selectors", func() {
+		BeforeEach(func() {
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{
+				{
+					Name:         aws.String("golden-amd64"),
+					ImageId:      aws.String("ami-golden-amd64"),
+					CreationDate: aws.String("2022-08-15T12:00:00Z"),
+					Architecture: aws.String("x86_64"),
+				},
+				{
+					Name:         aws.String("golden-arm64"),
+					ImageId:      aws.String("ami-golden-arm64"),
+					CreationDate: aws.String("2022-08-15T12:00:00Z"),
+					Architecture: aws.String("arm64"),
+				},
+				{
+					Name:         aws.String("golden-gpu-amd64"),
+					ImageId:      aws.String("ami-golden-gpu-amd64"),
+					CreationDate: aws.String("2022-08-15T12:00:00Z"),
+					Architecture: aws.String("x86_64"),
+					Tags:         []*ec2.Tag{{Key: aws.String(v1beta1.TagAMIGPUSupport), Value: aws.String("true")}},
+				},
+				{
+					Name:         aws.String("golden-cpu-amd64"),
+					ImageId:      aws.String("ami-golden-cpu-amd64"),
+					CreationDate: aws.String("2022-08-15T12:00:00Z"),
+					Architecture: aws.String("x86_64"),
+					Tags:         []*ec2.Tag{{Key: aws.String(v1beta1.TagAMIGPUSupport), Value: aws.String("false")}},
+				},
+				{
+					Name:         aws.String("golden-amd64-v2"),
+					ImageId:      aws.String("ami-golden-amd64-v2"),
+					CreationDate: aws.String("2022-08-16T12:00:00Z"),
+					Architecture: aws.String("x86_64"),
+				},
+			}})
+		})
+		It("should resolve an AMI for each architecture that the parameter is rendered for", func() {
+			awsEnv.SSMAPI.Parameters = map[string]string{
+				fmt.Sprintf("/golden-ami/%s/amd64/image_id", version): "ami-golden-amd64",
+				fmt.Sprintf("/golden-ami/%s/arm64/image_id", version): "ami-golden-arm64",
+			}
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{SSMParameter: "/golden-ami/{{.KubernetesVersion}}/{{.Arch}}/image_id"}}
+			amis, err := awsEnv.AMIProvider.Get(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(2))
+			ids := lo.Map(amis, func(a amifamily.AMI, _ int) string { return a.AmiID })
+			Expect(ids).To(ConsistOf("ami-golden-amd64", "ami-golden-arm64"))
+			for _, ami := range amis {
+				Expect(ami.Requirements.Get(v1.LabelArchStable).Values()).To(ConsistOf(lo.Ternary(ami.AmiID == "ami-golden-amd64", v1alpha5.ArchitectureAmd64, v1alpha5.ArchitectureArm64)))
+			}
+		})
+		It("should skip architectures that the parameter isn't published for", func() {
+			awsEnv.SSMAPI.Parameters = map[string]string{
+				fmt.Sprintf("/golden-ami/%s/amd64/image_id", version): "ami-golden-amd64",
+			}
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{SSMParameter: "/golden-ami/{{.KubernetesVersion}}/{{.Arch}}/image_id"}}
+			amis, err := awsEnv.AMIProvider.Get(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].AmiID).To(Equal("ami-golden-amd64"))
+		})
+		It("should fail to resolve AMIs when SSM returns an error", func() {
+			awsEnv.SSMAPI.WantErr = fmt.Errorf("failed")
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{SSMParameter: "/golden-ami/image_id"}}
+			_, err := awsEnv.AMIProvider.Get(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).To(HaveOccurred())
+		})
+		It("should infer GPU requirements from the gpu-support tag of the resolved AMI", func() {
+			awsEnv.SSMAPI.Parameters = map[string]string{
+				"/golden-ami/cpu/image_id": "ami-golden-cpu-amd64",
+				"/golden-ami/gpu/image_id": "ami-golden-gpu-amd64",
+			}
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
+				{SSMParameter: "/golden-ami/cpu/image_id"},
+				{SSMParameter: "/golden-ami/gpu/image_id"},
+			}
+			amis, err := awsEnv.AMIProvider.Get(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(2))
+			for _, ami := range amis {
+				Expect(ami.Requirements.Get(v1beta1.LabelInstanceGPUCount).Operator()).To(Equal(lo.Ternary(ami.AmiID == "ami-golden-gpu-amd64", v1.NodeSelectorOpExists, v1.NodeSelectorOpDoesNotExist)))
+			}
+		})
+		It("should not constrain GPU instance types for AMIs without the gpu-support tag", func() {
+			awsEnv.SSMAPI.Parameters = map[string]string{
+				"/golden-ami/image_id": "ami-golden-amd64",
+			}
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{SSMParameter: "/golden-ami/image_id"}}
+			amis, err := awsEnv.AMIProvider.Get(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].Requirements.Has(v1beta1.LabelInstanceGPUCount)).To(BeFalse())
+		})
+		It("should hold back an AMI until its parameter was updated for the soak period", func() {
+			nodeClass.Spec.AMISoakPeriod = &metav1.Duration{Duration: 24 * time.Hour}
+			updateTime := time.Now().Add(-time.Hour)
+			awsEnv.SSMAPI.ParameterHistory = map[string][]*ssm.ParameterHistory{
+				"/golden-ami/image_id": {
+					{Value: aws.String("ami-golden-amd64"), LastModifiedDate: aws.Time(time.Now().Add(-72 * time.Hour))},
+					{Value: aws.String("ami-golden-amd64-v2"), LastModifiedDate: aws.Time(updateTime)},
+				},
+			}
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{SSMParameter: "/golden-ami/image_id"}}
+			amis, err := awsEnv.AMIProvider.Get(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].AmiID).To(Equal("ami-golden-amd64"))
+			amis, err = awsEnv.AMIProvider.GetWithPending(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).ToNot(HaveOccurred())
+			pending, ok := lo.Find(amis, func(a amifamily.AMI) bool { return a.AmiID == "ami-golden-amd64-v2" })
+			Expect(ok).To(BeTrue())
+			Expect(pending.EligibleTime).To(BeTemporally("~", updateTime.Add(24*time.Hour), time.Second))
+		})
+		It("should not select AMIs by filters for SSM parameter terms", func() {
+			Expect(amifamily.GetFilterAndOwnerSets([]v1beta1.AMISelectorTerm{{SSMParameter: "/golden-ami/image_id"}})).To(BeEmpty())
+		})
+	})
+	Context("AMI Soak Period", func() {
+		var oldImg, newImg *ec2.Image
+		BeforeEach(func() {
+			nodeClass.Spec.AMISoakPeriod = &metav1.Duration{Duration: 24 * time.Hour}
+			oldImg = &ec2.Image{
+				Name:         aws.String("old-ami"),
+				ImageId:      aws.String("ami-old"),
+				CreationDate: aws.String(time.Now().Add(-72 * time.Hour).Format(time.RFC3339)),
+				Architecture: aws.String("x86_64"),
+				Tags:         []*ec2.Tag{{Key: aws.String("foo"), Value: aws.String("bar")}},
+			}
+			newImg = &ec2.Image{
+				Name:         aws.String("new-ami"),
+				ImageId:      aws.String("ami-new"),
+				CreationDate: aws.String(time.Now().Add(-time.Hour).Format(time.RFC3339)),
+				Architecture: aws.String("x86_64"),
+				Tags:         []*ec2.Tag{{Key: aws.String("foo"), Value: aws.String("bar")}},
+			}
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{oldImg, newImg}})
+		})
+		It("should not use AMIs that are still soaking", func() {
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Tags: map[string]string{"foo": "bar"}}}
+			amis, err := awsEnv.AMIProvider.Get(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].AmiID).To(Equal("ami-old"))
+		})
+		It("should return the AMIs that are still soaking with the time they become eligible", func() {
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Tags: map[string]string{"foo": "bar"}}}
+			amis, err := awsEnv.AMIProvider.GetWithPending(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(2))
+			Expect(amis[0].AmiID).To(Equal("ami-new"))
+			creationTime := lo.Must(time.Parse(time.RFC3339, aws.StringValue(newImg.CreationDate)))
+			Expect(amis[0].EligibleTime).To(Equal(creationTime.Add(24 * time.Hour)))
+			Expect(amis[1].AmiID).To(Equal("ami-old"))
+			Expect(amis[1].EligibleTime.IsZero()).To(BeTrue())
+		})
+		It("should use AMIs once they've soaked", func() {
+			nodeClass.Spec.AMISoakPeriod = &metav1.Duration{Duration: 30 * time.Minute}
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Tags: map[string]string{"foo": "bar"}}}
+			amis, err := awsEnv.AMIProvider.GetWithPending(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].AmiID).To(Equal("ami-new"))
+		})
+		It("should use AMIs that are pinned by id immediately", func() {
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{ID: "ami-new"}}
+			amis, err := awsEnv.AMIProvider.Get(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].AmiID).To(Equal("ami-new"))
+		})
+		It("should not use default AMIs until their SSM parameter was updated at least the soak period ago", func() {
+			version := lo.Must(awsEnv.VersionProvider.Get(ctx))
+			query := fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2/recommended/image_id", version)
+			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
+			awsEnv.SSMAPI.Parameters = map[string]string{query: "ami-new"}
+			updatedAt := time.Now().Add(-time.Hour)
+			awsEnv.SSMAPI.ParameterHistory = map[string][]*ssm.ParameterHistory{
+				query: {
+					{Name: aws.String(query), Value: aws.String("ami-old"), LastModifiedDate: aws.Time(time.Now().Add(-72 * time.Hour))},
+					{Name: aws.String(query), Value: aws.String("ami-new"), LastModifiedDate: aws.Time(updatedAt)},
+				},
+			}
+			amis, err := awsEnv.AMIProvider.Get(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].AmiID).To(Equal("ami-old"))
+
+			amis, err = awsEnv.AMIProvider.GetWithPending(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(2))
+			pending, ok := lo.Find(amis, func(a amifamily.AMI) bool { return a.AmiID == "ami-new" })
+			Expect(ok).To(BeTrue())
+			Expect(pending.EligibleTime).To(Equal(updatedAt.Add(24 * time.Hour)))
+		})
+	})
+	Context("AMI Deprecation", func() {
+		var img, deprecatedImg *ec2.Image
+		BeforeEach(func() {
+			img = &ec2.Image{
+				Name:         aws.String(amd64AMI),
+				ImageId:      aws.String("amd64-ami-id"),
+				CreationDate: aws.String(time.Now().Add(-time.Hour).Format(time.RFC3339)),
+				Architecture: aws.String("x86_64"),
+				State:        aws.String(ec2.ImageStateAvailable),
+				Tags:         []*ec2.Tag{{Key: aws.String("foo"), Value: aws.String("bar")}},
+			}
+			deprecatedImg = &ec2.Image{
+				Name:            aws.String(arm64AMI),
+				ImageId:         aws.String("arm64-ami-id"),
+				CreationDate:    aws.String(time.Now().Format(time.RFC3339)),
+				DeprecationTime: aws.String(time.Now().Add(-time.Hour).Format(time.RFC3339)),
+				Architecture:    aws.String("arm64"),
+				State:           aws.String(ec2.ImageStateAvailable),
+				Tags:            []*ec2.Tag{{Key: aws.String("foo"), Value: aws.String("bar")}},
+			}
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{img, deprecatedImg}})
+		})
+		It("should not resolve deprecated AMIs", func() {
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Tags: map[string]string{"foo": "bar"}}}
+			amis, err := awsEnv.AMIProvider.Get(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].AmiID).To(Equal(aws.StringValue(img.ImageId)))
+		})
+		It("should resolve deprecated AMIs when opted in", func() {
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Tags: map[string]string{"foo": "bar"}, IncludeDeprecated: true}}
+			amis, err := awsEnv.AMIProvider.Get(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(2))
+			Expect(lo.Map(amis, func(a amifamily.AMI, _ int) string { return a.AmiID })).To(ConsistOf(aws.StringValue(img.ImageId), aws.StringValue(deprecatedImg.ImageId)))
+		})
+		It("should resolve AMIs that will be deprecated in the future", func() {
+			deprecatedImg.DeprecationTime = aws.String(time.Now().Add(time.Hour).Format(time.RFC3339))
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Tags: map[string]string{"foo": "bar"}}}
+			amis, err := awsEnv.AMIProvider.Get(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(2))
+		})
+		It("should not resolve AMIs that aren't available", func() {
+			img.State = aws.String(ec2.ImageStateDeregistered)
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Tags: map[string]string{"foo": "bar"}, IncludeDeprecated: true}}
+			amis, err := awsEnv.AMIProvider.Get(ctx, nodeClass, &amifamily.Options{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].AmiID).To(Equal(aws.StringValue(deprecatedImg.ImageId)))
+		})
+		It("should report whether an AMI is deprecated", func() {
+			deprecated, err := awsEnv.AMIProvider.IsDeprecated(ctx, aws.StringValue(deprecatedImg.ImageId))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deprecated).To(BeTrue())
+			deprecated, err = awsEnv.AMIProvider.IsDeprecated(ctx, aws.StringValue(img.ImageId))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deprecated).To(BeFalse())
+		})
+	})
+	Context("AMI Rollout", func() {
+		var requirements scheduling.Requirements
+		BeforeEach(func() {
+			requirements = scheduling.NewRequirements(scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, v1alpha5.ArchitectureAmd64))
+			nodeClass.Spec.AMIRollout = &v1beta1.AMIRollout{
+				Steps:        []int32{25, 50},
+				StepDuration: metav1.Duration{Duration: time.Hour},
+			}
+			nodeClass.Status.AMIs = []v1beta1.AMI{{ID: "ami-new", Requirements: requirements.NodeSelectorRequirements()}}
+		})
+		It("should roll out the AMI to a share of NodeClaims that matches the current step", func() {
+			rollout := v1beta1.AMIRolloutStatus{ID: "ami-new", PreviousID: "ami-old"}
+			rolledOut := lo.CountBy(lo.Range(1000), func(i int) bool {
+				return amifamily.IsRolledOut(nodeClass, rollout, fmt.Sprintf("default-%d", i))
+			})
+			Expect(rolledOut).To(BeNumerically("~", 250, 50))
+		})
+		It("should keep the NodeClaims that the AMI is rolled out to as the rollout progresses", func() {
+			rollout := v1beta1.AMIRolloutStatus{ID: "ami-new", PreviousID: "ami-old"}
+			next := v1beta1.AMIRolloutStatus{ID: "ami-new", PreviousID: "ami-old", Step: 1}
+			for i := 0; i < 1000; i++ {
+				if name := fmt.Sprintf("default-%d", i); amifamily.IsRolledOut(nodeClass, rollout, name) {
+					Expect(amifamily.IsRolledOut(nodeClass, next, name)).To(BeTrue())
+				}
+			}
+		})
+		It("should use the previous AMI for every NodeClaim while the rollout is halted", func() {
+			nodeClass.Status.AMIRollouts = []v1beta1.AMIRolloutStatus{{ID: "ami-new", PreviousID: "ami-old", Halted: true}}
+			amis := amifamily.AMIs{{AmiID: "ami-new", Requirements: requirements}}
+			for i := 0; i < 100; i++ {
+				Expect(amis.ForNodeClaim(nodeClass, fmt.Sprintf("default-%d", i))[0].AmiID).To(Equal("ami-old"))
+			}
+		})
+		It("should keep the root device of the previous AMI while the rollout is halted", func() {
+			nodeClass.Status.AMIRollouts = []v1beta1.AMIRolloutStatus{{ID: "ami-new", PreviousID: "ami-old", PreviousRootDeviceName: "/dev/sda1", Halted: true}}
+			amis := amifamily.AMIs{{AmiID: "ami-new", RootDeviceName: "/dev/xvda", Requirements: requirements}}
+			ami := amis.ForNodeClaim(nodeClass, "default")[0]
+			Expect(ami.AmiID).To(Equal("ami-old"))
+			Expect(ami.RootDeviceName).To(Equal("/dev/sda1"))
+		})
+		It("should use the AMI for every NodeClaim once the rollout has completed all steps", func() {
+			nodeClass.Status.AMIRollouts = []v1beta1.AMIRolloutStatus{{ID: "ami-new", PreviousID: "ami-old", Step: 2}}
+			amis := amifamily.AMIs{{AmiID: "ami-new", Requirements: requirements}}
+			for i := 0; i < 100; i++ {
+				Expect(amis.ForNodeClaim(nodeClass, fmt.Sprintf("default-%d", i))[0].AmiID).To(Equal("ami-new"))
+			}
+		})
+		It("should use the AMI in the status until a newly resolved AMI has started its rollout", func() {
+			amis := amifamily.AMIs{{AmiID: "ami-newer", Requirements: requirements}}
+			Expect(amis.ForNodeClaim(nodeClass, "default")[0].AmiID).To(Equal("ami-new"))
+		})
+		It("should use the resolved AMI when no rollout is configured", func() {
+			nodeClass.Spec.AMIRollout = nil
+			amis := amifamily.AMIs{{AmiID: "ami-newer", Requirements: requirements}}
+			Expect(amis.ForNodeClaim(nodeClass, "default")[0].AmiID).To(Equal("ami-newer"))
+		})
+	})
 	Context("AMI Selectors", func() {
+		It("should split ids that include deprecated AMIs into their own set", func() {
+			amiSelectorTerms := []v1beta1.AMISelectorTerm{
+				{ID: "ami-1"},
+				{ID: "ami-2", IncludeDeprecated: true},
+			}
+			filterAndOwnersSets := amifamily.GetFilterAndOwnerSets(amiSelectorTerms)
+			ExpectConsistsOfFiltersAndOwners([]amifamily.FiltersAndOwners{
+				{
+					Filters: []*ec2.Filter{
+						{
+							Name:   aws.String("image-id"),
+							Values: aws.StringSlice([]string{"ami-1"}),
+						},
+					},
+				},
+				{
+					Filters: []*ec2.Filter{
+						{
+							Name:   aws.String("image-id"),
+							Values: aws.StringSlice([]string{"ami-2"}),
+						},
+					},
+					IncludeDeprecated: true,
+				},
+			}, filterAndOwnersSets)
+		})
 		It("should have default owners and use tags when prefixes aren't set", func() {
 			amiSelectorTerms := []v1beta1.AMISelectorTerm{
 				{
@@ -27,12 +27,14 @@ import (
 	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
 	corev1beta1 "github.com/aws/karpenter-core/pkg/apis/v1beta1"
 	"github.com/aws/karpenter-core/pkg/utils/resources"
+	"github.com/aws/karpenter/pkg/apis/v1beta1"
 )
 
 // Options is the node bootstrapping parameters passed from Karpenter to the provisioning node
 type Options struct {
 	ClusterName             string
 	ClusterEndpoint         string
+	ClusterCIDR             *string
 	KubeletConfig           *corev1beta1.KubeletConfiguration
 	Taints                  []core.Taint      `hash:"set"`
 	Labels                  map[string]string `hash:"set"`
@@ -40,6 +42,10 @@ type Options struct {
 	AWSENILimitedPodDensity bool
 	ContainerRuntime        *string
 	CustomUserData          *string
+	InstanceStorePolicy     *string
+	GPUSharing              *v1beta1.GPUSharing
+	NVIDIAGPUs              []string `hash:"set"`
+	Bottlerocket            *v1beta1.Bottlerocket
 }
 
 func (o Options) kubeletExtraArgs() (args []string) {
@@ -91,6 +97,11 @@ func (o Options) nodeLabelArg() string {
 	if len(o.Labels) == 0 {
 		return ""
 	}
+	return fmt.Sprintf("--node-labels=%q", strings.Join(o.nodeLabels(), ","))
+}
+
+// nodeLabels returns the labels that the kubelet registers the node with, as key=value pairs
+func (o Options) nodeLabels() []string {
 	var labelStrings []string
 	keys := lo.Keys(o.Labels)
 	sort.Strings(keys) // ensures this list is deterministic, for easy testing.
@@ -100,7 +111,7 @@ func (o Options) nodeLabelArg() string {
 		}
 		labelStrings = append(labelStrings, fmt.Sprintf("%s=%v", key, o.Labels[key]))
 	}
-	return fmt.Sprintf("--node-labels=%q", strings.Join(labelStrings, ","))
+	return labelStrings
 }
 
 // joinParameterArgs joins a map of keys and values by their separator. The separator will sit between the
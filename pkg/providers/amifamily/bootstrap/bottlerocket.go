@@ -23,12 +23,18 @@ import (
 
 	"github.com/imdario/mergo"
 	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/aws/karpenter-core/pkg/utils/resources"
 
 	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/aws/karpenter/pkg/apis/v1beta1"
 )
 
+// BottlerocketEphemeralStorageCommand is the name of the bootstrap command that sets up the instance-store disks
+const BottlerocketEphemeralStorageCommand = "000-karpenter-ephemeral-storage"
+
 type Bottlerocket struct {
 	Options
 }
@@ -39,6 +45,10 @@ func (b Bottlerocket) Script() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("invalid UserData %w", err)
 	}
+	// The typed Bottlerocket settings of the EC2NodeClass are merged over the settings of the custom UserData
+	b.mergeBottlerocketSettings(&s.Settings)
+	b.mergeGPUSharingSettings(&s.Settings)
+
 	// Karpenter will overwrite settings present inside custom UserData
 	// based on other fields specified in the provisioner
 	s.Settings.Kubernetes.ClusterName = &b.ClusterName
@@ -68,6 +78,15 @@ func (b Bottlerocket) Script() (string, error) {
 		if b.KubeletConfig.EvictionHard != nil {
 			s.Settings.Kubernetes.EvictionHard = b.KubeletConfig.EvictionHard
 		}
+		if b.KubeletConfig.EvictionSoft != nil {
+			s.Settings.Kubernetes.EvictionSoft = b.KubeletConfig.EvictionSoft
+		}
+		if b.KubeletConfig.EvictionSoftGracePeriod != nil {
+			s.Settings.Kubernetes.EvictionSoftGracePeriod = lo.MapValues(b.KubeletConfig.EvictionSoftGracePeriod, func(v metav1.Duration, _ string) string { return v.Duration.String() })
+		}
+		if b.KubeletConfig.EvictionMaxPodGracePeriod != nil {
+			s.Settings.Kubernetes.EvictionMaxPodGracePeriod = aws.Int(int(ptr.Int32Value(b.KubeletConfig.EvictionMaxPodGracePeriod)))
+		}
 		if b.KubeletConfig.ImageGCHighThresholdPercent != nil {
 			s.Settings.Kubernetes.ImageGCHighThresholdPercent = lo.ToPtr(strconv.FormatInt(int64(*b.KubeletConfig.ImageGCHighThresholdPercent), 10))
 		}
@@ -79,6 +98,22 @@ func (b Bottlerocket) Script() (string, error) {
 		}
 	}
 
+	if lo.FromPtr(b.InstanceStorePolicy) == v1beta1.InstanceStorePolicyRAID0 {
+		if s.Settings.BootstrapCommands == nil {
+			s.Settings.BootstrapCommands = map[string]BottlerocketBootstrapCommand{}
+		}
+		// Bottlerocket assembles the instance-store disks into a RAID0 array and bind mounts the directories of the
+		// kubelet and container runtime onto it
+		s.Settings.BootstrapCommands[BottlerocketEphemeralStorageCommand] = BottlerocketBootstrapCommand{
+			Commands: [][]string{
+				{"apiclient", "ephemeral-storage", "init"},
+				{"apiclient", "ephemeral-storage", "bind", "--dirs", "/var/lib/containerd", "/var/lib/kubelet", "/var/log/pods"},
+			},
+			Mode:      "always",
+			Essential: true,
+		}
+	}
+
 	s.Settings.Kubernetes.NodeTaints = map[string][]string{}
 	for _, taint := range b.Taints {
 		s.Settings.Kubernetes.NodeTaints[taint.Key] = append(s.Settings.Kubernetes.NodeTaints[taint.Key], fmt.Sprintf("%s:%s", taint.Value, taint.Effect))
@@ -89,3 +124,83 @@ func (b Bottlerocket) Script() (string, error) {
 	}
 	return base64.StdEncoding.EncodeToString(script), nil
 }
+
+// mergeBottlerocketSettings merges the typed Bottlerocket settings of the EC2NodeClass over the settings of the custom
+// UserData. Only the fields that are set on the EC2NodeClass overwrite the fields of a container in the UserData.
+func (b Bottlerocket) mergeBottlerocketSettings(settings *BottlerocketSettings) {
+	if b.Options.Bottlerocket == nil {
+		return
+	}
+	if len(b.Options.Bottlerocket.BootstrapContainers) > 0 && settings.BootstrapContainers == nil {
+		settings.BootstrapContainers = map[string]BottlerocketBootstrapContainer{}
+	}
+	for name, container := range b.Options.Bottlerocket.BootstrapContainers {
+		merged := settings.BootstrapContainers[name]
+		merged.Source = lo.ToPtr(container.Source)
+		if container.Mode != nil {
+			merged.Mode = container.Mode
+		} else if merged.Mode == nil {
+			merged.Mode = lo.ToPtr(v1beta1.BottlerocketContainerModeAlways)
+		}
+		if container.Essential != nil {
+			merged.Essential = container.Essential
+		}
+		if container.UserData != nil {
+			merged.UserData = lo.ToPtr(base64.StdEncoding.EncodeToString([]byte(*container.UserData)))
+		}
+		settings.BootstrapContainers[name] = merged
+	}
+	if len(b.Options.Bottlerocket.HostContainers) > 0 && settings.HostContainers == nil {
+		settings.HostContainers = map[string]BottlerocketHostContainer{}
+	}
+	for name, container := range b.Options.Bottlerocket.HostContainers {
+		merged := settings.HostContainers[name]
+		if container.Source != nil {
+			merged.Source = container.Source
+		}
+		if container.Enabled != nil {
+			merged.Enabled = container.Enabled
+		}
+		if container.Superpowered != nil {
+			merged.Superpowered = container.Superpowered
+		}
+		if container.UserData != nil {
+			merged.UserData = lo.ToPtr(base64.StdEncoding.EncodeToString([]byte(*container.UserData)))
+		}
+		settings.HostContainers[name] = merged
+	}
+	if b.Options.Bottlerocket.KernelLockdown != nil {
+		settings.Kernel = &BottlerocketKernel{Lockdown: b.Options.Bottlerocket.KernelLockdown}
+	}
+}
+
+// mergeGPUSharingSettings configures the NVIDIA device plugin of Bottlerocket to share the GPUs of the node. Options
+// only carry the GPUSharing of the EC2NodeClass when the instance types of the launch template share NVIDIA GPUs.
+func (b Bottlerocket) mergeGPUSharingSettings(settings *BottlerocketSettings) {
+	// The device plugin settings of the UserData are passed through the raw settings, only the sharing settings that
+	// are set here are merged over them
+	settings.KubeletDevicePlugins = nil
+	if b.Options.GPUSharing == nil {
+		return
+	}
+	nvidia := &BottlerocketNVIDIADevicePlugin{}
+	switch b.Options.GPUSharing.Strategy {
+	case v1beta1.GPUSharingStrategyTimeSlicing:
+		nvidia.DeviceSharingStrategy = lo.ToPtr("time-slicing")
+		// Renaming the replicas to nvidia.com/gpu.shared would hide them from pods that request nvidia.com/gpu
+		nvidia.TimeSlicing = &BottlerocketNVIDIATimeSlicing{
+			Replicas:        lo.ToPtr(int(lo.FromPtr(b.Options.GPUSharing.Replicas))),
+			RenameByDefault: lo.ToPtr(false),
+		}
+	case v1beta1.GPUSharingStrategyMIG:
+		nvidia.DeviceSharingStrategy = lo.ToPtr("mig")
+		nvidia.MIG = &BottlerocketNVIDIAMIG{
+			Profile: lo.SliceToMap(b.Options.NVIDIAGPUs, func(gpu string) (string, string) {
+				return gpu, lo.FromPtr(b.Options.GPUSharing.MIGProfile)
+			}),
+		}
+	default:
+		return
+	}
+	settings.KubeletDevicePlugins = &BottlerocketKubeletDevicePlugins{NVIDIA: nvidia}
+}
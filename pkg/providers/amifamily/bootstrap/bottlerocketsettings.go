@@ -38,7 +38,12 @@ type BottlerocketConfig struct {
 // BottlerocketSettings is a subset of all configuration in https://github.com/bottlerocket-os/bottlerocket/blob/develop/sources/models/src/aws-k8s-1.22/mod.rs
 // These settings apply across all K8s versions that karpenter supports.
 type BottlerocketSettings struct {
-	Kubernetes BottlerocketKubernetes `toml:"kubernetes"`
+	Kubernetes           BottlerocketKubernetes                    `toml:"kubernetes"`
+	BootstrapCommands    map[string]BottlerocketBootstrapCommand   `toml:"bootstrap-commands,omitempty"`
+	BootstrapContainers  map[string]BottlerocketBootstrapContainer `toml:"bootstrap-containers,omitempty"`
+	HostContainers       map[string]BottlerocketHostContainer      `toml:"host-containers,omitempty"`
+	Kernel               *BottlerocketKernel                       `toml:"kernel,omitempty"`
+	KubeletDevicePlugins *BottlerocketKubeletDevicePlugins         `toml:"kubelet-device-plugins,omitempty"`
 }
 
 // BottlerocketKubernetes is k8s specific configuration for bottlerocket api
@@ -53,6 +58,9 @@ type BottlerocketKubernetes struct {
 	MaxPods                            *int                             `toml:"max-pods,omitempty"`
 	StaticPods                         map[string]BottlerocketStaticPod `toml:"static-pods,omitempty"`
 	EvictionHard                       map[string]string                `toml:"eviction-hard,omitempty"`
+	EvictionSoft                       map[string]string                `toml:"eviction-soft,omitempty"`
+	EvictionSoftGracePeriod            map[string]string                `toml:"eviction-soft-grace-period,omitempty"`
+	EvictionMaxPodGracePeriod          *int                             `toml:"eviction-max-pod-grace-period,omitempty"`
 	KubeReserved                       map[string]string                `toml:"kube-reserved,omitempty"`
 	SystemReserved                     map[string]string                `toml:"system-reserved,omitempty"`
 	AllowedUnsafeSysctls               []string                         `toml:"allowed-unsafe-sysctls,omitempty"`
@@ -75,6 +83,62 @@ type BottlerocketKubernetes struct {
 	ShutdownGracePeriodForCriticalPods *string                          `toml:"shutdown-grace-period-for-critical-pods,omitempty"`
 }
 
+// BottlerocketBootstrapCommand is a command that is run by Bottlerocket before the kubelet starts, see more here
+// https://bottlerocket.dev/en/os/latest/api/settings/bootstrap-commands/
+type BottlerocketBootstrapCommand struct {
+	Commands  [][]string `toml:"commands"`
+	Mode      string     `toml:"mode"`
+	Essential bool       `toml:"essential"`
+}
+
+// BottlerocketBootstrapContainer is a container that is run by Bottlerocket before the kubelet starts, see more here
+// https://bottlerocket.dev/en/os/latest/api/settings/bootstrap-containers/
+type BottlerocketBootstrapContainer struct {
+	Source    *string `toml:"source,omitempty"`
+	Mode      *string `toml:"mode,omitempty"`
+	Essential *bool   `toml:"essential,omitempty"`
+	UserData  *string `toml:"user-data,omitempty"`
+}
+
+// BottlerocketHostContainer is a container that is run by Bottlerocket alongside the container runtime of the host, see
+// more here https://bottlerocket.dev/en/os/latest/api/settings/host-containers/
+type BottlerocketHostContainer struct {
+	Source       *string `toml:"source,omitempty"`
+	Enabled      *bool   `toml:"enabled,omitempty"`
+	Superpowered *bool   `toml:"superpowered,omitempty"`
+	UserData     *string `toml:"user-data,omitempty"`
+}
+
+// BottlerocketKernel is the subset of the kernel settings that Karpenter sets. The other kernel settings of the
+// UserData, like sysctls, are passed through untouched.
+type BottlerocketKernel struct {
+	Lockdown *string `toml:"lockdown,omitempty"`
+}
+
+// BottlerocketKubeletDevicePlugins configures the device plugins of the kubelet, see more here
+// https://bottlerocket.dev/en/os/latest/api/settings/kubelet-device-plugins/
+type BottlerocketKubeletDevicePlugins struct {
+	NVIDIA *BottlerocketNVIDIADevicePlugin `toml:"nvidia,omitempty"`
+}
+
+// BottlerocketNVIDIADevicePlugin is the subset of the settings of the NVIDIA device plugin that Karpenter sets. The
+// other settings of the UserData, like pass-device-specs, are passed through untouched.
+type BottlerocketNVIDIADevicePlugin struct {
+	DeviceSharingStrategy *string                        `toml:"device-sharing-strategy,omitempty"`
+	TimeSlicing           *BottlerocketNVIDIATimeSlicing `toml:"time-slicing,omitempty"`
+	MIG                   *BottlerocketNVIDIAMIG         `toml:"mig,omitempty"`
+}
+
+type BottlerocketNVIDIATimeSlicing struct {
+	Replicas        *int  `toml:"replicas,omitempty"`
+	RenameByDefault *bool `toml:"rename-by-default,omitempty"`
+}
+
+// BottlerocketNVIDIAMIG maps the GPUs, such as a100.40gb, to the MIG profile that each GPU is partitioned with
+type BottlerocketNVIDIAMIG struct {
+	Profile map[string]string `toml:"profile,omitempty"`
+}
+
 type BottlerocketStaticPod struct {
 	Enabled  *bool   `toml:"enabled,omitempty"`
 	Manifest *string `toml:"manifest,omitempty"`
@@ -101,5 +165,43 @@ func (c *BottlerocketConfig) MarshalTOML() ([]byte, error) {
 		c.SettingsRaw = map[string]interface{}{}
 	}
 	c.SettingsRaw["kubernetes"] = c.Settings.Kubernetes
+	if len(c.Settings.BootstrapCommands) > 0 {
+		c.SettingsRaw["bootstrap-commands"] = c.Settings.BootstrapCommands
+	}
+	if len(c.Settings.BootstrapContainers) > 0 {
+		c.SettingsRaw["bootstrap-containers"] = c.Settings.BootstrapContainers
+	}
+	if len(c.Settings.HostContainers) > 0 {
+		c.SettingsRaw["host-containers"] = c.Settings.HostContainers
+	}
+	if c.Settings.Kernel != nil && c.Settings.Kernel.Lockdown != nil {
+		kernel, ok := c.SettingsRaw["kernel"].(map[string]interface{})
+		if !ok {
+			kernel = map[string]interface{}{}
+		}
+		kernel["lockdown"] = *c.Settings.Kernel.Lockdown
+		c.SettingsRaw["kernel"] = kernel
+	}
+	if c.Settings.KubeletDevicePlugins != nil && c.Settings.KubeletDevicePlugins.NVIDIA != nil {
+		devicePlugins, ok := c.SettingsRaw["kubelet-device-plugins"].(map[string]interface{})
+		if !ok {
+			devicePlugins = map[string]interface{}{}
+		}
+		nvidia, ok := devicePlugins["nvidia"].(map[string]interface{})
+		if !ok {
+			nvidia = map[string]interface{}{}
+		}
+		if c.Settings.KubeletDevicePlugins.NVIDIA.DeviceSharingStrategy != nil {
+			nvidia["device-sharing-strategy"] = *c.Settings.KubeletDevicePlugins.NVIDIA.DeviceSharingStrategy
+		}
+		if c.Settings.KubeletDevicePlugins.NVIDIA.TimeSlicing != nil {
+			nvidia["time-slicing"] = c.Settings.KubeletDevicePlugins.NVIDIA.TimeSlicing
+		}
+		if c.Settings.KubeletDevicePlugins.NVIDIA.MIG != nil {
+			nvidia["mig"] = c.Settings.KubeletDevicePlugins.NVIDIA.MIG
+		}
+		devicePlugins["nvidia"] = nvidia
+		c.SettingsRaw["kubelet-device-plugins"] = devicePlugins
+	}
 	return toml.Marshal(c)
 }
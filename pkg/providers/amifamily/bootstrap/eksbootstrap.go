@@ -28,6 +28,8 @@ import (
 	"strings"
 
 	"github.com/samber/lo"
+
+	"github.com/aws/karpenter/pkg/apis/v1beta1"
 )
 
 type EKS struct {
@@ -39,8 +41,35 @@ const (
 	Boundary                      = "//"
 	MIMEVersionHeader             = "MIME-Version: 1.0"
 	MIMEContentTypeHeaderTemplate = "Content-Type: multipart/mixed; boundary=\"%s\""
+
+	shellScriptContentType = `text/x-shellscript; charset="us-ascii"`
+	cloudConfigContentType = `text/cloud-config; charset="us-ascii"`
+	// cloudConfigMergeType makes cloud-init append the lists and merge the dicts of multiple cloud-config parts, rather
+	// than replacing the keys of the earlier parts with the keys of the later parts
+	cloudConfigMergeType = "list(append)+dict(no_replace,recurse_list)+str()"
 )
 
+// userDataFormats are the cloud-init content types of user data, keyed by the start of their first line, see
+// https://cloudinit.readthedocs.io/en/latest/explanation/format.html. Longer prefixes come first, so that
+// #include-once isn't detected as #include.
+var userDataFormats = []struct {
+	prefix      string
+	contentType string
+}{
+	{"#!", shellScriptContentType},
+	{"#cloud-config", cloudConfigContentType},
+	{"#cloud-boothook", `text/cloud-boothook; charset="us-ascii"`},
+	{"#include-once", `text/x-include-once-url; charset="us-ascii"`},
+	{"#include", `text/x-include-url; charset="us-ascii"`},
+	{"#part-handler", `text/part-handler; charset="us-ascii"`},
+	{"## template: jinja", `text/jinja2; charset="us-ascii"`},
+}
+
+type mimePart struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
 func (e EKS) Script() (string, error) {
 	userData, err := e.mergeCustomUserData(lo.Compact([]string{lo.FromPtr(e.CustomUserData), e.eksBootstrapScript()})...)
 	if err != nil {
@@ -78,6 +107,9 @@ func (e EKS) eksBootstrapScript() string {
 	if args := e.kubeletExtraArgs(); len(args) > 0 {
 		userData.WriteString(fmt.Sprintf(" \\\n--kubelet-extra-args '%s'", strings.Join(args, " ")))
 	}
+	if lo.FromPtr(e.InstanceStorePolicy) == v1beta1.InstanceStorePolicyRAID0 {
+		userData.WriteString(" \\\n--local-disks raid0")
+	}
 	return userData.String()
 }
 
@@ -93,6 +125,36 @@ func (e EKS) kubeletExtraArgs() []string {
 }
 
 func (e EKS) mergeCustomUserData(userDatas ...string) (string, error) {
+	var mimedUserDatas []string
+	for _, userData := range userDatas {
+		mimedUserData, err := mimeify(userData, userDataContentType(userData))
+		if err != nil {
+			return "", err
+		}
+		mimedUserDatas = append(mimedUserDatas, mimedUserData)
+	}
+	return mergeMIMEUserData(mimedUserDatas...)
+}
+
+// mergeMIMEUserData merges the parts of each of the user data in mime format into a single mime document. When the
+// document has multiple cloud-config parts, the parts that don't declare how cloud-init merges them are merged
+// with cloudConfigMergeType.
+func mergeMIMEUserData(mimedUserDatas ...string) (string, error) {
+	var parts []mimePart
+	for _, mimedUserData := range mimedUserDatas {
+		userDataParts, err := readMIMEParts(mimedUserData)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, userDataParts...)
+	}
+	if cloudConfigs := lo.Filter(parts, func(p mimePart, _ int) bool { return isCloudConfig(p.header) }); len(cloudConfigs) > 1 {
+		for _, cloudConfig := range cloudConfigs {
+			if cloudConfig.header.Get("Merge-Type") == "" {
+				cloudConfig.header.Set("Merge-Type", cloudConfigMergeType)
+			}
+		}
+	}
 	var outputBuffer bytes.Buffer
 	writer := multipart.NewWriter(&outputBuffer)
 	if err := writer.SetBoundary(Boundary); err != nil {
@@ -100,13 +162,13 @@ func (e EKS) mergeCustomUserData(userDatas ...string) (string, error) {
 	}
 	outputBuffer.WriteString(MIMEVersionHeader + "\n")
 	outputBuffer.WriteString(fmt.Sprintf(MIMEContentTypeHeaderTemplate, Boundary) + "\n\n")
-	for _, userData := range userDatas {
-		mimedUserData, err := e.mimeify(userData)
+	for _, part := range parts {
+		partWriter, err := writer.CreatePart(part.header)
 		if err != nil {
-			return "", err
+			return "", fmt.Errorf("writing merged user data %w", err)
 		}
-		if err := copyCustomUserDataParts(writer, mimedUserData); err != nil {
-			return "", err
+		if _, err = partWriter.Write(part.body); err != nil {
+			return "", fmt.Errorf("writing merged user data %w", err)
 		}
 	}
 	writer.Close()
@@ -120,9 +182,26 @@ func (e EKS) isIPv6() bool {
 	return net.ParseIP(e.KubeletConfig.ClusterDNS[0]).To4() == nil
 }
 
-// mimeify returns userData in a mime format
+// userDataContentType returns the cloud-init content type of user data that isn't in a mime format, which is detected
+// from its first line. User data of an unknown format is assumed to be a shell script.
+func userDataContentType(userData string) string {
+	firstLine, _, _ := strings.Cut(strings.TrimSpace(userData), "\n")
+	for _, format := range userDataFormats {
+		if strings.HasPrefix(firstLine, format.prefix) {
+			return format.contentType
+		}
+	}
+	return shellScriptContentType
+}
+
+func isCloudConfig(header textproto.MIMEHeader) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	return err == nil && mediaType == "text/cloud-config"
+}
+
+// mimeify returns userData in a mime format, as a single part of the passed content type
 // if the userData passed in is already in a mime format, then the input is returned without modification
-func (e EKS) mimeify(customUserData string) (string, error) {
+func mimeify(customUserData string, contentType string) (string, error) {
 	if strings.HasPrefix(strings.TrimSpace(customUserData), "MIME-Version:") ||
 		strings.HasPrefix(strings.TrimSpace(customUserData), "Content-Type:") {
 		return customUserData, nil
@@ -132,7 +211,7 @@ func (e EKS) mimeify(customUserData string) (string, error) {
 	outputBuffer.WriteString(MIMEVersionHeader + "\n")
 	outputBuffer.WriteString(fmt.Sprintf(MIMEContentTypeHeaderTemplate, writer.Boundary()) + "\n\n")
 	partWriter, err := writer.CreatePart(textproto.MIMEHeader{
-		"Content-Type": []string{`text/x-shellscript; charset="us-ascii"`},
+		"Content-Type": []string{contentType},
 	})
 	if err != nil {
 		return "", fmt.Errorf("creating multi-part section from custom user-data: %w", err)
@@ -145,39 +224,32 @@ func (e EKS) mimeify(customUserData string) (string, error) {
 	return outputBuffer.String(), nil
 }
 
-// copyCustomUserDataParts reads the mime parts in the userData passed in and writes
-// to a new mime part in the passed in writer.
-func copyCustomUserDataParts(writer *multipart.Writer, customUserData string) error {
+// readMIMEParts reads the mime parts in the userData passed in
+func readMIMEParts(customUserData string) ([]mimePart, error) {
 	if customUserData == "" {
-		// No custom user data specified, so nothing to copy over.
-		return nil
+		// No custom user data specified, so there are no parts.
+		return nil, nil
 	}
 	reader, err := getMultiPartReader(customUserData)
 	if err != nil {
-		return fmt.Errorf("parsing custom user data input %w", err)
+		return nil, fmt.Errorf("parsing custom user data input %w", err)
 	}
+	var parts []mimePart
 	for {
 		p, err := reader.NextPart()
 		if errors.Is(err, io.EOF) {
 			break
 		}
 		if err != nil {
-			return fmt.Errorf("parsing custom user data input %w", err)
+			return nil, fmt.Errorf("parsing custom user data input %w", err)
 		}
 		slurp, err := io.ReadAll(p)
 		if err != nil {
-			return fmt.Errorf("parsing custom user data input %w", err)
-		}
-		partWriter, err := writer.CreatePart(p.Header)
-		if err != nil {
-			return fmt.Errorf("parsing custom user data input %w", err)
-		}
-		_, err = partWriter.Write(slurp)
-		if err != nil {
-			return fmt.Errorf("parsing custom user data input %w", err)
+			return nil, fmt.Errorf("parsing custom user data input %w", err)
 		}
+		parts = append(parts, mimePart{header: p.Header, body: slurp})
 	}
-	return nil
+	return parts, nil
 }
 
 func getMultiPartReader(userData string) (*multipart.Reader, error) {
@@ -0,0 +1,189 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/karpenter-core/pkg/utils/resources"
+	"github.com/aws/karpenter/pkg/apis/v1beta1"
+)
+
+const (
+	nodeConfigAPIVersion  = "node.eks.aws/v1alpha1"
+	nodeConfigKind        = "NodeConfig"
+	nodeConfigContentType = "application/node.eks.aws"
+)
+
+// Nodeadm bootstraps nodes with a nodeadm NodeConfig, which is how AL2023 joins nodes to the cluster
+type Nodeadm struct {
+	Options
+}
+
+// NodeConfig is the configuration that nodeadm reads from the user data
+// https://awslabs.github.io/amazon-eks-ami/nodeadm/doc/api/
+type NodeConfig struct {
+	APIVersion string         `json:"apiVersion"`
+	Kind       string         `json:"kind"`
+	Spec       NodeConfigSpec `json:"spec"`
+}
+
+type NodeConfigSpec struct {
+	Cluster  ClusterDetails   `json:"cluster"`
+	Kubelet  KubeletOptions   `json:"kubelet,omitempty"`
+	Instance *InstanceOptions `json:"instance,omitempty"`
+}
+
+type ClusterDetails struct {
+	Name                 string `json:"name"`
+	APIServerEndpoint    string `json:"apiServerEndpoint"`
+	CertificateAuthority string `json:"certificateAuthority,omitempty"`
+	CIDR                 string `json:"cidr"`
+}
+
+type KubeletOptions struct {
+	// Config is merged into the KubeletConfiguration that nodeadm generates
+	Config *KubeletConfiguration `json:"config,omitempty"`
+	Flags  []string              `json:"flags,omitempty"`
+}
+
+// KubeletConfiguration holds the fields of the KubeletConfiguration that Karpenter configures
+type KubeletConfiguration struct {
+	ClusterDNS                  []string          `json:"clusterDNS,omitempty"`
+	MaxPods                     *int32            `json:"maxPods,omitempty"`
+	PodsPerCore                 *int32            `json:"podsPerCore,omitempty"`
+	SystemReserved              map[string]string `json:"systemReserved,omitempty"`
+	KubeReserved                map[string]string `json:"kubeReserved,omitempty"`
+	EvictionHard                map[string]string `json:"evictionHard,omitempty"`
+	EvictionSoft                map[string]string `json:"evictionSoft,omitempty"`
+	EvictionSoftGracePeriod     map[string]string `json:"evictionSoftGracePeriod,omitempty"`
+	EvictionMaxPodGracePeriod   *int32            `json:"evictionMaxPodGracePeriod,omitempty"`
+	ImageGCHighThresholdPercent *int32            `json:"imageGCHighThresholdPercent,omitempty"`
+	ImageGCLowThresholdPercent  *int32            `json:"imageGCLowThresholdPercent,omitempty"`
+	CPUCFSQuota                 *bool             `json:"cpuCFSQuota,omitempty"`
+	RegisterWithTaints          []core.Taint      `json:"registerWithTaints,omitempty"`
+}
+
+type InstanceOptions struct {
+	LocalStorage *LocalStorageOptions `json:"localStorage,omitempty"`
+}
+
+type LocalStorageOptions struct {
+	Strategy string `json:"strategy"`
+}
+
+// Script returns the NodeConfig in a mime document, after the custom user data. nodeadm merges the NodeConfigs in
+// the order they appear, so the values that Karpenter configures take precedence over the custom user data.
+func (n Nodeadm) Script() (string, error) {
+	nodeConfig, err := n.nodeConfig()
+	if err != nil {
+		return "", err
+	}
+	var mimedUserDatas []string
+	if customUserData := lo.FromPtr(n.CustomUserData); customUserData != "" {
+		mimedUserData, err := mimeify(customUserData, lo.Ternary(isNodeConfig(customUserData), nodeConfigContentType, userDataContentType(customUserData)))
+		if err != nil {
+			return "", err
+		}
+		mimedUserDatas = append(mimedUserDatas, mimedUserData)
+	}
+	mimedNodeConfig, err := mimeify(nodeConfig, nodeConfigContentType)
+	if err != nil {
+		return "", err
+	}
+	userData, err := mergeMIMEUserData(append(mimedUserDatas, mimedNodeConfig)...)
+	if err != nil {
+		return "", err
+	}
+	// The mime/multipart package adds carriage returns, while the rest of our logic does not. Remove all
+	// carriage returns for consistency.
+	return base64.StdEncoding.EncodeToString([]byte(strings.ReplaceAll(userData, "\r", ""))), nil
+}
+
+func (n Nodeadm) nodeConfig() (string, error) {
+	// nodeadm refuses to join a node to the cluster without the service cidr
+	if n.ClusterCIDR == nil {
+		return "", fmt.Errorf("resolving cluster cidr, the cluster cidr is required to bootstrap nodes with nodeadm")
+	}
+	config := NodeConfig{
+		APIVersion: nodeConfigAPIVersion,
+		Kind:       nodeConfigKind,
+		Spec: NodeConfigSpec{
+			Cluster: ClusterDetails{
+				Name:                 n.ClusterName,
+				APIServerEndpoint:    n.ClusterEndpoint,
+				CertificateAuthority: lo.FromPtr(n.CABundle),
+				CIDR:                 lo.FromPtr(n.ClusterCIDR),
+			},
+			Kubelet: KubeletOptions{
+				Config: n.kubeletConfig(),
+			},
+		},
+	}
+	if labels := n.nodeLabels(); len(labels) > 0 {
+		config.Spec.Kubelet.Flags = append(config.Spec.Kubelet.Flags, fmt.Sprintf("--node-labels=%s", strings.Join(labels, ",")))
+	}
+	if lo.FromPtr(n.InstanceStorePolicy) == v1beta1.InstanceStorePolicyRAID0 {
+		config.Spec.Instance = &InstanceOptions{LocalStorage: &LocalStorageOptions{Strategy: "RAID0"}}
+	}
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("marshaling node config, %w", err)
+	}
+	return string(out), nil
+}
+
+func (n Nodeadm) kubeletConfig() *KubeletConfiguration {
+	config := &KubeletConfiguration{RegisterWithTaints: n.Taints}
+	// Set the static value for maxPods to 110 when AWSENILimitedPodDensity is explicitly disabled and the value isn't set
+	if !n.AWSENILimitedPodDensity {
+		config.MaxPods = lo.ToPtr(int32(110))
+	}
+	if n.KubeletConfig == nil {
+		return config
+	}
+	config.ClusterDNS = n.KubeletConfig.ClusterDNS
+	if n.KubeletConfig.MaxPods != nil {
+		config.MaxPods = n.KubeletConfig.MaxPods
+	}
+	config.PodsPerCore = n.KubeletConfig.PodsPerCore
+	// We have to convert some of these maps so that their values return the correct string
+	config.SystemReserved = resources.StringMap(n.KubeletConfig.SystemReserved)
+	config.KubeReserved = resources.StringMap(n.KubeletConfig.KubeReserved)
+	config.EvictionHard = n.KubeletConfig.EvictionHard
+	config.EvictionSoft = n.KubeletConfig.EvictionSoft
+	config.EvictionSoftGracePeriod = lo.MapValues(n.KubeletConfig.EvictionSoftGracePeriod, func(v metav1.Duration, _ string) string { return v.Duration.String() })
+	config.EvictionMaxPodGracePeriod = n.KubeletConfig.EvictionMaxPodGracePeriod
+	config.ImageGCHighThresholdPercent = n.KubeletConfig.ImageGCHighThresholdPercent
+	config.ImageGCLowThresholdPercent = n.KubeletConfig.ImageGCLowThresholdPercent
+	config.CPUCFSQuota = n.KubeletConfig.CPUCFSQuota
+	return config
+}
+
+// isNodeConfig returns whether the custom user data is a NodeConfig, rather than a script
+func isNodeConfig(userData string) bool {
+	typeMeta := metav1.TypeMeta{}
+	if err := yaml.Unmarshal([]byte(userData), &typeMeta); err != nil {
+		return false
+	}
+	return typeMeta.Kind == nodeConfigKind && strings.HasPrefix(typeMeta.APIVersion, "node.eks.aws/")
+}
@@ -16,6 +16,7 @@ package amifamily
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/samber/lo"
 
@@ -33,6 +34,10 @@ import (
 	"github.com/aws/karpenter/pkg/apis/v1alpha1"
 )
 
+func init() {
+	Register(v1beta1.AMIFamilyBottlerocket, func(options *Options) AMIFamily { return &Bottlerocket{Options: options} })
+}
+
 type Bottlerocket struct {
 	DefaultFamily
 	*Options
@@ -89,7 +94,14 @@ func (b Bottlerocket) DefaultAMIs(version string, isNodeTemplate bool) []Default
 }
 
 // UserData returns the default userdata script for the AMI Family
-func (b Bottlerocket) UserData(kubeletConfig *corev1beta1.KubeletConfiguration, taints []v1.Taint, labels map[string]string, caBundle *string, _ []*cloudprovider.InstanceType, customUserData *string) bootstrap.Bootstrapper {
+func (b Bottlerocket) UserData(kubeletConfig *corev1beta1.KubeletConfiguration, taints []v1.Taint, labels map[string]string, caBundle *string, instanceTypes []*cloudprovider.InstanceType, customUserData *string) bootstrap.Bootstrapper {
+	// The resolver groups the instance types that share NVIDIA GPUs into their own launch templates
+	var gpuSharing *v1beta1.GPUSharing
+	var gpus []string
+	if len(instanceTypes) > 0 && lo.EveryBy(instanceTypes, b.Options.sharesNVIDIAGPUs) {
+		gpuSharing = b.Options.GPUSharing
+		gpus = nvidiaGPUs(instanceTypes)
+	}
 	return bootstrap.Bottlerocket{
 		Options: bootstrap.Options{
 			ClusterName:             b.Options.ClusterName,
@@ -100,6 +112,10 @@ func (b Bottlerocket) UserData(kubeletConfig *corev1beta1.KubeletConfiguration,
 			Labels:                  labels,
 			CABundle:                caBundle,
 			CustomUserData:          customUserData,
+			InstanceStorePolicy:     b.Options.InstanceStorePolicy,
+			GPUSharing:              gpuSharing,
+			NVIDIAGPUs:              gpus,
+			Bottlerocket:            b.Options.Bottlerocket,
 		},
 	}
 }
@@ -110,7 +126,7 @@ func (b Bottlerocket) DefaultBlockDeviceMappings() []*v1beta1.BlockDeviceMapping
 	xvdaEBS.VolumeSize = lo.ToPtr(resource.MustParse("4Gi"))
 	return []*v1beta1.BlockDeviceMapping{
 		{
-			DeviceName: aws.String("/dev/xvda"),
+			DeviceName: b.RootBlockDevice(),
 			EBS:        &xvdaEBS,
 		},
 		{
@@ -124,23 +140,47 @@ func (b Bottlerocket) EphemeralBlockDevice() *string {
 	return aws.String("/dev/xvdb")
 }
 
+func (b Bottlerocket) RootBlockDevice() *string {
+	return aws.String("/dev/xvda")
+}
+
 // PodsPerCoreEnabled is currently disabled for Bottlerocket AMIFamily because it does
 // not currently support the podsPerCore parameter passed through the kubernetes settings TOML userData
 // If a NodePool sets the podsPerCore value when using the Bottlerocket AMIFamily in the provider,
 // podsPerCore will be ignored
 // https://github.com/bottlerocket-os/bottlerocket/issues/1721
 
-// EvictionSoftEnabled is currently disabled for Bottlerocket AMIFamily because it does
-// not currently support the evictionSoft parameter passed through the kubernetes settings TOML userData
-// If a NodePool sets the evictionSoft value when using the Bottlerocket AMIFamily in the provider,
-// evictionSoft will be ignored
+// EvictionSoftEnabled is enabled for Bottlerocket AMIFamily since the evictionSoft parameters of a NodePool are
+// passed through the eviction-soft kubernetes settings of the TOML userData
 // https://github.com/bottlerocket-os/bottlerocket/issues/1445
 
 func (b Bottlerocket) FeatureFlags() FeatureFlags {
 	return FeatureFlags{
 		UsesENILimitedMemoryOverhead: false,
 		PodsPerCoreEnabled:           false,
-		EvictionSoftEnabled:          false,
+		EvictionSoftEnabled:          true,
 		SupportsENILimitedPodDensity: true,
+		SupportsInstanceStoreRAID0:   true,
+	}
+}
+
+// KubeReserved returns the default settings.kubernetes.kube-reserved of Bottlerocket, which pluto computes from the
+// max-pods setting rather than from the ENI limited pod density like the EKS optimized AMIs. The formulas are the same,
+// the CPU reservation of the EKS optimized AMIs originates from Bottlerocket.
+func (b Bottlerocket) KubeReserved(cpus, pods *resource.Quantity) v1.ResourceList {
+	return b.DefaultFamily.KubeReserved(cpus, pods)
+}
+
+// SystemReserved returns the default settings.kubernetes.system-reserved of Bottlerocket, which is unset
+func (b Bottlerocket) SystemReserved() v1.ResourceList {
+	return v1.ResourceList{}
+}
+
+// EvictionThreshold returns the default settings.kubernetes.eviction-hard of Bottlerocket, which evicts at 100Mi of
+// available memory and 10% of available storage on the data volume
+func (b Bottlerocket) EvictionThreshold(storage *resource.Quantity) v1.ResourceList {
+	return v1.ResourceList{
+		v1.ResourceMemory:           resource.MustParse("100Mi"),
+		v1.ResourceEphemeralStorage: resource.MustParse(fmt.Sprint(math.Ceil(float64(storage.Value()) / 100 * 10))),
 	}
 }
@@ -15,7 +15,9 @@ limitations under the License.
 package amifamily
 
 import (
+	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	corev1beta1 "github.com/aws/karpenter-core/pkg/apis/v1beta1"
 	"github.com/aws/karpenter-core/pkg/cloudprovider"
@@ -24,6 +26,13 @@ import (
 	"github.com/aws/karpenter/pkg/providers/amifamily/bootstrap"
 )
 
+func init() {
+	Register(v1beta1.AMIFamilyCustom, func(options *Options) AMIFamily { return &Custom{Options: options} })
+}
+
+// Custom is the AMIFamily of AMIs with a bootstrap that is unknown to us. The kube-reserved, system-reserved and eviction
+// defaults of the EKS optimized AMIs that custom AMIs are commonly built from are used, which can be adjusted to match
+// the custom bootstrap through the kubelet configuration of the NodePool.
 type Custom struct {
 	DefaultFamily
 	*Options
@@ -49,7 +58,52 @@ func (c Custom) DefaultBlockDeviceMappings() []*v1beta1.BlockDeviceMapping {
 }
 
 // EphemeralBlockDevice is the block device that the pods on the node will use. For an AMI of a custom family, this is unknown
-// to us.
+// to us unless it's declared by the EC2NodeClass.
 func (c Custom) EphemeralBlockDevice() *string {
+	return c.customAMIFamily().EphemeralBlockDevice
+}
+
+// RootBlockDevice is nil since the root device of a custom AMI is only known from the AMI itself
+func (c Custom) RootBlockDevice() *string {
 	return nil
 }
+
+// FeatureFlags are those of the EKS optimized AMIs, except for the ones that the EC2NodeClass declares
+func (c Custom) FeatureFlags() FeatureFlags {
+	featureFlags := c.DefaultFamily.FeatureFlags()
+	if declared := c.customAMIFamily().FeatureFlags; declared != nil {
+		featureFlags.UsesENILimitedMemoryOverhead = lo.FromPtrOr(declared.UsesENILimitedMemoryOverhead, featureFlags.UsesENILimitedMemoryOverhead)
+		featureFlags.PodsPerCoreEnabled = lo.FromPtrOr(declared.PodsPerCoreEnabled, featureFlags.PodsPerCoreEnabled)
+		featureFlags.EvictionSoftEnabled = lo.FromPtrOr(declared.EvictionSoftEnabled, featureFlags.EvictionSoftEnabled)
+		featureFlags.SupportsENILimitedPodDensity = lo.FromPtrOr(declared.SupportsENILimitedPodDensity, featureFlags.SupportsENILimitedPodDensity)
+	}
+	// The UserData of custom AMIs sets up the instance-store disks, so it's only supported if it's declared
+	featureFlags.SupportsInstanceStoreRAID0 = c.customAMIFamily().FeatureFlags != nil && lo.FromPtr(c.customAMIFamily().FeatureFlags.SupportsInstanceStoreRAID0)
+	return featureFlags
+}
+
+// KubeReserved returns the resources that are reserved for kubernetes system daemons according to the kube-reserved
+// model of the EC2NodeClass
+func (c Custom) KubeReserved(cpus, pods *resource.Quantity) v1.ResourceList {
+	if lo.FromPtr(c.customAMIFamily().KubeReservedModel) == v1beta1.KubeReservedModelNone {
+		return v1.ResourceList{}
+	}
+	return c.DefaultFamily.KubeReserved(cpus, pods)
+}
+
+// Architectures returns the architectures that the AMIs support, which are all architectures if they aren't declared
+func (c Custom) Architectures() []string {
+	return c.customAMIFamily().Architectures
+}
+
+// OperatingSystem returns the operating system of the AMIs, which is linux if it isn't declared
+func (c Custom) OperatingSystem() string {
+	return lo.FromPtrOr(c.customAMIFamily().OperatingSystem, string(v1.Linux))
+}
+
+func (c Custom) customAMIFamily() *v1beta1.CustomAMIFamily {
+	if c.Options == nil || c.Options.CustomAMIFamily == nil {
+		return &v1beta1.CustomAMIFamily{}
+	}
+	return c.Options.CustomAMIFamily
+}
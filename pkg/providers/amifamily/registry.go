@@ -0,0 +1,54 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package amifamily
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/aws/karpenter/pkg/apis/v1beta1"
+)
+
+// NewAMIFamilyFunc constructs an AMIFamily with the options of the launch that it's used for
+type NewAMIFamilyFunc func(options *Options) AMIFamily
+
+var registry = map[string]NewAMIFamilyFunc{}
+
+// Register makes an AMIFamily available to EC2NodeClasses under the passed amiFamily name. The built-in AMI families
+// register themselves on init, and downstream builds can register their own AMI families the same way, which also
+// makes the name pass validation of the EC2NodeClass. Register isn't safe for concurrent use, so it should only be
+// called from init functions, and it panics if the name is already registered.
+func Register(name string, newAMIFamily NewAMIFamilyFunc) {
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("registering ami family %q, ami family is already registered", name))
+	}
+	registry[name] = newAMIFamily
+	v1beta1.RegisterAMIFamily(name)
+}
+
+// GetAMIFamily returns the registered AMIFamily of the passed name, or AL2 if the amiFamily isn't set. An amiFamily
+// that isn't registered is an error rather than falling back to AL2, so that nodes aren't launched with the AMIs and
+// user data of a different family.
+func GetAMIFamily(amiFamily *string, options *Options) (AMIFamily, error) {
+	if aws.StringValue(amiFamily) == "" {
+		return &AL2{Options: options}, nil
+	}
+	newAMIFamily, ok := registry[aws.StringValue(amiFamily)]
+	if !ok {
+		return nil, fmt.Errorf("ami family %q isn't registered", aws.StringValue(amiFamily))
+	}
+	return newAMIFamily(options), nil
+}
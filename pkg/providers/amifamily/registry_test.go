@@ -0,0 +1,79 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package amifamily_test
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
+
+	"github.com/aws/karpenter/pkg/apis/v1beta1"
+	"github.com/aws/karpenter/pkg/providers/amifamily"
+)
+
+// Flatcar is an AMIFamily that's registered the way that a downstream build would register one
+type Flatcar struct {
+	amifamily.AL2
+}
+
+func (f Flatcar) DefaultAMIs(version string, _ bool) []amifamily.DefaultAMIOutput {
+	return []amifamily.DefaultAMIOutput{{Query: fmt.Sprintf("/flatcar/%s/image_id", version)}}
+}
+
+func init() {
+	amifamily.Register("Flatcar", func(options *amifamily.Options) amifamily.AMIFamily {
+		return &Flatcar{AL2: amifamily.AL2{Options: options}}
+	})
+}
+
+var _ = Describe("AMIFamily Registry", func() {
+	It("should return the built-in AMI families", func() {
+		Expect(amifamily.GetAMIFamily(aws.String(v1beta1.AMIFamilyAL2), &amifamily.Options{})).To(BeAssignableToTypeOf(&amifamily.AL2{}))
+		Expect(amifamily.GetAMIFamily(aws.String(v1beta1.AMIFamilyAL2023), &amifamily.Options{})).To(BeAssignableToTypeOf(&amifamily.AL2023{}))
+		Expect(amifamily.GetAMIFamily(aws.String(v1beta1.AMIFamilyBottlerocket), &amifamily.Options{})).To(BeAssignableToTypeOf(&amifamily.Bottlerocket{}))
+		Expect(amifamily.GetAMIFamily(aws.String(v1beta1.AMIFamilyUbuntu), &amifamily.Options{})).To(BeAssignableToTypeOf(&amifamily.Ubuntu{}))
+		Expect(amifamily.GetAMIFamily(aws.String(v1beta1.AMIFamilyCustom), &amifamily.Options{})).To(BeAssignableToTypeOf(&amifamily.Custom{}))
+		windows, err := amifamily.GetAMIFamily(aws.String(v1beta1.AMIFamilyWindows2022), &amifamily.Options{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(windows).To(BeAssignableToTypeOf(&amifamily.Windows{}))
+		Expect(windows.(*amifamily.Windows).Version).To(Equal(v1beta1.Windows2022))
+	})
+	It("should return a registered AMI family", func() {
+		amiFamily, err := amifamily.GetAMIFamily(aws.String("Flatcar"), &amifamily.Options{ClusterName: "test-cluster"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(amiFamily).To(BeAssignableToTypeOf(&Flatcar{}))
+		Expect(amiFamily.(*Flatcar).Options.ClusterName).To(Equal("test-cluster"))
+		Expect(amiFamily.DefaultAMIs("1.28", false)).To(ConsistOf(amifamily.DefaultAMIOutput{Query: "/flatcar/1.28/image_id"}))
+	})
+	It("should make a registered AMI family pass validation", func() {
+		Expect(lo.Contains(v1beta1.SupportedAMIFamilies, "Flatcar")).To(BeTrue())
+	})
+	It("should default to AL2 when the AMI family isn't set", func() {
+		Expect(amifamily.GetAMIFamily(nil, &amifamily.Options{})).To(BeAssignableToTypeOf(&amifamily.AL2{}))
+		Expect(amifamily.GetAMIFamily(aws.String(""), &amifamily.Options{})).To(BeAssignableToTypeOf(&amifamily.AL2{}))
+	})
+	It("should fail when the AMI family isn't registered", func() {
+		_, err := amifamily.GetAMIFamily(aws.String("Bottlerocekt"), &amifamily.Options{})
+		Expect(err).To(HaveOccurred())
+	})
+	It("should panic when an AMI family is registered twice", func() {
+		Expect(func() {
+			amifamily.Register(v1beta1.AMIFamilyAL2, func(options *amifamily.Options) amifamily.AMIFamily { return &amifamily.AL2{Options: options} })
+		}).To(Panic())
+	})
+})
@@ -17,7 +17,9 @@ package amifamily
 import (
 	"context"
 	"fmt"
+	"math"
 	"net"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -49,6 +51,7 @@ type Resolver struct {
 type Options struct {
 	ClusterName             string
 	ClusterEndpoint         string
+	ClusterCIDR             *string
 	AWSENILimitedPodDensity bool
 	InstanceProfile         string
 	CABundle                *string `hash:"ignore"`
@@ -58,6 +61,10 @@ type Options struct {
 	Labels                   map[string]string `hash:"ignore"`
 	KubeDNSIP                net.IP
 	AssociatePublicIPAddress *bool
+	InstanceStorePolicy      *string
+	GPUSharing               *v1beta1.GPUSharing
+	CustomAMIFamily          *v1beta1.CustomAMIFamily
+	Bottlerocket             *v1beta1.Bottlerocket
 }
 
 // LaunchTemplate holds the dynamically generated launch template parameters
@@ -71,14 +78,23 @@ type LaunchTemplate struct {
 	DetailedMonitoring  bool
 }
 
-// AMIFamily can be implemented to override the default logic for generating dynamic launch template parameters
+// AMIFamily can be implemented to override the default logic for generating dynamic launch template parameters. An
+// AMIFamily is made available to EC2NodeClasses by registering it with Register.
 type AMIFamily interface {
 	DefaultAMIs(version string, isNodeTemplate bool) []DefaultAMIOutput
 	UserData(kubeletConfig *corev1beta1.KubeletConfiguration, taints []core.Taint, labels map[string]string, caBundle *string, instanceTypes []*cloudprovider.InstanceType, customUserData *string) bootstrap.Bootstrapper
 	DefaultBlockDeviceMappings() []*v1beta1.BlockDeviceMapping
 	DefaultMetadataOptions() *v1beta1.MetadataOptions
 	EphemeralBlockDevice() *string
+	// RootBlockDevice is the device name of the root volume of the AMIs of the AMI family, or nil if it isn't known
+	// ahead of launch
+	RootBlockDevice() *string
 	FeatureFlags() FeatureFlags
+	// KubeReserved, SystemReserved and EvictionThreshold are the overhead defaults of the bootstrap of the AMI family.
+	// DefaultFamily provides those of the EKS optimized Linux AMIs, which AL2, AL2023 and Ubuntu share.
+	KubeReserved(cpus, pods *resource.Quantity) core.ResourceList
+	SystemReserved() core.ResourceList
+	EvictionThreshold(storage *resource.Quantity) core.ResourceList
 }
 
 type DefaultAMIOutput struct {
@@ -92,6 +108,9 @@ type FeatureFlags struct {
 	PodsPerCoreEnabled           bool
 	EvictionSoftEnabled          bool
 	SupportsENILimitedPodDensity bool
+	// SupportsInstanceStoreRAID0 is whether the bootstrap assembles the instance-store disks into a RAID0 array that
+	// backs the kubelet and container runtime when the instance store policy is RAID0
+	SupportsInstanceStoreRAID0 bool
 }
 
 // DefaultFamily provides default values for AMIFamilies that compose it
@@ -103,9 +122,96 @@ func (d DefaultFamily) FeatureFlags() FeatureFlags {
 		PodsPerCoreEnabled:           true,
 		EvictionSoftEnabled:          true,
 		SupportsENILimitedPodDensity: true,
+		SupportsInstanceStoreRAID0:   true,
 	}
 }
 
+// KubeReserved returns the resources that the bootstrap of the EKS optimized AMIs reserves for kubernetes system
+// daemons by default. The CPU reservation is computed from
+// https://github.com/bottlerocket-os/bottlerocket/pull/1388/files#diff-bba9e4e3e46203be2b12f22e0d654ebd270f0b478dd34f40c31d7aa695620f2fR611
+func (d DefaultFamily) KubeReserved(cpus, pods *resource.Quantity) core.ResourceList {
+	resources := core.ResourceList{
+		core.ResourceMemory:           resource.MustParse(fmt.Sprintf("%dMi", (11*pods.Value())+255)),
+		core.ResourceEphemeralStorage: resource.MustParse("1Gi"), // default kube-reserved ephemeral-storage
+	}
+	for _, cpuRange := range []struct {
+		start      int64
+		end        int64
+		percentage float64
+	}{
+		{start: 0, end: 1000, percentage: 0.06},
+		{start: 1000, end: 2000, percentage: 0.01},
+		{start: 2000, end: 4000, percentage: 0.005},
+		{start: 4000, end: 1 << 31, percentage: 0.0025},
+	} {
+		if cpu := cpus.MilliValue(); cpu >= cpuRange.start {
+			r := float64(cpuRange.end - cpuRange.start)
+			if cpu < cpuRange.end {
+				r = float64(cpu - cpuRange.start)
+			}
+			cpuOverhead := resources.Cpu()
+			cpuOverhead.Add(*resource.NewMilliQuantity(int64(r*cpuRange.percentage), resource.DecimalSI))
+			resources[core.ResourceCPU] = *cpuOverhead
+		}
+	}
+	return resources
+}
+
+// SystemReserved returns the resources that the bootstrap reserves for OS system daemons by default. The bootstrap of
+// the EKS optimized AMIs doesn't reserve any resources for system daemons.
+func (d DefaultFamily) SystemReserved() core.ResourceList {
+	return core.ResourceList{}
+}
+
+// EvictionThreshold returns the default hard eviction thresholds of the kubelet
+func (d DefaultFamily) EvictionThreshold(storage *resource.Quantity) core.ResourceList {
+	return core.ResourceList{
+		core.ResourceMemory:           resource.MustParse("100Mi"),
+		core.ResourceEphemeralStorage: resource.MustParse(fmt.Sprint(math.Ceil(float64(storage.Value()) / 100 * 10))),
+	}
+}
+
+// launchTemplateGroup is the key that instance types are grouped by, where each group resolves a launch template
+type launchTemplateGroup struct {
+	maxPods    int
+	sharesGPUs bool
+}
+
+// sharesNVIDIAGPUs returns true if the NVIDIA GPUs of the instance type are shared with the GPUSharing of the
+// EC2NodeClass. MIG only applies to the instance types whose GPUs are partitioned into MIG instances of the profile.
+func (o Options) sharesNVIDIAGPUs(instanceType *cloudprovider.InstanceType) bool {
+	if o.GPUSharing == nil {
+		return false
+	}
+	var gpus resource.Quantity
+	switch o.GPUSharing.Strategy {
+	case v1beta1.GPUSharingStrategyTimeSlicing:
+		gpus = instanceType.Capacity[v1beta1.ResourceNVIDIAGPU]
+	case v1beta1.GPUSharingStrategyMIG:
+		gpus = instanceType.Capacity[core.ResourceName(v1beta1.ResourceNVIDIAMIGPrefix+lo.FromPtr(o.GPUSharing.MIGProfile))]
+	}
+	return !gpus.IsZero()
+}
+
+// nvidiaGPUs returns the GPUs of the instance types in the form that Bottlerocket keys the MIG profiles by, such as
+// a100.40gb for the GPUs of the p4d instance types
+func nvidiaGPUs(instanceTypes []*cloudprovider.InstanceType) []string {
+	var gpus []string
+	for _, instanceType := range instanceTypes {
+		names := instanceType.Requirements.Get(v1beta1.LabelInstanceGPUName).Values()
+		memories := instanceType.Requirements.Get(v1beta1.LabelInstanceGPUMemory).Values()
+		if len(names) != 1 || len(memories) != 1 {
+			continue
+		}
+		memoryMiB, err := strconv.ParseInt(memories[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		gpus = append(gpus, fmt.Sprintf("%s.%dgb", names[0], memoryMiB/1024))
+	}
+	return lo.Uniq(gpus)
+}
+
 // New constructs a new launch template Resolver
 func New(amiProvider *Provider) *Resolver {
 	return &Resolver{
@@ -116,7 +222,10 @@ func New(amiProvider *Provider) *Resolver {
 // Resolve generates launch templates using the static options and dynamically generates launch template parameters.
 // Multiple ResolvedTemplates are returned based on the instanceTypes passed in to support special AMIs for certain instance types like GPUs.
 func (r Resolver) Resolve(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType, options *Options) ([]*LaunchTemplate, error) {
-	amiFamily := GetAMIFamily(nodeClass.Spec.AMIFamily, options)
+	amiFamily, err := GetAMIFamily(nodeClass.Spec.AMIFamily, options)
+	if err != nil {
+		return nil, err
+	}
 	amis, err := r.amiProvider.Get(ctx, nodeClass, options)
 	if err != nil {
 		return nil, err
@@ -124,19 +233,27 @@ func (r Resolver) Resolve(ctx context.Context, nodeClass *v1beta1.EC2NodeClass,
 	if len(amis) == 0 {
 		return nil, fmt.Errorf("no amis exist given constraints")
 	}
+	amis = amis.ForNodeClaim(nodeClass, nodeClaim.Name)
 	mappedAMIs := amis.MapToInstanceTypes(instanceTypes, nodeClaim.IsMachine)
 	if len(mappedAMIs) == 0 {
 		return nil, fmt.Errorf("no instance types satisfy requirements of amis %v", amis)
 	}
 	var resolvedTemplates []*LaunchTemplate
 	for amiID, instanceTypes := range mappedAMIs {
-		maxPodsToInstanceTypes := lo.GroupBy(instanceTypes, func(instanceType *cloudprovider.InstanceType) int {
-			return int(instanceType.Capacity.Pods().Value())
+		ami, _ := lo.Find(amis, func(a AMI) bool { return a.AmiID == amiID })
+		groupedInstanceTypes := lo.GroupBy(instanceTypes, func(instanceType *cloudprovider.InstanceType) launchTemplateGroup {
+			return launchTemplateGroup{
+				maxPods:    int(instanceType.Capacity.Pods().Value()),
+				sharesGPUs: options.sharesNVIDIAGPUs(instanceType),
+			}
 		})
 		// In order to support reserved ENIs for CNI custom networking setups,
 		// we need to pass down the max-pods calculation to the kubelet.
 		// This requires that we resolve a unique launch template per max-pods value.
-		for maxPods, instanceTypes := range maxPodsToInstanceTypes {
+		// The GPU sharing configuration of the bootstrap only applies to instance types that share NVIDIA GPUs, which
+		// also requires a unique launch template.
+		for group, instanceTypes := range groupedInstanceTypes {
+			maxPods := group.maxPods
 			kubeletConfig := &corev1beta1.KubeletConfiguration{}
 			if nodeClaim.Spec.Kubelet != nil {
 				if err := mergo.Merge(kubeletConfig, nodeClaim.Spec.Kubelet); err != nil {
@@ -156,15 +273,12 @@ func (r Resolver) Resolve(ctx context.Context, nodeClass *v1beta1.EC2NodeClass,
 					instanceTypes,
 					nodeClass.Spec.UserData,
 				),
-				BlockDeviceMappings: nodeClass.Spec.BlockDeviceMappings,
+				BlockDeviceMappings: ami.BlockDeviceMappings(amiFamily, nodeClass.Spec.BlockDeviceMappings),
 				MetadataOptions:     nodeClass.Spec.MetadataOptions,
 				DetailedMonitoring:  aws.BoolValue(nodeClass.Spec.DetailedMonitoring),
 				AMIID:               amiID,
 				InstanceTypes:       instanceTypes,
 			}
-			if len(resolved.BlockDeviceMappings) == 0 {
-				resolved.BlockDeviceMappings = amiFamily.DefaultBlockDeviceMappings()
-			}
 			if resolved.MetadataOptions == nil {
 				resolved.MetadataOptions = amiFamily.DefaultMetadataOptions()
 			}
@@ -174,23 +288,6 @@ func (r Resolver) Resolve(ctx context.Context, nodeClass *v1beta1.EC2NodeClass,
 	return resolvedTemplates, nil
 }
 
-func GetAMIFamily(amiFamily *string, options *Options) AMIFamily {
-	switch aws.StringValue(amiFamily) {
-	case v1beta1.AMIFamilyBottlerocket:
-		return &Bottlerocket{Options: options}
-	case v1beta1.AMIFamilyUbuntu:
-		return &Ubuntu{Options: options}
-	case v1beta1.AMIFamilyWindows2019:
-		return &Windows{Options: options, Version: v1beta1.Windows2019, Build: v1beta1.Windows2019Build}
-	case v1beta1.AMIFamilyWindows2022:
-		return &Windows{Options: options, Version: v1beta1.Windows2022, Build: v1beta1.Windows2022Build}
-	case v1beta1.AMIFamilyCustom:
-		return &Custom{Options: options}
-	default:
-		return &AL2{Options: options}
-	}
-}
-
 func (o Options) DefaultMetadataOptions() *v1beta1.MetadataOptions {
 	return &v1beta1.MetadataOptions{
 		HTTPEndpoint:            aws.String(ec2.LaunchTemplateInstanceMetadataEndpointStateEnabled),
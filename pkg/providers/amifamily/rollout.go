@@ -0,0 +1,76 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package amifamily
+
+import (
+	"hash/fnv"
+
+	"github.com/mitchellh/hashstructure/v2"
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/aws/karpenter/pkg/apis/v1beta1"
+)
+
+// RolloutPercentage returns the percentage of nodes that are launched with the AMI that's being rolled out
+func RolloutPercentage(nodeClass *v1beta1.EC2NodeClass, rollout v1beta1.AMIRolloutStatus) int32 {
+	if rollout.Halted {
+		return 0
+	}
+	// The rollout is done if it's no longer configured
+	if nodeClass.Spec.AMIRollout == nil || int(rollout.Step) >= len(nodeClass.Spec.AMIRollout.Steps) {
+		return 100
+	}
+	return nodeClass.Spec.AMIRollout.Steps[rollout.Step]
+}
+
+// IsRolledOut returns whether the NodeClaim with the passed name falls within the current step of the rollout. Each
+// NodeClaim is assigned a fixed bucket based on its name, so that launches and drift agree on which NodeClaims use the
+// AMI that's being rolled out, and so that the NodeClaims that use it only grow as the rollout progresses.
+func IsRolledOut(nodeClass *v1beta1.EC2NodeClass, rollout v1beta1.AMIRolloutStatus, nodeClaimName string) bool {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(nodeClaimName))
+	return int32(h.Sum32()%100) < RolloutPercentage(nodeClass, rollout)
+}
+
+// ForNodeClaim returns the AMIs that the NodeClaim with the passed name is launched with. An AMI that's being rolled out
+// is swapped for the AMI that it replaces if the NodeClaim doesn't fall within the current step of its rollout. While a
+// rollout is halted no NodeClaim falls within it, so nodes that were launched with the AMI are drifted back.
+func (a AMIs) ForNodeClaim(nodeClass *v1beta1.EC2NodeClass, nodeClaimName string) AMIs {
+	return lo.Map(a, func(ami AMI, _ int) AMI {
+		id, rootDeviceName := ami.AmiID, ami.RootDeviceName
+		// A newly resolved AMI hasn't started its rollout until it's in the status of the EC2NodeClass, so the AMI that it
+		// replaces is used until then
+		if nodeClass.Spec.AMIRollout != nil && !lo.ContainsBy(nodeClass.Status.AMIs, func(s v1beta1.AMI) bool { return s.ID == id }) {
+			if replaced, ok := lo.Find(nodeClass.Status.AMIs, func(s v1beta1.AMI) bool {
+				return s.EligibleTime == nil && requirementsHash(s.Requirements) == requirementsHash(ami.Requirements.NodeSelectorRequirements())
+			}); ok {
+				id, rootDeviceName = replaced.ID, replaced.RootDeviceName
+			}
+		}
+		if rollout, ok := lo.Find(nodeClass.Status.AMIRollouts, func(r v1beta1.AMIRolloutStatus) bool { return r.ID == id }); ok && !IsRolledOut(nodeClass, rollout, nodeClaimName) {
+			id, rootDeviceName = rollout.PreviousID, rollout.PreviousRootDeviceName
+		}
+		if id == ami.AmiID {
+			return ami
+		}
+		// The root device is kept so that the root volume is still retargeted at it
+		return AMI{AmiID: id, RootDeviceName: rootDeviceName, Requirements: ami.Requirements}
+	})
+}
+
+func requirementsHash(requirements []v1.NodeSelectorRequirement) uint64 {
+	return lo.Must(hashstructure.Hash(requirements, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true}))
+}
@@ -28,6 +28,10 @@ import (
 	"github.com/aws/karpenter-core/pkg/scheduling"
 )
 
+func init() {
+	Register(v1beta1.AMIFamilyUbuntu, func(options *Options) AMIFamily { return &Ubuntu{Options: options} })
+}
+
 type Ubuntu struct {
 	DefaultFamily
 	*Options
@@ -67,6 +71,14 @@ func (u Ubuntu) UserData(kubeletConfig *corev1beta1.KubeletConfiguration, taints
 	}
 }
 
+// FeatureFlags are those of the EKS optimized AMIs, except that the bootstrap of the Canonical AMIs doesn't support
+// the --local-disks flag that sets up the instance-store disks
+func (u Ubuntu) FeatureFlags() FeatureFlags {
+	featureFlags := u.DefaultFamily.FeatureFlags()
+	featureFlags.SupportsInstanceStoreRAID0 = false
+	return featureFlags
+}
+
 // DefaultBlockDeviceMappings returns the default block device mappings for the AMI Family
 func (u Ubuntu) DefaultBlockDeviceMappings() []*v1beta1.BlockDeviceMapping {
 	return []*v1beta1.BlockDeviceMapping{{
@@ -78,3 +90,7 @@ func (u Ubuntu) DefaultBlockDeviceMappings() []*v1beta1.BlockDeviceMapping {
 func (u Ubuntu) EphemeralBlockDevice() *string {
 	return aws.String("/dev/sda1")
 }
+
+func (u Ubuntu) RootBlockDevice() *string {
+	return u.EphemeralBlockDevice()
+}
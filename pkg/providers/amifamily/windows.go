@@ -16,6 +16,7 @@ package amifamily
 
 import (
 	"fmt"
+	"math"
 
 	corev1beta1 "github.com/aws/karpenter-core/pkg/apis/v1beta1"
 	"github.com/aws/karpenter-core/pkg/scheduling"
@@ -35,6 +36,15 @@ import (
 	"github.com/aws/karpenter/pkg/apis/v1alpha1"
 )
 
+func init() {
+	Register(v1beta1.AMIFamilyWindows2019, func(options *Options) AMIFamily {
+		return &Windows{Options: options, Version: v1beta1.Windows2019, Build: v1beta1.Windows2019Build}
+	})
+	Register(v1beta1.AMIFamilyWindows2022, func(options *Options) AMIFamily {
+		return &Windows{Options: options, Version: v1beta1.Windows2022, Build: v1beta1.Windows2022Build}
+	})
+}
+
 type Windows struct {
 	DefaultFamily
 	*Options
@@ -84,11 +94,33 @@ func (w Windows) EphemeralBlockDevice() *string {
 	return aws.String("/dev/sda1")
 }
 
+func (w Windows) RootBlockDevice() *string {
+	return w.EphemeralBlockDevice()
+}
+
 func (w Windows) FeatureFlags() FeatureFlags {
 	return FeatureFlags{
 		UsesENILimitedMemoryOverhead: false,
 		PodsPerCoreEnabled:           true,
 		EvictionSoftEnabled:          true,
 		SupportsENILimitedPodDensity: false,
+		SupportsInstanceStoreRAID0:   false,
+	}
+}
+
+// SystemReserved returns the memory that the EKS optimized Windows AMI bootstrap reserves for the OS, since the system
+// processes of Windows use considerably more memory than those of Linux
+func (w Windows) SystemReserved() v1.ResourceList {
+	return v1.ResourceList{
+		v1.ResourceMemory: resource.MustParse("1.5Gi"),
+	}
+}
+
+// EvictionThreshold returns the hard eviction thresholds that the EKS optimized Windows AMI bootstrap configures on the
+// kubelet. Windows nodes need a larger memory buffer than Linux nodes since Windows doesn't have an OOM killer.
+func (w Windows) EvictionThreshold(storage *resource.Quantity) v1.ResourceList {
+	return v1.ResourceList{
+		v1.ResourceMemory:           resource.MustParse("500Mi"),
+		v1.ResourceEphemeralStorage: resource.MustParse(fmt.Sprint(math.Ceil(float64(storage.Value()) / 100 * 10))),
 	}
 }
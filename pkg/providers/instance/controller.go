@@ -0,0 +1,56 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corecontroller "github.com/aws/karpenter-core/pkg/operator/controller"
+)
+
+// inventorySyncInterval is the interval at which the inventory is replaced by a full list of the instances. State
+// change events keep the inventory current in between, so this only corrects for missed or dropped events.
+const inventorySyncInterval = 5 * time.Minute
+
+// Controller periodically reconciles the instance inventory against EC2
+type Controller struct {
+	instanceProvider *Provider
+}
+
+func NewController(instanceProvider *Provider) *Controller {
+	return &Controller{
+		instanceProvider: instanceProvider,
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	if err := c.instanceProvider.SyncInventory(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("syncing instance inventory, %w", err)
+	}
+	return reconcile.Result{RequeueAfter: inventorySyncInterval}, nil
+}
+
+func (c *Controller) Name() string {
+	return "instance.inventory"
+}
+
+func (c *Controller) Builder(_ context.Context, m manager.Manager) corecontroller.Builder {
+	return corecontroller.NewSingletonManagedBy(m)
+}
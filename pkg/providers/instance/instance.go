@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -69,6 +71,7 @@ type Provider struct {
 	subnetProvider         *subnet.Provider
 	launchTemplateProvider *launchtemplate.Provider
 	ec2Batcher             *batcher.EC2API
+	inventory              *Inventory
 }
 
 func NewProvider(ctx context.Context, region string, ec2api ec2iface.EC2API, unavailableOfferings *cache.UnavailableOfferings,
@@ -81,12 +84,13 @@ func NewProvider(ctx context.Context, region string, ec2api ec2iface.EC2API, una
 		subnetProvider:         subnetProvider,
 		launchTemplateProvider: launchTemplateProvider,
 		ec2Batcher:             batcher.EC2(ctx, ec2api),
+		inventory:              NewInventory(),
 	}
 }
 
 func (p *Provider) Create(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType) (*Instance, error) {
 	instanceTypes = p.filterInstanceTypes(nodeClaim, instanceTypes)
-	instanceTypes = orderInstanceTypesByPrice(instanceTypes, scheduling.NewNodeSelectorRequirements(nodeClaim.Spec.Requirements...))
+	instanceTypes = orderInstanceTypesByPrice(instanceTypes, scheduling.NewNodeSelectorRequirements(nodeClaim.Spec.Requirements...), nodeClass.Spec.InstanceTypeRanking)
 	if len(instanceTypes) > MaxInstanceTypes {
 		instanceTypes = instanceTypes[0:MaxInstanceTypes]
 	}
@@ -100,7 +104,11 @@ func (p *Provider) Create(ctx context.Context, nodeClass *v1beta1.EC2NodeClass,
 	if err != nil {
 		return nil, err
 	}
-	return NewInstanceFromFleet(fleetInstance, tags), nil
+	instance := NewInstanceFromFleet(fleetInstance, tags)
+	if inventoryEnabled(ctx) {
+		p.inventory.Add(instance, false)
+	}
+	return instance, nil
 }
 
 func (p *Provider) Link(ctx context.Context, id, provisionerName string) error {
@@ -114,6 +122,14 @@ func (p *Provider) Link(ctx context.Context, id, provisionerName string) error {
 }
 
 func (p *Provider) Get(ctx context.Context, id string) (*Instance, error) {
+	if inventoryEnabled(ctx) {
+		if instance, ok := p.inventory.Get(id); ok {
+			if instance.State == ec2.InstanceStateNameTerminated {
+				return nil, cloudprovider.NewNodeClaimNotFoundError(fmt.Errorf("instance terminated"))
+			}
+			return instance, nil
+		}
+	}
 	out, err := p.ec2Batcher.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
 		InstanceIds: aws.StringSlice([]string{id}),
 		Filters:     []*ec2.Filter{instanceStateFilter},
@@ -131,20 +147,54 @@ func (p *Provider) Get(ctx context.Context, id string) (*Instance, error) {
 	if len(instances) != 1 {
 		return nil, fmt.Errorf("expected a single instance, %w", err)
 	}
+	if inventoryEnabled(ctx) {
+		p.inventory.Add(instances[0], true)
+	}
 	return instances[0], nil
 }
 
+// List returns the instances launched by Karpenter in the cluster. When the inventory is enabled, instances are listed
+// from EC2 only to seed the inventory and are read from the inventory afterwards.
 func (p *Provider) List(ctx context.Context) ([]*Instance, error) {
-	var out = &ec2.DescribeInstancesOutput{}
-	tagKeys := []string{v1alpha5.ProvisionerNameLabelKey}
-	if nodepoolutil.EnableNodePools {
-		tagKeys = append(tagKeys, corev1beta1.NodePoolLabelKey)
+	if inventoryEnabled(ctx) {
+		if !p.inventory.Synced() {
+			if err := p.SyncInventory(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return lo.Filter(p.inventory.List(), func(i *Instance, _ int) bool { return isClusterInstance(ctx, i) }), nil
+	}
+	return p.list(ctx)
+}
+
+// SyncInventory replaces the inventory with a full list of the instances from EC2
+func (p *Provider) SyncInventory(ctx context.Context) error {
+	listedAt := time.Now()
+	instances, err := p.list(ctx)
+	if err != nil {
+		return err
 	}
+	p.inventory.Replace(instances, listedAt)
+	return nil
+}
+
+// UpdateInstanceState updates the state of an instance in the inventory from an EC2 state change event
+func (p *Provider) UpdateInstanceState(id string, state string) {
+	p.inventory.UpdateState(id, state)
+}
+
+// ResetInventory clears the inventory so that it's seeded again by the next list
+func (p *Provider) ResetInventory() {
+	p.inventory.Reset()
+}
+
+func (p *Provider) list(ctx context.Context) ([]*Instance, error) {
+	var out = &ec2.DescribeInstancesOutput{}
 	err := p.ec2api.DescribeInstancesPagesWithContext(ctx, &ec2.DescribeInstancesInput{
 		Filters: []*ec2.Filter{
 			{
 				Name:   aws.String("tag-key"),
-				Values: aws.StringSlice(tagKeys),
+				Values: aws.StringSlice(clusterInstanceTagKeys()),
 			},
 			{
 				Name:   aws.String("tag-key"),
@@ -168,6 +218,7 @@ func (p *Provider) Delete(ctx context.Context, id string) error {
 		InstanceIds: []*string{aws.String(id)},
 	}); err != nil {
 		if awserrors.IsNotFound(err) {
+			p.inventory.UpdateState(id, ec2.InstanceStateNameTerminated)
 			return cloudprovider.NewNodeClaimNotFoundError(fmt.Errorf("instance already terminated"))
 		}
 		if _, e := p.Get(ctx, id); err != nil {
@@ -178,6 +229,7 @@ func (p *Provider) Delete(ctx context.Context, id string) error {
 		}
 		return fmt.Errorf("terminating instance, %w", err)
 	}
+	p.inventory.UpdateState(id, ec2.InstanceStateNameShuttingDown)
 	return nil
 }
 
@@ -194,6 +246,7 @@ func (p *Provider) CreateTags(ctx context.Context, id string, tags map[string]st
 		}
 		return fmt.Errorf("tagging instance, %w", err)
 	}
+	p.inventory.UpdateTags(id, tags)
 	return nil
 }
 
@@ -255,6 +308,31 @@ func (p *Provider) launchInstance(ctx context.Context, nodeClass *v1beta1.EC2Nod
 	return createFleetOutput.Instances[0], nil
 }
 
+// inventoryEnabled returns whether instances are read from the inventory, which is only kept current when EC2 state
+// change events are received from the interruption queue
+func inventoryEnabled(ctx context.Context) bool {
+	return settings.FromContext(ctx).InterruptionQueueName != ""
+}
+
+func clusterInstanceTagKeys() []string {
+	tagKeys := []string{v1alpha5.ProvisionerNameLabelKey}
+	if nodepoolutil.EnableNodePools {
+		tagKeys = append(tagKeys, corev1beta1.NodePoolLabelKey)
+	}
+	return tagKeys
+}
+
+// isClusterInstance returns whether the instance would be returned by the filters used to list instances from EC2
+func isClusterInstance(ctx context.Context, instance *Instance) bool {
+	if _, ok := instance.Tags[fmt.Sprintf("kubernetes.io/cluster/%s", settings.FromContext(ctx).ClusterName)]; !ok {
+		return false
+	}
+	return lo.SomeBy(clusterInstanceTagKeys(), func(key string) bool {
+		_, ok := instance.Tags[key]
+		return ok
+	}) && lo.Contains(aws.StringValueSlice(instanceStateFilter.Values), instance.State)
+}
+
 func getTags(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta1.NodeClaim) map[string]string {
 	var overridableTags, staticTags map[string]string
 	if nodeClaim.IsMachine {
@@ -302,13 +380,17 @@ func (p *Provider) checkODFallback(nodeClaim *corev1beta1.NodeClaim, instanceTyp
 func (p *Provider) getLaunchTemplateConfigs(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta1.NodeClaim,
 	instanceTypes []*cloudprovider.InstanceType, zonalSubnets map[string]*ec2.Subnet, capacityType string, tags map[string]string) ([]*ec2.FleetLaunchTemplateConfigRequest, error) {
 	var launchTemplateConfigs []*ec2.FleetLaunchTemplateConfigRequest
+	zones, err := p.getZoneRequirement(ctx, scheduling.NewNodeSelectorRequirements(nodeClaim.Spec.Requirements...))
+	if err != nil {
+		return nil, fmt.Errorf("getting zones, %w", err)
+	}
 	launchTemplates, err := p.launchTemplateProvider.EnsureAll(ctx, nodeClass, nodeClaim, instanceTypes, map[string]string{corev1beta1.CapacityTypeLabelKey: capacityType}, tags)
 	if err != nil {
 		return nil, fmt.Errorf("getting launch templates, %w", err)
 	}
 	for _, launchTemplate := range launchTemplates {
 		launchTemplateConfig := &ec2.FleetLaunchTemplateConfigRequest{
-			Overrides: p.getOverrides(launchTemplate.InstanceTypes, zonalSubnets, scheduling.NewNodeSelectorRequirements(nodeClaim.Spec.Requirements...).Get(v1.LabelTopologyZone), capacityType, launchTemplate.ImageID),
+			Overrides: p.getOverrides(launchTemplate.InstanceTypes, zonalSubnets, zones, capacityType, launchTemplate.ImageID),
 			LaunchTemplateSpecification: &ec2.FleetLaunchTemplateSpecificationRequest{
 				LaunchTemplateName: aws.String(launchTemplate.Name),
 				Version:            aws.String("$Latest"),
@@ -324,6 +406,24 @@ func (p *Provider) getLaunchTemplateConfigs(ctx context.Context, nodeClass *v1be
 	return launchTemplateConfigs, nil
 }
 
+// getZoneRequirement returns the zones that satisfy the zone, zone ID and zone type requirements. Since the zone ID and
+// zone type of an instance type's offerings are only known by zone name, they're resolved to zone names before launch.
+func (p *Provider) getZoneRequirement(ctx context.Context, requirements scheduling.Requirements) (*scheduling.Requirement, error) {
+	zoneRequirement := requirements.Get(v1.LabelTopologyZone)
+	if !requirements.Has(v1beta1.LabelTopologyZoneID) && !requirements.Has(v1beta1.LabelTopologyZoneType) {
+		return zoneRequirement, nil
+	}
+	zones, err := p.instanceTypeProvider.GetZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return scheduling.NewRequirement(v1.LabelTopologyZone, v1.NodeSelectorOpIn, lo.FilterMap(lo.Values(zones), func(zone instancetype.Zone, _ int) (string, bool) {
+		return zone.Name, zoneRequirement.Has(zone.Name) &&
+			requirements.Get(v1beta1.LabelTopologyZoneID).Has(zone.ID) &&
+			requirements.Get(v1beta1.LabelTopologyZoneType).Has(zone.Type)
+	})...), nil
+}
+
 // getOverrides creates and returns launch template overrides for the cross product of InstanceTypes and subnets (with subnets being constrained by
 // zones and the offerings in InstanceTypes)
 func (p *Provider) getOverrides(instanceTypes []*cloudprovider.InstanceType, zonalSubnets map[string]*ec2.Subnet, zones *scheduling.Requirement, capacityType string, image string) []*ec2.FleetLaunchTemplateOverridesRequest {
@@ -394,25 +494,65 @@ func (p *Provider) getCapacityType(nodeClaim *corev1beta1.NodeClaim, instanceTyp
 	return corev1beta1.CapacityTypeOnDemand
 }
 
-func orderInstanceTypesByPrice(instanceTypes []*cloudprovider.InstanceType, requirements scheduling.Requirements) []*cloudprovider.InstanceType {
-	// Order instance types so that we get the cheapest instance types of the available offerings
-	sort.Slice(instanceTypes, func(i, j int) bool {
-		iPrice := math.MaxFloat64
-		jPrice := math.MaxFloat64
-		if len(instanceTypes[i].Offerings.Available().Requirements(requirements)) > 0 {
-			iPrice = instanceTypes[i].Offerings.Available().Requirements(requirements).Cheapest().Price
+// orderInstanceTypesByPrice orders instance types by the price of their cheapest available offering, normalized according
+// to the ranking policy of the EC2NodeClass. Instance types without a ranking value (e.g. an unscored instance family) are
+// ordered by absolute price after the ranked instance types, followed by the instance types without an available offering.
+func orderInstanceTypesByPrice(instanceTypes []*cloudprovider.InstanceType, requirements scheduling.Requirements, ranking *v1beta1.InstanceTypeRanking) []*cloudprovider.InstanceType {
+	type rank struct {
+		tier  int
+		value float64
+	}
+	ranks := make(map[string]rank, len(instanceTypes))
+	for _, it := range instanceTypes {
+		offerings := it.Offerings.Available().Requirements(requirements)
+		if len(offerings) == 0 {
+			ranks[it.Name] = rank{tier: 2, value: math.MaxFloat64}
+			continue
+		}
+		price := offerings.Cheapest().Price
+		if value, ok := rankingValue(it, price, ranking); ok {
+			ranks[it.Name] = rank{tier: 0, value: value}
+		} else {
+			ranks[it.Name] = rank{tier: 1, value: price}
 		}
-		if len(instanceTypes[j].Offerings.Available().Requirements(requirements)) > 0 {
-			jPrice = instanceTypes[j].Offerings.Available().Requirements(requirements).Cheapest().Price
+	}
+	// Order instance types so that we get the best ranked instance types of the available offerings
+	sort.Slice(instanceTypes, func(i, j int) bool {
+		iRank, jRank := ranks[instanceTypes[i].Name], ranks[instanceTypes[j].Name]
+		if iRank.tier != jRank.tier {
+			return iRank.tier < jRank.tier
 		}
-		if iPrice == jPrice {
+		if iRank.value == jRank.value {
 			return instanceTypes[i].Name < instanceTypes[j].Name
 		}
-		return iPrice < jPrice
+		return iRank.value < jRank.value
 	})
 	return instanceTypes
 }
 
+// rankingValue returns the price of the instance type normalized by the ranking policy, where lower values are better.
+// It returns false if the instance type can't be ranked by the policy.
+func rankingValue(instanceType *cloudprovider.InstanceType, price float64, ranking *v1beta1.InstanceTypeRanking) (float64, bool) {
+	if ranking == nil {
+		return price, true
+	}
+	var units float64
+	switch ranking.Policy {
+	case v1beta1.InstanceTypeRankingPolicyPricePerVCPU:
+		units = instanceType.Capacity.Cpu().AsApproximateFloat64()
+	case v1beta1.InstanceTypeRankingPolicyPricePerGiB:
+		units = instanceType.Capacity.Memory().AsApproximateFloat64() / float64(1<<30)
+	case v1beta1.InstanceTypeRankingPolicyPricePerPerformanceScore:
+		units = float64(ranking.PerformanceScores[strings.Split(instanceType.Name, ".")[0]])
+	default:
+		return price, true
+	}
+	if units <= 0 {
+		return 0, false
+	}
+	return price / units, true
+}
+
 // filterInstanceTypes is used to provide filtering on the list of potential instance types to further limit it to those
 // that make the most sense given our specific AWS cloudprovider.
 func (p *Provider) filterInstanceTypes(nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType) []*cloudprovider.InstanceType {
@@ -490,9 +630,11 @@ func filterExoticInstanceTypes(instanceTypes []*cloudprovider.InstanceType, isMa
 			continue
 		}
 		if !resources.IsZero(it.Capacity[lo.Ternary(isMachine, v1alpha1.ResourceAWSNeuron, v1beta1.ResourceAWSNeuron)]) ||
+			!resources.IsZero(it.Capacity[lo.Ternary(isMachine, v1alpha1.ResourceAWSNeuronCore, v1beta1.ResourceAWSNeuronCore)]) ||
 			!resources.IsZero(it.Capacity[lo.Ternary(isMachine, v1alpha1.ResourceAMDGPU, v1beta1.ResourceAMDGPU)]) ||
 			!resources.IsZero(it.Capacity[lo.Ternary(isMachine, v1alpha1.ResourceNVIDIAGPU, v1beta1.ResourceNVIDIAGPU)]) ||
-			!resources.IsZero(it.Capacity[lo.Ternary(isMachine, v1alpha1.ResourceHabanaGaudi, v1beta1.ResourceHabanaGaudi)]) {
+			!resources.IsZero(it.Capacity[lo.Ternary(isMachine, v1alpha1.ResourceHabanaGaudi, v1beta1.ResourceHabanaGaudi)]) ||
+			hasMIGInstances(it) {
 			continue
 		}
 		genericInstanceTypes = append(genericInstanceTypes, it)
@@ -504,6 +646,17 @@ func filterExoticInstanceTypes(instanceTypes []*cloudprovider.InstanceType, isMa
 	return instanceTypes
 }
 
+// hasMIGInstances returns true if the GPUs of the instance type are partitioned into MIG instances, in which case
+// the instance type doesn't advertise nvidia.com/gpu
+func hasMIGInstances(it *cloudprovider.InstanceType) bool {
+	for name, quantity := range it.Capacity {
+		if strings.HasPrefix(string(name), v1beta1.ResourceNVIDIAMIGPrefix) && !resources.IsZero(quantity) {
+			return true
+		}
+	}
+	return false
+}
+
 func instancesFromOutput(out *ec2.DescribeInstancesOutput) ([]*Instance, error) {
 	if len(out.Reservations) == 0 {
 		return nil, cloudprovider.NewNodeClaimNotFoundError(fmt.Errorf("instance not found"))
@@ -0,0 +1,145 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instance
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/samber/lo"
+)
+
+// Inventory is an in-memory view of the instances launched by Karpenter. It's seeded by a single list of the instances,
+// kept current by the instances that Karpenter creates, tags and terminates and by the EC2 state change events that
+// arrive on the interruption queue, and periodically replaced by a full list to correct any drift from EC2.
+type Inventory struct {
+	mu      sync.RWMutex
+	entries map[string]*inventoryEntry
+	synced  bool
+}
+
+type inventoryEntry struct {
+	instance *Instance
+	// complete is false when the instance was only partially described, e.g. from the response to CreateFleet, in
+	// which case it's described again before being returned by Get
+	complete  bool
+	updatedAt time.Time
+}
+
+func NewInventory() *Inventory {
+	return &Inventory{entries: map[string]*inventoryEntry{}}
+}
+
+// Synced returns whether the inventory has been seeded by a full list of the instances
+func (i *Inventory) Synced() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.synced
+}
+
+// Get returns the instance with the passed id, and whether it was found and completely described. A terminated
+// instance is returned so that callers don't have to describe instances that are known to be gone.
+func (i *Inventory) Get(id string) (*Instance, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	entry, ok := i.entries[id]
+	if !ok || !entry.complete {
+		return nil, false
+	}
+	return copyInstance(entry.instance), true
+}
+
+// List returns the instances that haven't terminated, ordered by id
+func (i *Inventory) List() []*Instance {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	var instances []*Instance
+	for _, entry := range i.entries {
+		if entry.instance.State != ec2.InstanceStateNameTerminated {
+			instances = append(instances, copyInstance(entry.instance))
+		}
+	}
+	sort.Slice(instances, func(a, b int) bool { return instances[a].ID < instances[b].ID })
+	return instances
+}
+
+// Add adds or replaces the instance in the inventory
+func (i *Inventory) Add(instance *Instance, complete bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.entries[instance.ID] = &inventoryEntry{instance: copyInstance(instance), complete: complete, updatedAt: time.Now()}
+}
+
+// UpdateState updates the state of the instance if it's in the inventory. Terminated instances are kept until the
+// next full list so that later gets don't fall back to describing them.
+func (i *Inventory) UpdateState(id string, state string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	entry, ok := i.entries[id]
+	if !ok {
+		return
+	}
+	entry.instance = copyInstance(entry.instance)
+	entry.instance.State = state
+	entry.updatedAt = time.Now()
+}
+
+// UpdateTags merges the tags into the tags of the instance if it's in the inventory
+func (i *Inventory) UpdateTags(id string, tags map[string]string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	entry, ok := i.entries[id]
+	if !ok {
+		return
+	}
+	entry.instance = copyInstance(entry.instance)
+	entry.instance.Tags = lo.Assign(entry.instance.Tags, tags)
+	entry.updatedAt = time.Now()
+}
+
+// Replace replaces the inventory with the instances from a full list that started at the passed time. Entries that
+// were updated after the list started are kept since they may be more current than the list.
+func (i *Inventory) Replace(instances []*Instance, listedAt time.Time) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	entries := make(map[string]*inventoryEntry, len(instances))
+	for _, instance := range instances {
+		entries[instance.ID] = &inventoryEntry{instance: copyInstance(instance), complete: true, updatedAt: listedAt}
+	}
+	for id, entry := range i.entries {
+		if entry.updatedAt.After(listedAt) {
+			entries[id] = entry
+		}
+	}
+	i.entries = entries
+	i.synced = true
+}
+
+// Reset clears the inventory so that it's seeded again by the next list
+func (i *Inventory) Reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.entries = map[string]*inventoryEntry{}
+	i.synced = false
+}
+
+func copyInstance(instance *Instance) *Instance {
+	out := *instance
+	out.SecurityGroupIDs = append([]string(nil), instance.SecurityGroupIDs...)
+	out.Tags = lo.Assign(instance.Tags)
+	return &out
+}
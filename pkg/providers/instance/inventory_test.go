@@ -0,0 +1,161 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instance_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/types"
+
+	corev1beta1 "github.com/aws/karpenter-core/pkg/apis/v1beta1"
+	corecloudprovider "github.com/aws/karpenter-core/pkg/cloudprovider"
+	. "github.com/aws/karpenter-core/pkg/test/expectations"
+	"github.com/aws/karpenter/pkg/apis/settings"
+	"github.com/aws/karpenter/pkg/fake"
+	"github.com/aws/karpenter/pkg/providers/instance"
+	"github.com/aws/karpenter/pkg/test"
+)
+
+var _ = Describe("InstanceProvider/Inventory", func() {
+	var instanceID string
+	BeforeEach(func() {
+		ctx = settings.ToContext(ctx, test.Settings(test.SettingOptions{
+			InterruptionQueueName: lo.ToPtr("test-cluster"),
+		}))
+		instanceID = storeInstance()
+	})
+	It("should only list instances from EC2 to seed the inventory", func() {
+		for i := 0; i < 3; i++ {
+			instances, err := awsEnv.InstanceProvider.List(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instances).To(HaveLen(1))
+			Expect(instances[0].ID).To(Equal(instanceID))
+		}
+		Expect(awsEnv.EC2API.DescribeInstancesBehavior.Calls()).To(Equal(1))
+	})
+	It("should list instances from EC2 every time without an interruption queue", func() {
+		ctx = settings.ToContext(ctx, test.Settings())
+		for i := 0; i < 3; i++ {
+			_, err := awsEnv.InstanceProvider.List(ctx)
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(awsEnv.EC2API.DescribeInstancesBehavior.Calls()).To(Equal(3))
+	})
+	It("should get instances from the inventory once it's seeded", func() {
+		_, err := awsEnv.InstanceProvider.List(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		i, err := awsEnv.InstanceProvider.Get(ctx, instanceID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(i.ID).To(Equal(instanceID))
+		Expect(awsEnv.EC2API.DescribeInstancesBehavior.Calls()).To(Equal(1))
+	})
+	It("should describe instances that aren't in the inventory and add them", func() {
+		for i := 0; i < 3; i++ {
+			i, err := awsEnv.InstanceProvider.Get(ctx, instanceID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(i.ID).To(Equal(instanceID))
+		}
+		Expect(awsEnv.EC2API.DescribeInstancesBehavior.Calls()).To(Equal(1))
+	})
+	It("should stop returning instances once a terminated state change is received", func() {
+		_, err := awsEnv.InstanceProvider.List(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		awsEnv.InstanceProvider.UpdateInstanceState(instanceID, ec2.InstanceStateNameTerminated)
+
+		instances, err := awsEnv.InstanceProvider.List(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instances).To(BeEmpty())
+		_, err = awsEnv.InstanceProvider.Get(ctx, instanceID)
+		Expect(corecloudprovider.IsNodeClaimNotFoundError(err)).To(BeTrue())
+		Expect(awsEnv.EC2API.DescribeInstancesBehavior.Calls()).To(Equal(1))
+	})
+	It("should update the state of instances from state change events", func() {
+		_, err := awsEnv.InstanceProvider.List(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		awsEnv.InstanceProvider.UpdateInstanceState(instanceID, ec2.InstanceStateNameStopping)
+
+		i, err := awsEnv.InstanceProvider.Get(ctx, instanceID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(i.State).To(Equal(ec2.InstanceStateNameStopping))
+	})
+	It("should mark instances as shutting down when they're deleted", func() {
+		_, err := awsEnv.InstanceProvider.List(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(awsEnv.InstanceProvider.Delete(ctx, instanceID)).To(Succeed())
+
+		i, err := awsEnv.InstanceProvider.Get(ctx, instanceID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(i.State).To(Equal(ec2.InstanceStateNameShuttingDown))
+	})
+	It("should update the tags of instances when they're tagged", func() {
+		_, err := awsEnv.InstanceProvider.List(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(awsEnv.InstanceProvider.CreateTags(ctx, instanceID, map[string]string{"foo": "bar"})).To(Succeed())
+
+		i, err := awsEnv.InstanceProvider.Get(ctx, instanceID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(i.Tags).To(HaveKeyWithValue("foo", "bar"))
+		Expect(i.Tags).To(HaveKey(corev1beta1.NodePoolLabelKey))
+	})
+	It("should reconcile the inventory with a full list of the instances", func() {
+		_, err := awsEnv.InstanceProvider.List(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		// an instance that was launched without a state change event reaching the inventory
+		otherInstanceID := storeInstance()
+		instances, err := awsEnv.InstanceProvider.List(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instances).To(HaveLen(1))
+
+		result := ExpectReconcileSucceeded(ctx, instance.NewController(awsEnv.InstanceProvider), types.NamespacedName{})
+		Expect(result.RequeueAfter).ToNot(BeZero())
+		instances, err = awsEnv.InstanceProvider.List(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lo.Map(instances, func(i *instance.Instance, _ int) string { return i.ID })).To(ConsistOf(instanceID, otherInstanceID))
+	})
+})
+
+// storeInstance stores a running instance launched by Karpenter in the fake EC2 API and returns its id
+func storeInstance() string {
+	instanceID := fake.InstanceID()
+	awsEnv.EC2API.Instances.Store(instanceID, &ec2.Instance{
+		State: &ec2.InstanceState{
+			Name: aws.String(ec2.InstanceStateNameRunning),
+		},
+		Tags: []*ec2.Tag{
+			{
+				Key:   aws.String(fmt.Sprintf("kubernetes.io/cluster/%s", settings.FromContext(ctx).ClusterName)),
+				Value: aws.String("owned"),
+			},
+			{
+				Key:   aws.String(corev1beta1.NodePoolLabelKey),
+				Value: aws.String("default"),
+			},
+		},
+		PrivateDnsName: aws.String(fake.PrivateDNSName()),
+		Placement: &ec2.Placement{
+			AvailabilityZone: aws.String(fake.DefaultRegion),
+		},
+		LaunchTime:   aws.Time(time.Now().Add(-time.Minute)),
+		InstanceId:   aws.String(instanceID),
+		InstanceType: aws.String("m5.large"),
+	})
+	return instanceID
+}
@@ -0,0 +1,70 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancetype
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"knative.dev/pkg/logging"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corecontroller "github.com/aws/karpenter-core/pkg/operator/controller"
+)
+
+// snapshotUpdateInterval is the interval at which the instance type snapshot is refreshed. The instance type catalog
+// and offerings change rarely, so the snapshot only needs to be refreshed occasionally.
+const snapshotUpdateInterval = time.Hour
+
+// Controller periodically persists the instance type catalog and zonal offerings, so that instance types can be
+// resolved by the next Karpenter process to start even if the EC2 API is unavailable
+type Controller struct {
+	kubeClient           client.Client
+	instanceTypeProvider *Provider
+}
+
+func NewController(kubeClient client.Client, instanceTypeProvider *Provider) *Controller {
+	return &Controller{
+		kubeClient:           kubeClient,
+		instanceTypeProvider: instanceTypeProvider,
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	snapshot, err := c.instanceTypeProvider.UpdateSnapshot(ctx)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("updating instance type snapshot, %w", err)
+	}
+	cm, err := snapshot.ConfigMap()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := c.kubeClient.Patch(ctx, cm, client.Apply, client.ForceOwnership, client.FieldOwner(c.Name())); err != nil {
+		return reconcile.Result{}, fmt.Errorf("persisting instance type snapshot, %w", err)
+	}
+	logging.FromContext(ctx).With("instance-type-count", len(snapshot.InstanceTypes)).Debugf("persisted instance type snapshot")
+	return reconcile.Result{RequeueAfter: snapshotUpdateInterval}, nil
+}
+
+func (c *Controller) Name() string {
+	return "instancetype.snapshot"
+}
+
+func (c *Controller) Builder(_ context.Context, m manager.Manager) corecontroller.Builder {
+	return corecontroller.NewSingletonManagedBy(m)
+}
@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -33,21 +34,37 @@ import (
 	"github.com/mitchellh/hashstructure/v2"
 	"github.com/patrickmn/go-cache"
 	"github.com/samber/lo"
+	"golang.org/x/sync/singleflight"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"knative.dev/pkg/logging"
 
+	"github.com/aws/karpenter/pkg/providers/amifamily"
 	"github.com/aws/karpenter/pkg/providers/pricing"
 	"github.com/aws/karpenter/pkg/providers/subnet"
 
 	"github.com/aws/karpenter-core/pkg/cloudprovider"
+	"github.com/aws/karpenter-core/pkg/scheduling"
 	"github.com/aws/karpenter-core/pkg/utils/pretty"
 )
 
 const (
 	InstanceTypesCacheKey           = "types"
 	InstanceTypeZonesCacheKeyPrefix = "zones:"
+	OfferingsCacheKeyPrefix         = "offerings:"
+	InstanceTypesCacheKeyPrefix     = "instancetypes:"
+	OverheadsCacheKeyPrefix         = "overheads:"
+	ZonesCacheKey                   = "availability-zones"
 )
 
+// Zone is an availability zone, local zone or wavelength zone in the region
+type Zone struct {
+	Name string
+	// ID is the zone ID, which identifies the same physical location across accounts
+	ID string
+	// Type is one of availability-zone, local-zone or wavelength-zone
+	Type string
+}
+
 type Provider struct {
 	region          string
 	ec2api          ec2iface.EC2API
@@ -55,21 +72,32 @@ type Provider struct {
 	pricingProvider *pricing.Provider
 	// Has one cache entry for all the instance types (key: InstanceTypesCacheKey)
 	// Has one cache entry for all the zones for each subnet selector (key: InstanceTypesZonesCacheKeyPrefix:<hash_of_selector>)
+	// Has one cache entry for the IDs and types of all the zones in the region (key: ZonesCacheKey)
+	// Has one cache entry for the offerings of all the instance types for each set of zones and pricing (key: OfferingsCacheKeyPrefix:<seq_nums_and_hashes>)
+	// Has one cache entry for the instance types without their overhead for each set of offerings, instance type
+	// configuration of the node class, and max pods of the kubelet configuration (key: InstanceTypesCacheKeyPrefix:<seq_nums_and_hashes>)
+	// Has one cache entry for the overhead of all the instance types for each overhead configuration of the node class
+	// and kubelet configuration, regardless of offerings (key: OverheadsCacheKeyPrefix:<seq_nums_and_hashes>)
 	// Values cached *before* considering insufficient capacity errors from the unavailableOfferings cache.
-	// Fully initialized Instance Types are also cached based on the set of all instance types, zones, unavailableOfferings cache,
-	// node template, and kubelet configuration from the provisioner
+	// Fully initialized Instance Types are also cached, which only point to the instance types and overheads that they're
+	// composed of. Insufficient capacity errors are overlaid on top.
 
-	mu    sync.Mutex
 	cache *cache.Cache
+	// group deduplicates concurrent calls to EC2 for the same cache key
+	group singleflight.Group
+	// snapshot is the last known instance type catalog, which is used when the EC2 API is unavailable
+	mu       sync.RWMutex
+	snapshot *Snapshot
 
 	unavailableOfferings *awscache.UnavailableOfferings
+	overheads            *OverheadStore
 	cm                   *pretty.ChangeMonitor
 	// instanceTypesSeqNum is a monotonically increasing change counter used to avoid the expensive hashing operation on instance types
 	instanceTypesSeqNum uint64
 }
 
 func NewProvider(region string, cache *cache.Cache, ec2api ec2iface.EC2API, subnetProvider *subnet.Provider,
-	unavailableOfferingsCache *awscache.UnavailableOfferings, pricingProvider *pricing.Provider) *Provider {
+	unavailableOfferingsCache *awscache.UnavailableOfferings, pricingProvider *pricing.Provider, overheads *OverheadStore) *Provider {
 	return &Provider{
 		ec2api:               ec2api,
 		region:               region,
@@ -77,6 +105,7 @@ func NewProvider(region string, cache *cache.Cache, ec2api ec2iface.EC2API, subn
 		pricingProvider:      pricingProvider,
 		cache:                cache,
 		unavailableOfferings: unavailableOfferingsCache,
+		overheads:            overheads,
 		cm:                   pretty.NewChangeMonitor(),
 		instanceTypesSeqNum:  0,
 	}
@@ -93,20 +122,37 @@ func (p *Provider) List(ctx context.Context, kc *corev1beta1.KubeletConfiguratio
 	if err != nil {
 		return nil, err
 	}
+	zones, err := p.GetZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	offerings, offeringsKey := p.getOfferings(ctx, instanceTypes, instanceTypeZones, zones)
 
-	// Compute fully initialized instance types hash key
-	instanceTypeZonesHash, _ := hashstructure.Hash(instanceTypeZones, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
+	// Compute fully initialized instance types hash key. Instance types are shared by every node class that has the same
+	// offerings and instance type configuration, regardless of fields such as its subnets, security groups or tags.
 	kcHash, _ := hashstructure.Hash(kc, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
-	key := fmt.Sprintf("%d-%d-%s-%016x-%016x", p.instanceTypesSeqNum, p.unavailableOfferings.SeqNum, nodeClass.UID, instanceTypeZonesHash, kcHash)
+	key := fmt.Sprintf("%s-%d-%016x-%016x", offeringsKey, atomic.LoadUint64(&p.overheads.SeqNum), nodeClassHash(nodeClass), kcHash)
 
 	if item, ok := p.cache.Get(key); ok {
-		return item.([]*cloudprovider.InstanceType), nil
+		return p.applyUnavailableOfferings(item.([]*cloudprovider.InstanceType), zones), nil
 	}
-	// Reject any instance types that don't have any offerings due to zone
-	result := lo.Reject(lo.Map(instanceTypes, func(i *ec2.InstanceTypeInfo, _ int) *cloudprovider.InstanceType {
-		return NewInstanceType(ctx, i, kc, p.region, nodeClass, p.createOfferings(ctx, i, instanceTypeZones[aws.StringValue(i.InstanceType)]))
-	}), func(i *cloudprovider.InstanceType, _ int) bool {
-		return len(i.Offerings) == 0
+	// Instance types of architectures that the AMIs of the node class don't support can't be launched
+	amiFamily, err := amifamily.GetAMIFamily(nodeClass.Spec.AMIFamily, &amifamily.Options{CustomAMIFamily: nodeClass.Spec.CustomAMIFamily})
+	if err != nil {
+		return nil, err
+	}
+	supported := lo.Filter(instanceTypes, func(i *ec2.InstanceTypeInfo, _ int) bool { return supportsArchitecture(i, amiFamily) })
+	// The overheads are composed with the instance types rather than computed with them, since they depend on the
+	// reserved resources of the kubelet configuration while the instance types only depend on its max pods
+	overheads := p.getOverheads(ctx, supported, kc, nodeClass, kcHash)
+	result := lo.Map(p.getInstanceTypesWithoutOverhead(ctx, supported, kc, nodeClass, offerings, offeringsKey, zones), func(i *cloudprovider.InstanceType, _ int) *cloudprovider.InstanceType {
+		return &cloudprovider.InstanceType{
+			Name:         i.Name,
+			Requirements: i.Requirements,
+			Offerings:    i.Offerings,
+			Capacity:     i.Capacity,
+			Overhead:     overheads[i.Name],
+		}
 	})
 	for _, instanceType := range instanceTypes {
 		InstanceTypeVCPU.With(prometheus.Labels{
@@ -117,7 +163,144 @@ func (p *Provider) List(ctx context.Context, kc *corev1beta1.KubeletConfiguratio
 		}).Set(float64(aws.Int64Value(instanceType.MemoryInfo.SizeInMiB) * 1024 * 1024))
 	}
 	p.cache.SetDefault(key, result)
-	return result, nil
+	return p.applyUnavailableOfferings(result, zones), nil
+}
+
+// getInstanceTypesWithoutOverhead returns the instance types that have offerings, without their overhead. They're
+// shared by every node class and kubelet configuration with the same offerings, instance type configuration and max pods.
+func (p *Provider) getInstanceTypesWithoutOverhead(ctx context.Context, instanceTypes []*ec2.InstanceTypeInfo, kc *corev1beta1.KubeletConfiguration,
+	nodeClass *v1beta1.EC2NodeClass, offerings map[string]cloudprovider.Offerings, offeringsKey string, zones map[string]Zone) []*cloudprovider.InstanceType {
+
+	podsHash, _ := hashstructure.Hash(podsConfiguration(kc), hashstructure.FormatV2, nil)
+	key := fmt.Sprintf("%s%s-%d-%016x-%016x", InstanceTypesCacheKeyPrefix, offeringsKey, atomic.LoadUint64(&p.overheads.SeqNum), nodeClassHash(nodeClass), podsHash)
+	if item, ok := p.cache.Get(key); ok {
+		return item.([]*cloudprovider.InstanceType)
+	}
+	// Reject any instance types that don't have any offerings due to zone
+	result := lo.Reject(lo.Map(instanceTypes, func(i *ec2.InstanceTypeInfo, _ int) *cloudprovider.InstanceType {
+		return newInstanceTypeWithoutOverhead(ctx, i, kc, p.region, nodeClass, offerings[aws.StringValue(i.InstanceType)], zones, p.overheads)
+	}), func(i *cloudprovider.InstanceType, _ int) bool {
+		return len(i.Offerings) == 0
+	})
+	p.cache.SetDefault(key, result)
+	return result
+}
+
+// getOverheads returns the overhead of each instance type by name. Overheads don't depend on offerings, so they're
+// shared by every node class with the same overhead configuration, regardless of its subnets.
+func (p *Provider) getOverheads(ctx context.Context, instanceTypes []*ec2.InstanceTypeInfo, kc *corev1beta1.KubeletConfiguration,
+	nodeClass *v1beta1.EC2NodeClass, kcHash uint64) map[string]*cloudprovider.InstanceTypeOverhead {
+
+	key := fmt.Sprintf("%s%d-%d-%016x-%016x", OverheadsCacheKeyPrefix, atomic.LoadUint64(&p.instanceTypesSeqNum), atomic.LoadUint64(&p.overheads.SeqNum), overheadNodeClassHash(nodeClass), kcHash)
+	if item, ok := p.cache.Get(key); ok {
+		return item.(map[string]*cloudprovider.InstanceTypeOverhead)
+	}
+	overheads := lo.SliceToMap(instanceTypes, func(i *ec2.InstanceTypeInfo) (string, *cloudprovider.InstanceTypeOverhead) {
+		return aws.StringValue(i.InstanceType), newOverhead(ctx, i, kc, nodeClass, p.overheads)
+	})
+	p.cache.SetDefault(key, overheads)
+	return overheads
+}
+
+// getOfferings returns the offerings of every instance type in the zones that it's offered in, along with the cache key
+// that they're stored under. Offerings are computed before considering insufficient capacity errors, so that they can be
+// shared by every node class whose subnets are in the same zones.
+func (p *Provider) getOfferings(ctx context.Context, instanceTypes []*ec2.InstanceTypeInfo, instanceTypeZones map[string]sets.Set[string],
+	zones map[string]Zone) (map[string]cloudprovider.Offerings, string) {
+
+	instanceTypeZonesHash, _ := hashstructure.Hash(instanceTypeZones, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
+	zonesHash, _ := hashstructure.Hash(zones, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
+	// Prices are only part of the key through the pricing SeqNum, which only changes when prices change materially.
+	key := fmt.Sprintf("%s%d-%d-%016x-%016x", OfferingsCacheKeyPrefix, atomic.LoadUint64(&p.instanceTypesSeqNum), atomic.LoadUint64(&p.pricingProvider.SeqNum), instanceTypeZonesHash, zonesHash)
+	if item, ok := p.cache.Get(key); ok {
+		return item.(map[string]cloudprovider.Offerings), key
+	}
+	offerings := make(map[string]cloudprovider.Offerings, len(instanceTypes))
+	for _, instanceType := range instanceTypes {
+		name := aws.StringValue(instanceType.InstanceType)
+		offerings[name] = p.createOfferings(ctx, instanceType, instanceTypeZones[name], zones)
+	}
+	p.cache.SetDefault(key, offerings)
+	return offerings, key
+}
+
+// applyUnavailableOfferings overlays the offerings that have recently seen an insufficient capacity error from EC2 onto
+// the cached instance types. Only the instance types with unavailable offerings are copied, the rest are shared with
+// the cache and must not be mutated.
+func (p *Provider) applyUnavailableOfferings(instanceTypes []*cloudprovider.InstanceType, zones map[string]Zone) []*cloudprovider.InstanceType {
+	unavailable := p.unavailableOfferings.InstanceTypes()
+	if unavailable.Len() == 0 {
+		return instanceTypes
+	}
+	return lo.Map(instanceTypes, func(instanceType *cloudprovider.InstanceType, _ int) *cloudprovider.InstanceType {
+		if !unavailable.Has(instanceType.Name) {
+			return instanceType
+		}
+		offerings := make(cloudprovider.Offerings, 0, len(instanceType.Offerings))
+		for _, offering := range instanceType.Offerings {
+			offering.Available = offering.Available && !p.unavailableOfferings.IsUnavailable(instanceType.Name, offering.Zone, offering.CapacityType)
+			offerings = append(offerings, offering)
+		}
+		requirements := scheduling.Requirements{}
+		for key, requirement := range instanceType.Requirements {
+			requirements[key] = requirement
+		}
+		for key, requirement := range offeringRequirements(offerings, zones) {
+			requirements[key] = requirement
+		}
+		return &cloudprovider.InstanceType{
+			Name:         instanceType.Name,
+			Requirements: requirements,
+			Offerings:    offerings,
+			Capacity:     instanceType.Capacity,
+			Overhead:     instanceType.Overhead,
+		}
+	})
+}
+
+// nodeClassHash hashes the fields of the node class that instance types are computed from
+func nodeClassHash(nodeClass *v1beta1.EC2NodeClass) uint64 {
+	hash, _ := hashstructure.Hash(struct {
+		AMIFamily           *string
+		CustomAMIFamily     *v1beta1.CustomAMIFamily
+		BlockDeviceMappings []*v1beta1.BlockDeviceMapping
+		InstanceStorePolicy *string
+		GPUSharing          *v1beta1.GPUSharing
+		IsNodeTemplate      bool
+	}{
+		AMIFamily:           nodeClass.Spec.AMIFamily,
+		CustomAMIFamily:     nodeClass.Spec.CustomAMIFamily,
+		BlockDeviceMappings: nodeClass.Spec.BlockDeviceMappings,
+		InstanceStorePolicy: nodeClass.Spec.InstanceStorePolicy,
+		GPUSharing:          nodeClass.Spec.GPUSharing,
+		IsNodeTemplate:      nodeClass.IsNodeTemplate,
+	}, hashstructure.FormatV2, nil)
+	return hash
+}
+
+// overheadNodeClassHash hashes the fields of the node class that the overheads of instance types are computed from
+func overheadNodeClassHash(nodeClass *v1beta1.EC2NodeClass) uint64 {
+	hash, _ := hashstructure.Hash(struct {
+		AMIFamily           *string
+		CustomAMIFamily     *v1beta1.CustomAMIFamily
+		BlockDeviceMappings []*v1beta1.BlockDeviceMapping
+		InstanceStorePolicy *string
+	}{
+		AMIFamily:           nodeClass.Spec.AMIFamily,
+		CustomAMIFamily:     nodeClass.Spec.CustomAMIFamily,
+		BlockDeviceMappings: nodeClass.Spec.BlockDeviceMappings,
+		InstanceStorePolicy: nodeClass.Spec.InstanceStorePolicy,
+	}, hashstructure.FormatV2, nil)
+	return hash
+}
+
+// podsConfiguration is the part of the kubelet configuration that the capacity and requirements of instance types
+// depend on
+func podsConfiguration(kc *corev1beta1.KubeletConfiguration) struct{ MaxPods, PodsPerCore *int32 } {
+	if kc == nil {
+		return struct{ MaxPods, PodsPerCore *int32 }{}
+	}
+	return struct{ MaxPods, PodsPerCore *int32 }{MaxPods: kc.MaxPods, PodsPerCore: kc.PodsPerCore}
 }
 
 func (p *Provider) LivenessProbe(req *http.Request) error {
@@ -127,30 +310,36 @@ func (p *Provider) LivenessProbe(req *http.Request) error {
 	return p.pricingProvider.LivenessProbe(req)
 }
 
-func (p *Provider) createOfferings(ctx context.Context, instanceType *ec2.InstanceTypeInfo, zones sets.Set[string]) []cloudprovider.Offering {
+func (p *Provider) createOfferings(ctx context.Context, instanceType *ec2.InstanceTypeInfo, instanceTypeZones sets.Set[string], zones map[string]Zone) []cloudprovider.Offering {
 	var offerings []cloudprovider.Offering
-	for zone := range zones {
+	for zone := range instanceTypeZones {
+		zoneType := lo.Ternary(zones[zone].Type == "", v1beta1.ZoneTypeAvailabilityZone, zones[zone].Type)
 		// while usage classes should be a distinct set, there's no guarantee of that
 		for capacityType := range sets.NewString(aws.StringValueSlice(instanceType.SupportedUsageClasses)...) {
-			// exclude any offerings that have recently seen an insufficient capacity error from EC2
-			isUnavailable := p.unavailableOfferings.IsUnavailable(*instanceType.InstanceType, zone, capacityType)
 			var price float64
 			var ok bool
 			switch capacityType {
 			case ec2.UsageClassTypeSpot:
-				price, ok = p.pricingProvider.SpotPrice(*instanceType.InstanceType, zone)
+				// Most local and wavelength zones don't offer spot capacity, so they're only available once spot prices
+				// have been seen for them
+				if zoneType == v1beta1.ZoneTypeAvailabilityZone {
+					price, ok = p.pricingProvider.SpotPrice(*instanceType.InstanceType, zone)
+				} else {
+					price, ok = p.pricingProvider.ObservedSpotPrice(*instanceType.InstanceType, zone)
+				}
 			case ec2.UsageClassTypeOnDemand:
+				// On-demand prices are only known for the region, which underestimates the price in local and wavelength zones
 				price, ok = p.pricingProvider.OnDemandPrice(*instanceType.InstanceType)
 			default:
 				logging.FromContext(ctx).Errorf("Received unknown capacity type %s for instance type %s", capacityType, *instanceType.InstanceType)
 				continue
 			}
-			available := !isUnavailable && ok
+			// offerings that have recently seen an insufficient capacity error from EC2 are excluded by applyUnavailableOfferings
 			offerings = append(offerings, cloudprovider.Offering{
 				Zone:         zone,
 				CapacityType: capacityType,
 				Price:        price,
-				Available:    available,
+				Available:    ok,
 			})
 		}
 	}
@@ -158,13 +347,6 @@ func (p *Provider) createOfferings(ctx context.Context, instanceType *ec2.Instan
 }
 
 func (p *Provider) getInstanceTypeZones(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (map[string]sets.Set[string], error) {
-	// DO NOT REMOVE THIS LOCK ----------------------------------------------------------------------------
-	// We lock here so that multiple callers to getInstanceTypeZones do not result in cache misses and multiple
-	// calls to EC2 when we could have just made one call.
-	// TODO @joinnis: This can be made more efficient by holding a Read lock and only obtaining the Write if not in cache
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
 	subnetSelectorHash, err := hashstructure.Hash(nodeClass.Spec.SubnetSelectorTerms, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash the subnet selector: %w", err)
@@ -173,55 +355,119 @@ func (p *Provider) getInstanceTypeZones(ctx context.Context, nodeClass *v1beta1.
 	if cached, ok := p.cache.Get(cacheKey); ok {
 		return cached.(map[string]sets.Set[string]), nil
 	}
+	// Concurrent callers with the same subnet selector share a single call to EC2, while callers with different subnet
+	// selectors don't block each other
+	result, err, _ := p.group.Do(cacheKey, func() (interface{}, error) {
+		if cached, ok := p.cache.Get(cacheKey); ok {
+			return cached, nil
+		}
+		// Constrain AZs from subnets
+		subnets, err := p.subnetProvider.List(ctx, nodeClass)
+		if err != nil {
+			return nil, err
+		}
+		if len(subnets) == 0 {
+			return map[string]sets.Set[string](nil), nil
+		}
+		zones := sets.New(lo.Map(subnets, func(subnet *ec2.Subnet, _ int) string {
+			return aws.StringValue(subnet.AvailabilityZone)
+		})...)
 
-	// Constrain AZs from subnets
-	subnets, err := p.subnetProvider.List(ctx, nodeClass)
+		// Get offerings from EC2
+		instanceTypeZones, err := p.describeInstanceTypeOfferings(ctx, zones)
+		if err != nil {
+			if instanceTypeZones, err = p.snapshotInstanceTypeZones(ctx, zones, err); err != nil {
+				return nil, err
+			}
+		}
+		if p.cm.HasChanged("zonal-offerings", nodeClass.Spec.SubnetSelectorTerms) {
+			logging.FromContext(ctx).With("zones", sets.List(zones), "instance-type-count", len(instanceTypeZones), "node-template", nodeClass.Name).Debugf("discovered offerings for instance types")
+		}
+		p.cache.SetDefault(cacheKey, instanceTypeZones)
+		return instanceTypeZones, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	if len(subnets) == 0 {
-		return nil, nil
-	}
-	zones := sets.NewString(lo.Map(subnets, func(subnet *ec2.Subnet, _ int) string {
-		return aws.StringValue(subnet.AvailabilityZone)
-	})...)
+	return result.(map[string]sets.Set[string]), nil
+}
 
-	// Get offerings from EC2
-	instanceTypeZones := map[string]sets.Set[string]{}
-	if err := p.ec2api.DescribeInstanceTypeOfferingsPagesWithContext(ctx, &ec2.DescribeInstanceTypeOfferingsInput{LocationType: aws.String("availability-zone")},
-		func(output *ec2.DescribeInstanceTypeOfferingsOutput, lastPage bool) bool {
-			for _, offering := range output.InstanceTypeOfferings {
-				if zones.Has(aws.StringValue(offering.Location)) {
-					if _, ok := instanceTypeZones[aws.StringValue(offering.InstanceType)]; !ok {
-						instanceTypeZones[aws.StringValue(offering.InstanceType)] = sets.New[string]()
-					}
-					instanceTypeZones[aws.StringValue(offering.InstanceType)].Insert(aws.StringValue(offering.Location))
-				}
-			}
-			return true
-		}); err != nil {
-		return nil, fmt.Errorf("describing instance type zone offerings, %w", err)
+// GetZones returns the ID and type of each zone in the region that is available to the account, keyed by zone name
+func (p *Provider) GetZones(ctx context.Context) (map[string]Zone, error) {
+	if cached, ok := p.cache.Get(ZonesCacheKey); ok {
+		return cached.(map[string]Zone), nil
 	}
-	if p.cm.HasChanged("zonal-offerings", nodeClass.Spec.SubnetSelectorTerms) {
-		logging.FromContext(ctx).With("zones", zones.List(), "instance-type-count", len(instanceTypeZones), "node-template", nodeClass.Name).Debugf("discovered offerings for instance types")
+	result, err, _ := p.group.Do(ZonesCacheKey, func() (interface{}, error) {
+		if cached, ok := p.cache.Get(ZonesCacheKey); ok {
+			return cached, nil
+		}
+		zones, err := p.describeZones(ctx)
+		if err != nil {
+			if zones, err = p.snapshotZones(ctx, err); err != nil {
+				return nil, err
+			}
+		}
+		if p.cm.HasChanged("zones", zones) {
+			logging.FromContext(ctx).With("zones", lo.Keys(zones)).Debugf("discovered zones")
+		}
+		p.cache.SetDefault(ZonesCacheKey, zones)
+		return zones, nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	p.cache.SetDefault(cacheKey, instanceTypeZones)
-	return instanceTypeZones, nil
+	return result.(map[string]Zone), nil
 }
 
 // GetInstanceTypes retrieves all instance types from the ec2 DescribeInstanceTypes API using some opinionated filters
 func (p *Provider) GetInstanceTypes(ctx context.Context) ([]*ec2.InstanceTypeInfo, error) {
-	// DO NOT REMOVE THIS LOCK ----------------------------------------------------------------------------
-	// We lock here so that multiple callers to GetInstanceTypes do not result in cache misses and multiple
-	// calls to EC2 when we could have just made one call. This lock is here because multiple callers to EC2 result
-	// in A LOT of extra memory generated from the response for simultaneous callers.
-	// TODO @joinnis: This can be made more efficient by holding a Read lock and only obtaining the Write if not in cache
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
 	if cached, ok := p.cache.Get(InstanceTypesCacheKey); ok {
 		return cached.([]*ec2.InstanceTypeInfo), nil
 	}
+	// Concurrent callers share a single call to EC2 when the cache is empty, since multiple callers to EC2 result in
+	// A LOT of extra memory generated from the response for simultaneous callers.
+	result, err, _ := p.group.Do(InstanceTypesCacheKey, func() (interface{}, error) {
+		if cached, ok := p.cache.Get(InstanceTypesCacheKey); ok {
+			return cached, nil
+		}
+		instanceTypes, err := p.describeInstanceTypes(ctx)
+		if err != nil {
+			// The snapshot is cached like the EC2 API response so that the EC2 API is only retried once the cache expires
+			if instanceTypes, err = p.snapshotInstanceTypes(ctx, err); err != nil {
+				return nil, err
+			}
+		}
+		if p.cm.HasChanged("instance-types", instanceTypes) {
+			logging.FromContext(ctx).With(
+				"count", len(instanceTypes)).Debugf("discovered instance types")
+		}
+		atomic.AddUint64(&p.instanceTypesSeqNum, 1)
+		p.cache.SetDefault(InstanceTypesCacheKey, instanceTypes)
+		return instanceTypes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]*ec2.InstanceTypeInfo), nil
+}
+
+func (p *Provider) describeZones(ctx context.Context) (map[string]Zone, error) {
+	output, err := p.ec2api.DescribeAvailabilityZonesWithContext(ctx, &ec2.DescribeAvailabilityZonesInput{})
+	if err != nil {
+		return nil, fmt.Errorf("describing availability zones, %w", err)
+	}
+	zones := map[string]Zone{}
+	for _, zone := range output.AvailabilityZones {
+		zones[aws.StringValue(zone.ZoneName)] = Zone{
+			Name: aws.StringValue(zone.ZoneName),
+			ID:   aws.StringValue(zone.ZoneId),
+			Type: lo.FromPtrOr(zone.ZoneType, v1beta1.ZoneTypeAvailabilityZone),
+		}
+	}
+	return zones, nil
+}
+
+func (p *Provider) describeInstanceTypes(ctx context.Context) ([]*ec2.InstanceTypeInfo, error) {
 	var instanceTypes []*ec2.InstanceTypeInfo
 	if err := p.ec2api.DescribeInstanceTypesPagesWithContext(ctx, &ec2.DescribeInstanceTypesInput{
 		Filters: []*ec2.Filter{
@@ -240,11 +486,27 @@ func (p *Provider) GetInstanceTypes(ctx context.Context) ([]*ec2.InstanceTypeInf
 	}); err != nil {
 		return nil, fmt.Errorf("fetching instance types using ec2.DescribeInstanceTypes, %w", err)
 	}
-	if p.cm.HasChanged("instance-types", instanceTypes) {
-		logging.FromContext(ctx).With(
-			"count", len(instanceTypes)).Debugf("discovered instance types")
-	}
-	atomic.AddUint64(&p.instanceTypesSeqNum, 1)
-	p.cache.SetDefault(InstanceTypesCacheKey, instanceTypes)
+	InstanceTypeCatalogUpdateTimestamp.Set(float64(time.Now().Unix()))
 	return instanceTypes, nil
 }
+
+// describeInstanceTypeOfferings returns the zones that offer each instance type, limited to the given zones. If no
+// zones are given, the offerings in every zone in the region are returned.
+func (p *Provider) describeInstanceTypeOfferings(ctx context.Context, zones sets.Set[string]) (map[string]sets.Set[string], error) {
+	instanceTypeZones := map[string]sets.Set[string]{}
+	if err := p.ec2api.DescribeInstanceTypeOfferingsPagesWithContext(ctx, &ec2.DescribeInstanceTypeOfferingsInput{LocationType: aws.String("availability-zone")},
+		func(output *ec2.DescribeInstanceTypeOfferingsOutput, lastPage bool) bool {
+			for _, offering := range output.InstanceTypeOfferings {
+				if zones == nil || zones.Has(aws.StringValue(offering.Location)) {
+					if _, ok := instanceTypeZones[aws.StringValue(offering.InstanceType)]; !ok {
+						instanceTypeZones[aws.StringValue(offering.InstanceType)] = sets.New[string]()
+					}
+					instanceTypeZones[aws.StringValue(offering.InstanceType)].Insert(aws.StringValue(offering.Location))
+				}
+			}
+			return true
+		}); err != nil {
+		return nil, fmt.Errorf("describing instance type zone offerings, %w", err)
+	}
+	return instanceTypeZones, nil
+}
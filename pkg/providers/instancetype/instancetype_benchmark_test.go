@@ -0,0 +1,158 @@
+//go:build test_performance
+
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancetype_test
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/patrickmn/go-cache"
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	coresettings "github.com/aws/karpenter-core/pkg/apis/settings"
+	corev1beta1 "github.com/aws/karpenter-core/pkg/apis/v1beta1"
+	coretest "github.com/aws/karpenter-core/pkg/test"
+	"github.com/aws/karpenter/pkg/apis/settings"
+	"github.com/aws/karpenter/pkg/apis/v1beta1"
+	awscache "github.com/aws/karpenter/pkg/cache"
+	"github.com/aws/karpenter/pkg/fake"
+	"github.com/aws/karpenter/pkg/providers/instancetype"
+	"github.com/aws/karpenter/pkg/providers/pricing"
+	"github.com/aws/karpenter/pkg/providers/subnet"
+	"github.com/aws/karpenter/pkg/test"
+)
+
+func BenchmarkList80NodeClasses(b *testing.B) {
+	benchmarkList(b, 80, false)
+}
+
+func BenchmarkList10NodeClasses(b *testing.B) {
+	benchmarkList(b, 10, false)
+}
+
+func BenchmarkList80NodeClassesWithInsufficientCapacity(b *testing.B) {
+	benchmarkList(b, 80, true)
+}
+
+func BenchmarkList10NodeClassesWithInsufficientCapacity(b *testing.B) {
+	benchmarkList(b, 10, true)
+}
+
+// benchmarkList lists the instance types of every node class, optionally marking an offering as unavailable before
+// each iteration as happens whenever EC2 returns an insufficient capacity error
+func benchmarkList(b *testing.B, nodeClassCount int, insufficientCapacity bool) {
+	ctx := coresettings.ToContext(context.Background(), coretest.Settings())
+	ctx = settings.ToContext(ctx, test.Settings())
+
+	ec2api := fake.NewEC2API()
+	unavailableOfferings := awscache.NewUnavailableOfferings()
+	provider := instancetype.NewProvider(
+		fake.DefaultRegion,
+		cache.New(awscache.InstanceTypesAndZonesTTL, awscache.DefaultCleanupInterval),
+		ec2api,
+		subnet.NewProvider(ec2api, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval)),
+		unavailableOfferings,
+		pricing.NewProvider(ctx, &fake.PricingAPI{}, ec2api, fake.DefaultRegion),
+		instancetype.NewOverheadStore(),
+	)
+	nodeClasses := lo.Times(nodeClassCount, func(i int) *v1beta1.EC2NodeClass {
+		return test.EC2NodeClass(v1beta1.EC2NodeClass{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("nodeclass-%d", i), UID: types.UID(fmt.Sprint(i))},
+			Spec:       v1beta1.EC2NodeClassSpec{Tags: map[string]string{"nodeclass": fmt.Sprint(i)}},
+		})
+	})
+	kc := &corev1beta1.KubeletConfiguration{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if insufficientCapacity {
+			unavailableOfferings.MarkUnavailable(ctx, "InsufficientInstanceCapacity", "m5.large", "test-zone-1a", corev1beta1.CapacityTypeSpot)
+		}
+		for _, nodeClass := range nodeClasses {
+			if _, err := provider.List(ctx, kc, nodeClass); err != nil {
+				b.Fatalf("listing instance types, %v", err)
+			}
+		}
+	}
+	b.StopTimer()
+
+	var memStats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&memStats)
+	b.ReportMetric(float64(memStats.HeapInuse)/float64(1<<20), "heap-MiB")
+}
+
+// BenchmarkListParallelDifferentSubnetSelectors lists the instance types of node classes with different subnet
+// selectors in parallel, which shouldn't block each other while their offerings are retrieved from EC2
+func BenchmarkListParallelDifferentSubnetSelectors(b *testing.B) {
+	ctx := coresettings.ToContext(context.Background(), coretest.Settings())
+	ctx = settings.ToContext(ctx, test.Settings())
+
+	ec2api := &slowEC2API{EC2API: fake.NewEC2API()}
+	provider := instancetype.NewProvider(
+		fake.DefaultRegion,
+		cache.New(awscache.InstanceTypesAndZonesTTL, awscache.DefaultCleanupInterval),
+		ec2api,
+		subnet.NewProvider(ec2api, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval)),
+		awscache.NewUnavailableOfferings(),
+		pricing.NewProvider(ctx, &fake.PricingAPI{}, ec2api, fake.DefaultRegion),
+		instancetype.NewOverheadStore(),
+	)
+	kc := &corev1beta1.KubeletConfiguration{}
+	var selector atomic.Int64
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			nodeClass := test.EC2NodeClass(v1beta1.EC2NodeClass{
+				Spec: v1beta1.EC2NodeClassSpec{
+					// every node class selects the same subnets through a different selector
+					SubnetSelectorTerms: []v1beta1.SubnetSelectorTerm{
+						{Tags: map[string]string{"foo": "bar"}},
+						{Tags: map[string]string{"selector": fmt.Sprint(selector.Add(1))}},
+					},
+				},
+			})
+			if _, err := provider.List(ctx, kc, nodeClass); err != nil {
+				b.Fatalf("listing instance types, %v", err)
+			}
+		}
+	})
+}
+
+// ec2Latency is the simulated latency of the EC2 API, which callers used to be serialized behind
+const ec2Latency = 10 * time.Millisecond
+
+type slowEC2API struct {
+	ec2iface.EC2API
+}
+
+func (s *slowEC2API) DescribeInstanceTypeOfferingsPagesWithContext(ctx context.Context, input *ec2.DescribeInstanceTypeOfferingsInput,
+	fn func(*ec2.DescribeInstanceTypeOfferingsOutput, bool) bool, opts ...request.Option) error {
+	time.Sleep(ec2Latency)
+	return s.EC2API.DescribeInstanceTypeOfferingsPagesWithContext(ctx, input, fn, opts...)
+}
@@ -27,6 +27,7 @@ const (
 
 var (
 	InstanceTypeLabel = "instance_type"
+	AMIFamilyLabel    = "ami_family"
 
 	InstanceTypeVCPU = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -49,8 +50,28 @@ var (
 		[]string{
 			InstanceTypeLabel,
 		})
+
+	MemoryCapacityPredictionError = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: cloudProviderSubsystem,
+			Name:      "instance_type_memory_capacity_prediction_error_bytes",
+			Help:      "Difference, in bytes, between the predicted memory capacity of an instance type and the memory capacity reported by its most recently observed node. Positive values mean the capacity was overestimated.",
+		},
+		[]string{
+			InstanceTypeLabel,
+			AMIFamilyLabel,
+		})
+
+	InstanceTypeCatalogUpdateTimestamp = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: cloudProviderSubsystem,
+			Name:      "instance_type_catalog_updated_timestamp_seconds",
+			Help:      "Unix timestamp at which the instance type catalog in use was retrieved from the EC2 API. While the EC2 API is unavailable and the persisted instance type snapshot is used, the age of the catalog is the current time minus this value.",
+		})
 )
 
 func init() {
-	crmetrics.Registry.MustRegister(InstanceTypeVCPU, InstanceTypeMemory)
+	crmetrics.Registry.MustRegister(InstanceTypeVCPU, InstanceTypeMemory, MemoryCapacityPredictionError, InstanceTypeCatalogUpdateTimestamp)
 }
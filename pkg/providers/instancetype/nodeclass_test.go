@@ -106,6 +106,8 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 			v1.LabelArchStable:               "amd64",
 			corev1beta1.CapacityTypeLabelKey: "on-demand",
 			// Well Known to AWS
+			v1beta1.LabelTopologyZoneID:                       "testzone1a",
+			v1beta1.LabelTopologyZoneType:                     "availability-zone",
 			v1beta1.LabelInstanceHypervisor:                   "nitro",
 			v1beta1.LabelInstanceEncryptionInTransitSupported: "true",
 			v1beta1.LabelInstanceCategory:                     "g",
@@ -113,6 +115,10 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 			v1beta1.LabelInstanceFamily:                       "g4dn",
 			v1beta1.LabelInstanceSize:                         "8xlarge",
 			v1beta1.LabelInstanceCPU:                          "32",
+			v1beta1.LabelInstanceCPUManufacturer:              "intel",
+			v1beta1.LabelInstanceCPUSustainedClockSpeedMhz:    "2500",
+			v1beta1.LabelInstanceEBSBandwidth:                 "9500",
+			v1beta1.LabelInstanceENAExpress:                   "false",
 			v1beta1.LabelInstanceMemory:                       "131072",
 			v1beta1.LabelInstanceNetworkBandwidth:             "50000",
 			v1beta1.LabelInstancePods:                         "58",
@@ -124,6 +130,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 			v1beta1.LabelInstanceAcceleratorName:              "inferentia",
 			v1beta1.LabelInstanceAcceleratorManufacturer:      "aws",
 			v1beta1.LabelInstanceAcceleratorCount:             "1",
+			v1beta1.LabelInstanceNeuronCoreCount:              "4",
 			// Deprecated Labels
 			v1.LabelFailureDomainBetaRegion: fake.DefaultRegion,
 			v1.LabelFailureDomainBetaZone:   "test-zone-1a",
@@ -159,6 +166,8 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 			v1.LabelArchStable:               "amd64",
 			corev1beta1.CapacityTypeLabelKey: "on-demand",
 			// Well Known to AWS
+			v1beta1.LabelTopologyZoneID:                       "testzone1a",
+			v1beta1.LabelTopologyZoneType:                     "availability-zone",
 			v1beta1.LabelInstanceHypervisor:                   "nitro",
 			v1beta1.LabelInstanceEncryptionInTransitSupported: "true",
 			v1beta1.LabelInstanceCategory:                     "g",
@@ -166,6 +175,10 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 			v1beta1.LabelInstanceFamily:                       "g4dn",
 			v1beta1.LabelInstanceSize:                         "8xlarge",
 			v1beta1.LabelInstanceCPU:                          "32",
+			v1beta1.LabelInstanceCPUManufacturer:              "intel",
+			v1beta1.LabelInstanceCPUSustainedClockSpeedMhz:    "2500",
+			v1beta1.LabelInstanceEBSBandwidth:                 "9500",
+			v1beta1.LabelInstanceENAExpress:                   "false",
 			v1beta1.LabelInstanceMemory:                       "131072",
 			v1beta1.LabelInstanceNetworkBandwidth:             "50000",
 			v1beta1.LabelInstancePods:                         "58",
@@ -190,6 +203,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 					v1beta1.LabelInstanceAcceleratorCount,
 					v1beta1.LabelInstanceAcceleratorName,
 					v1beta1.LabelInstanceAcceleratorManufacturer,
+					v1beta1.LabelInstanceNeuronCoreCount,
 					v1.LabelWindowsBuild,
 				)).UnsortedList(), lo.Keys(corev1beta1.NormalizedLabels)...)))
 
@@ -210,6 +224,8 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 			v1.LabelArchStable:               "amd64",
 			corev1beta1.CapacityTypeLabelKey: "on-demand",
 			// Well Known to AWS
+			v1beta1.LabelTopologyZoneID:                       "testzone1a",
+			v1beta1.LabelTopologyZoneType:                     "availability-zone",
 			v1beta1.LabelInstanceHypervisor:                   "nitro",
 			v1beta1.LabelInstanceEncryptionInTransitSupported: "true",
 			v1beta1.LabelInstanceCategory:                     "inf",
@@ -217,12 +233,17 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 			v1beta1.LabelInstanceFamily:                       "inf1",
 			v1beta1.LabelInstanceSize:                         "2xlarge",
 			v1beta1.LabelInstanceCPU:                          "8",
+			v1beta1.LabelInstanceCPUManufacturer:              "intel",
+			v1beta1.LabelInstanceCPUSustainedClockSpeedMhz:    "3000",
+			v1beta1.LabelInstanceEBSBandwidth:                 "4750",
+			v1beta1.LabelInstanceENAExpress:                   "false",
 			v1beta1.LabelInstanceMemory:                       "16384",
 			v1beta1.LabelInstanceNetworkBandwidth:             "5000",
 			v1beta1.LabelInstancePods:                         "38",
 			v1beta1.LabelInstanceAcceleratorName:              "inferentia",
 			v1beta1.LabelInstanceAcceleratorManufacturer:      "aws",
 			v1beta1.LabelInstanceAcceleratorCount:             "1",
+			v1beta1.LabelInstanceNeuronCoreCount:              "4",
 			// Deprecated Labels
 			v1.LabelFailureDomainBetaRegion: fake.DefaultRegion,
 			v1.LabelFailureDomainBetaZone:   "test-zone-1a",
@@ -247,6 +268,37 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 		ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
 		ExpectScheduled(ctx, env.Client, pod)
 	})
+	It("should fail to list instance types when the AMI family isn't registered", func() {
+		nodeClass.Spec.AMIFamily = aws.String("Bottlerocekt")
+		_, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+		Expect(err).To(HaveOccurred())
+	})
+	It("should use the lower-cased cpu manufacturer that EC2 reports", func() {
+		instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+		Expect(err).To(BeNil())
+		manufacturers := lo.SliceToMap(instanceTypes, func(it *corecloudprovider.InstanceType) (string, string) {
+			return it.Name, it.Requirements.Get(v1beta1.LabelInstanceCPUManufacturer).Any()
+		})
+		Expect(manufacturers).To(HaveKeyWithValue("c6g.large", "aws"))
+		Expect(manufacturers).To(HaveKeyWithValue("t4g.medium", "aws"))
+		Expect(manufacturers).To(HaveKeyWithValue("m5.large", "intel"))
+		Expect(manufacturers).To(HaveKeyWithValue("m6idn.32xlarge", "intel"))
+	})
+	It("should not set the cpu manufacturer, clock speed and ebs bandwidth labels when they are unknown", func() {
+		infos, err := awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		m5, ok := lo.Find(infos, func(i *ec2.InstanceTypeInfo) bool { return aws.StringValue(i.InstanceType) == "m5.large" })
+		Expect(ok).To(BeTrue())
+		// Copy so that the cached instance type info isn't modified
+		info := *m5
+		info.ProcessorInfo = &ec2.ProcessorInfo{SupportedArchitectures: m5.ProcessorInfo.SupportedArchitectures}
+		info.EbsInfo = nil
+		it := instancetype.NewInstanceType(ctx, &info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
+		Expect(it.Requirements.Get(v1beta1.LabelInstanceCPUManufacturer).Operator()).To(Equal(v1.NodeSelectorOpDoesNotExist))
+		Expect(it.Requirements.Get(v1beta1.LabelInstanceCPUSustainedClockSpeedMhz).Operator()).To(Equal(v1.NodeSelectorOpDoesNotExist))
+		Expect(it.Requirements.Get(v1beta1.LabelInstanceEBSBandwidth).Operator()).To(Equal(v1.NodeSelectorOpDoesNotExist))
+		Expect(it.Requirements.Get(v1beta1.LabelInstanceENAExpress).Any()).To(Equal("false"))
+	})
 	It("should not launch AWS Pod ENI on a t3", func() {
 		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
 		pod := coretest.UnschedulablePod(coretest.PodOptions{
@@ -304,6 +356,92 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 			Expect(expected.Has(aws.StringValue(override.InstanceType))).To(BeTrue(), fmt.Sprintf("expected %s to exist in set", aws.StringValue(override.InstanceType)))
 		}
 	})
+	It("should order the instance types by price per vCPU and only consider the cheapest ones", func() {
+		instances := makeFakeInstances()
+		for _, info := range instances {
+			if strings.HasPrefix(aws.StringValue(info.InstanceType), "x2iedn.") {
+				info.VCpuInfo.DefaultVCpus = aws.Int64(128)
+			}
+		}
+		awsEnv.EC2API.DescribeInstanceTypesOutput.Set(&ec2.DescribeInstanceTypesOutput{
+			InstanceTypes: instances,
+		})
+		awsEnv.EC2API.DescribeInstanceTypeOfferingsOutput.Set(&ec2.DescribeInstanceTypeOfferingsOutput{
+			InstanceTypeOfferings: makeFakeInstanceOfferings(instances),
+		})
+		nodeClass.Spec.InstanceTypeRanking = &v1beta1.InstanceTypeRanking{Policy: v1beta1.InstanceTypeRankingPolicyPricePerVCPU}
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		pod := coretest.UnschedulablePod(coretest.PodOptions{
+			ResourceRequirements: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+				Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+			},
+		})
+		ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+		ExpectScheduled(ctx, env.Client, pod)
+		its, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+		Expect(err).To(BeNil())
+		// Metal instance types are filtered out before ordering when other instance types are available
+		its = lo.Reject(its, func(it *corecloudprovider.InstanceType, _ int) bool { return strings.HasSuffix(it.Name, ".metal") })
+		// Order all the instances by their price per vCPU
+		reqs := scheduling.NewNodeSelectorRequirements(nodePool.Spec.Template.Spec.Requirements...)
+		pricePerVCPU := func(it *corecloudprovider.InstanceType) float64 {
+			return it.Offerings.Requirements(reqs).Cheapest().Price / it.Capacity.Cpu().AsApproximateFloat64()
+		}
+		sort.Slice(its, func(i, j int) bool {
+			if pricePerVCPU(its[i]) == pricePerVCPU(its[j]) {
+				return its[i].Name < its[j].Name
+			}
+			return pricePerVCPU(its[i]) < pricePerVCPU(its[j])
+		})
+		expected := sets.NewString(lo.Map(its[:instance.MaxInstanceTypes], func(i *corecloudprovider.InstanceType, _ int) string {
+			return i.Name
+		})...)
+		Expect(expected.Has("x2iedn.xlarge")).To(BeTrue())
+		Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+		call := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+		Expect(call.LaunchTemplateConfigs).To(HaveLen(1))
+		Expect(call.LaunchTemplateConfigs[0].Overrides).To(HaveLen(instance.MaxInstanceTypes))
+		for _, override := range call.LaunchTemplateConfigs[0].Overrides {
+			Expect(expected.Has(aws.StringValue(override.InstanceType))).To(BeTrue(), fmt.Sprintf("expected %s to exist in set", aws.StringValue(override.InstanceType)))
+		}
+	})
+	It("should order scored instance families first when ranking by price per performance score", func() {
+		instances := makeFakeInstances()
+		awsEnv.EC2API.DescribeInstanceTypesOutput.Set(&ec2.DescribeInstanceTypesOutput{
+			InstanceTypes: instances,
+		})
+		awsEnv.EC2API.DescribeInstanceTypeOfferingsOutput.Set(&ec2.DescribeInstanceTypeOfferingsOutput{
+			InstanceTypeOfferings: makeFakeInstanceOfferings(instances),
+		})
+		nodeClass.Spec.InstanceTypeRanking = &v1beta1.InstanceTypeRanking{
+			Policy:            v1beta1.InstanceTypeRankingPolicyPricePerPerformanceScore,
+			PerformanceScores: map[string]int64{"x2iedn": 100},
+		}
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		pod := coretest.UnschedulablePod(coretest.PodOptions{
+			ResourceRequirements: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+				Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+			},
+		})
+		ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+		ExpectScheduled(ctx, env.Client, pod)
+		Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+		call := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+		Expect(call.LaunchTemplateConfigs).To(HaveLen(1))
+		Expect(call.LaunchTemplateConfigs[0].Overrides).To(HaveLen(instance.MaxInstanceTypes))
+		// All non-metal types of the scored family are expensive but must be sent to CreateFleet
+		overrides := sets.NewString(lo.Map(call.LaunchTemplateConfigs[0].Overrides, func(o *ec2.FleetLaunchTemplateOverridesRequest, _ int) string {
+			return aws.StringValue(o.InstanceType)
+		})...)
+		for _, info := range instances {
+			name := aws.StringValue(info.InstanceType)
+			if strings.HasPrefix(name, "x2iedn.") && !strings.HasSuffix(name, ".metal") {
+				Expect(overrides.Has(name)).To(BeTrue(), fmt.Sprintf("expected %s to exist in set", name))
+			}
+		}
+	})
 	It("should order the instance types by price and only consider the spot types that are cheaper than the cheapest on-demand", func() {
 		instances := makeFakeInstances()
 		awsEnv.EC2API.DescribeInstanceTypesOutput.Set(&ec2.DescribeInstanceTypesOutput{
@@ -555,6 +693,33 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 		}
 		Expect(nodeNames.Len()).To(Equal(2))
 	})
+	It("should launch instances for AWS Neuron core resource requests", func() {
+		nodeNames := sets.NewString()
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		pods := []*v1.Pod{
+			coretest.UnschedulablePod(coretest.PodOptions{
+				ResourceRequirements: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1beta1.ResourceAWSNeuronCore: resource.MustParse("4")},
+					Limits:   v1.ResourceList{v1beta1.ResourceAWSNeuronCore: resource.MustParse("4")},
+				},
+			}),
+			// Should pack onto same instance
+			coretest.UnschedulablePod(coretest.PodOptions{
+				ResourceRequirements: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1beta1.ResourceAWSNeuronCore: resource.MustParse("8")},
+					Limits:   v1.ResourceList{v1beta1.ResourceAWSNeuronCore: resource.MustParse("8")},
+				},
+			}),
+		}
+		ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+		for _, pod := range pods {
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(v1.LabelInstanceTypeStable, "inf1.6xlarge"))
+			Expect(node.Labels).To(HaveKeyWithValue(v1beta1.LabelInstanceNeuronCoreCount, "16"))
+			nodeNames.Insert(node.Name)
+		}
+		Expect(nodeNames.Len()).To(Equal(1))
+	})
 	It("should launch trn1 instances for AWS Neuron resource requests", func() {
 		nodeNames := sets.NewString()
 		nodePool.Spec.Template.Spec.Requirements = []v1.NodeSelectorRequirement{
@@ -588,7 +753,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 		instanceInfo, err := awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
 		Expect(err).To(BeNil())
 		for _, info := range instanceInfo {
-			it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+			it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 			Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 110))
 		}
 	})
@@ -596,7 +761,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 		instanceInfo, err := awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
 		Expect(err).To(BeNil())
 		for _, info := range instanceInfo {
-			it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+			it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 			Expect(it.Capacity.Pods().Value()).ToNot(BeNumerically("==", 110))
 		}
 	})
@@ -608,7 +773,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 			EnableENILimitedPodDensity: lo.ToPtr(true),
 		}))
 		for _, info := range instanceInfo {
-			it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, windowsNodeClass, nil)
+			it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, windowsNodeClass, nil, nil, nil)
 			Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 110))
 		}
 	})
@@ -667,7 +832,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 		})
 		Context("System Reserved Resources", func() {
 			It("should use defaults when no kubelet is specified", func() {
-				it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil)
+				it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
 				Expect(it.Overhead.SystemReserved.Cpu().String()).To(Equal("0"))
 				Expect(it.Overhead.SystemReserved.Memory().String()).To(Equal("0"))
 				Expect(it.Overhead.SystemReserved.StorageEphemeral().String()).To(Equal("0"))
@@ -680,7 +845,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 						v1.ResourceEphemeralStorage: resource.MustParse("10Gi"),
 					},
 				}
-				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 				Expect(it.Overhead.SystemReserved.Cpu().String()).To(Equal("2"))
 				Expect(it.Overhead.SystemReserved.Memory().String()).To(Equal("20Gi"))
 				Expect(it.Overhead.SystemReserved.StorageEphemeral().String()).To(Equal("10Gi"))
@@ -688,7 +853,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 		})
 		Context("Kube Reserved Resources", func() {
 			It("should use defaults when no kubelet is specified", func() {
-				it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil)
+				it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
 				Expect(it.Overhead.KubeReserved.Cpu().String()).To(Equal("80m"))
 				Expect(it.Overhead.KubeReserved.Memory().String()).To(Equal("893Mi"))
 				Expect(it.Overhead.KubeReserved.StorageEphemeral().String()).To(Equal("1Gi"))
@@ -705,12 +870,32 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 						v1.ResourceMemory:           resource.MustParse("10Gi"),
 						v1.ResourceEphemeralStorage: resource.MustParse("2Gi"),
 					},
-				}, fake.DefaultRegion, nodeClass, nil)
+				}, fake.DefaultRegion, nodeClass, nil, nil, nil)
 				Expect(it.Overhead.KubeReserved.Cpu().String()).To(Equal("2"))
 				Expect(it.Overhead.KubeReserved.Memory().String()).To(Equal("10Gi"))
 				Expect(it.Overhead.KubeReserved.StorageEphemeral().String()).To(Equal("2Gi"))
 			})
 		})
+		DescribeTable("should use the defaults of the AMI family when no kubelet is specified",
+			func(amiFamily string, customAMIFamily *v1beta1.CustomAMIFamily, maxPods *int32, systemReservedMemory, kubeReservedMemory, evictionThresholdMemory string) {
+				nodeClass.Spec.AMIFamily = lo.ToPtr(amiFamily)
+				nodeClass.Spec.CustomAMIFamily = customAMIFamily
+				it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{MaxPods: maxPods}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+				Expect(it.Overhead.SystemReserved.Memory().String()).To(Equal(systemReservedMemory))
+				Expect(it.Overhead.KubeReserved.Memory().String()).To(Equal(kubeReservedMemory))
+				Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal(evictionThresholdMemory))
+			},
+			Entry("AL2", v1beta1.AMIFamilyAL2, nil, nil, "0", "893Mi", "100Mi"),
+			Entry("AL2 with max pods", v1beta1.AMIFamilyAL2, nil, lo.ToPtr[int32](110), "0", "893Mi", "100Mi"),
+			Entry("AL2023", v1beta1.AMIFamilyAL2023, nil, nil, "0", "893Mi", "100Mi"),
+			Entry("Ubuntu", v1beta1.AMIFamilyUbuntu, nil, nil, "0", "893Mi", "100Mi"),
+			Entry("Bottlerocket", v1beta1.AMIFamilyBottlerocket, nil, nil, "0", "893Mi", "100Mi"),
+			Entry("Bottlerocket with max pods", v1beta1.AMIFamilyBottlerocket, nil, lo.ToPtr[int32](110), "0", "1465Mi", "100Mi"),
+			Entry("Windows2019", v1beta1.AMIFamilyWindows2019, nil, nil, "1536Mi", "1465Mi", "500Mi"),
+			Entry("Windows2022", v1beta1.AMIFamilyWindows2022, nil, nil, "1536Mi", "1465Mi", "500Mi"),
+			Entry("Custom", v1beta1.AMIFamilyCustom, nil, nil, "0", "893Mi", "100Mi"),
+			Entry("Custom without kube-reserved", v1beta1.AMIFamilyCustom, &v1beta1.CustomAMIFamily{KubeReservedModel: lo.ToPtr(v1beta1.KubeReservedModelNone)}, nil, "0", "0", "100Mi"),
+		)
 		Context("Eviction Thresholds", func() {
 			BeforeEach(func() {
 				ctx = settings.ToContext(ctx, test.Settings(test.SettingOptions{
@@ -730,7 +915,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 							instancetype.MemoryAvailable: "500Mi",
 						},
 					}
-					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("500Mi"))
 				})
 				It("should override eviction threshold when specified as a percentage value", func() {
@@ -745,7 +930,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 							instancetype.MemoryAvailable: "10%",
 						},
 					}
-					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 					Expect(it.Overhead.EvictionThreshold.Memory().Value()).To(BeNumerically("~", float64(it.Capacity.Memory().Value())*0.1, 10))
 				})
 				It("should consider the eviction threshold disabled when specified as 100%", func() {
@@ -760,7 +945,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 							instancetype.MemoryAvailable: "100%",
 						},
 					}
-					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("0"))
 				})
 				It("should used default eviction threshold for memory when evictionHard not specified", func() {
@@ -775,9 +960,15 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 							instancetype.MemoryAvailable: "50Mi",
 						},
 					}
-					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("50Mi"))
 				})
+				It("should use the default eviction threshold of the AMI family when evictionHard not specified", func() {
+					it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("100Mi"))
+					it = instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, windowsNodeClass, nil, nil, nil)
+					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("500Mi"))
+				})
 			})
 			Context("Eviction Soft", func() {
 				It("should override eviction threshold when specified as a quantity", func() {
@@ -792,7 +983,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 							instancetype.MemoryAvailable: "500Mi",
 						},
 					}
-					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("500Mi"))
 				})
 				It("should override eviction threshold when specified as a percentage value", func() {
@@ -810,7 +1001,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 							instancetype.MemoryAvailable: "10%",
 						},
 					}
-					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 					Expect(it.Overhead.EvictionThreshold.Memory().Value()).To(BeNumerically("~", float64(it.Capacity.Memory().Value())*0.1, 10))
 				})
 				It("should consider the eviction threshold disabled when specified as 100%", func() {
@@ -825,10 +1016,10 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 							instancetype.MemoryAvailable: "100%",
 						},
 					}
-					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("0"))
 				})
-				It("should ignore eviction threshold when using Bottlerocket AMI", func() {
+				It("should use the eviction soft threshold when using Bottlerocket AMI", func() {
 					nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
 					nodePool.Spec.Template.Spec.Kubelet = &corev1beta1.KubeletConfiguration{
 						SystemReserved: v1.ResourceList{
@@ -844,12 +1035,12 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 							instancetype.MemoryAvailable: "10Gi",
 						},
 					}
-					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
-					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("1Gi"))
+					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
+					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("10Gi"))
 				})
 			})
 			It("should take the default eviction threshold when none is specified", func() {
-				it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil)
+				it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
 				Expect(it.Overhead.EvictionThreshold.Cpu().String()).To(Equal("0"))
 				Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("100Mi"))
 				Expect(it.Overhead.EvictionThreshold.StorageEphemeral().AsApproximateFloat64()).To(BeNumerically("~", resources.Quantity("2Gi").AsApproximateFloat64()))
@@ -869,7 +1060,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 						instancetype.MemoryAvailable: "1Gi",
 					},
 				}
-				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 				Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("3Gi"))
 			})
 			It("should take the greater of evictionHard and evictionSoft for overhead as a value", func() {
@@ -887,7 +1078,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 						instancetype.MemoryAvailable: "5%",
 					},
 				}
-				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 				Expect(it.Overhead.EvictionThreshold.Memory().Value()).To(BeNumerically("~", float64(it.Capacity.Memory().Value())*0.05, 10))
 			})
 			It("should take the greater of evictionHard and evictionSoft for overhead with mixed percentage/value", func() {
@@ -905,7 +1096,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 						instancetype.MemoryAvailable: "1Gi",
 					},
 				}
-				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 				Expect(it.Overhead.EvictionThreshold.Memory().Value()).To(BeNumerically("~", float64(it.Capacity.Memory().Value())*0.1, 10))
 			})
 		})
@@ -914,11 +1105,11 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 			Expect(err).To(BeNil())
 			for _, info := range instanceInfo {
 				if *info.InstanceType == "t3.large" {
-					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 					Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 35))
 				}
 				if *info.InstanceType == "m6idn.32xlarge" {
-					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+					it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 					Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 345))
 				}
 			}
@@ -930,7 +1121,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 				MaxPods: ptr.Int32(10),
 			}
 			for _, info := range instanceInfo {
-				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 10))
 			}
 		})
@@ -945,7 +1136,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 				MaxPods: ptr.Int32(10),
 			}
 			for _, info := range instanceInfo {
-				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 10))
 			}
 		})
@@ -960,7 +1151,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 				return *info.InstanceType == "t3.large"
 			})
 			Expect(ok).To(Equal(true))
-			it := instancetype.NewInstanceType(ctx, t3Large, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+			it := instancetype.NewInstanceType(ctx, t3Large, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 			// t3.large
 			// maxInterfaces = 3
 			// maxIPv4PerInterface = 12
@@ -980,7 +1171,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 				return *info.InstanceType == "t3.large"
 			})
 			Expect(ok).To(Equal(true))
-			it := instancetype.NewInstanceType(ctx, t3Large, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+			it := instancetype.NewInstanceType(ctx, t3Large, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 			// t3.large
 			// maxInterfaces = 3
 			// maxIPv4PerInterface = 12
@@ -997,7 +1188,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 				PodsPerCore: ptr.Int32(1),
 			}
 			for _, info := range instanceInfo {
-				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", ptr.Int64Value(info.VCpuInfo.DefaultVCpus)))
 			}
 		})
@@ -1009,7 +1200,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 				MaxPods:     ptr.Int32(20),
 			}
 			for _, info := range instanceInfo {
-				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", lo.Min([]int64{20, ptr.Int64Value(info.VCpuInfo.DefaultVCpus) * 4})))
 			}
 		})
@@ -1021,7 +1212,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 				PodsPerCore: ptr.Int32(1),
 			}
 			for _, info := range instanceInfo {
-				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 				limitedPods := instancetype.ENILimitedPods(ctx, info)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", limitedPods.Value()))
 			}
@@ -1037,7 +1228,7 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 				PodsPerCore: ptr.Int32(0),
 			}
 			for _, info := range instanceInfo {
-				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil)
+				it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, fake.DefaultRegion, nodeClass, nil, nil, nil)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 110))
 			}
 		})
@@ -1338,6 +1529,127 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 			}
 			Expect(instanceTypeNames.Has("m5.xlarge"))
 		})
+		It("should overlay insufficient capacity errors without modifying the cached instance types", func() {
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			cached, ok := lo.Find(instanceTypes, func(i *corecloudprovider.InstanceType) bool { return i.Name == "m5.large" })
+			Expect(ok).To(BeTrue())
+			unaffected, ok := lo.Find(instanceTypes, func(i *corecloudprovider.InstanceType) bool { return i.Name == "m5.xlarge" })
+			Expect(ok).To(BeTrue())
+
+			awsEnv.UnavailableOfferingsCache.MarkUnavailable(ctx, "InsufficientInstanceCapacity", "m5.large", "test-zone-1a", corev1beta1.CapacityTypeOnDemand)
+			awsEnv.UnavailableOfferingsCache.MarkUnavailable(ctx, "InsufficientInstanceCapacity", "m5.large", "test-zone-1a", corev1beta1.CapacityTypeSpot)
+			instanceTypes, err = awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			overlaid, ok := lo.Find(instanceTypes, func(i *corecloudprovider.InstanceType) bool { return i.Name == "m5.large" })
+			Expect(ok).To(BeTrue())
+			Expect(lo.Map(overlaid.Offerings.Available(), func(o corecloudprovider.Offering, _ int) string { return o.Zone })).ToNot(ContainElement("test-zone-1a"))
+			Expect(overlaid.Requirements.Get(v1.LabelTopologyZone).Has("test-zone-1a")).To(BeFalse())
+			Expect(overlaid.Requirements.Get(v1beta1.LabelTopologyZoneID).Has("testzone1a")).To(BeFalse())
+			// instance types without unavailable offerings are shared with the cache
+			Expect(instanceTypes).To(ContainElement(BeIdenticalTo(unaffected)))
+			Expect(lo.Map(cached.Offerings.Available(), func(o corecloudprovider.Offering, _ int) string { return o.Zone })).To(ContainElement("test-zone-1a"))
+			Expect(cached.Requirements.Get(v1.LabelTopologyZone).Has("test-zone-1a")).To(BeTrue())
+
+			awsEnv.UnavailableOfferingsCache.Delete("m5.large", "test-zone-1a", corev1beta1.CapacityTypeOnDemand)
+			awsEnv.UnavailableOfferingsCache.Delete("m5.large", "test-zone-1a", corev1beta1.CapacityTypeSpot)
+			instanceTypes, err = awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instanceTypes).To(ContainElement(BeIdenticalTo(cached)))
+		})
+	})
+	Context("Caching", func() {
+		It("should share instance types between node classes with the same instance type configuration", func() {
+			otherNodeClass := test.EC2NodeClass(v1beta1.EC2NodeClass{
+				Spec: v1beta1.EC2NodeClassSpec{
+					Tags: map[string]string{"team": "other"},
+				},
+			})
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			otherInstanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, otherNodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(otherInstanceTypes).To(HaveLen(len(instanceTypes)))
+			for i := range instanceTypes {
+				Expect(otherInstanceTypes[i]).To(BeIdenticalTo(instanceTypes[i]))
+			}
+		})
+		It("should not share instance types between node classes with a different instance type configuration", func() {
+			otherNodeClass := test.EC2NodeClass(v1beta1.EC2NodeClass{
+				Spec: v1beta1.EC2NodeClassSpec{
+					AMIFamily: aws.String(v1beta1.AMIFamilyBottlerocket),
+				},
+			})
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			otherInstanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, otherNodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(otherInstanceTypes[0]).ToNot(BeIdenticalTo(instanceTypes[0]))
+		})
+		It("should not reuse instance types after the custom AMI family changes", func() {
+			nodeClass.Spec.AMIFamily = aws.String(v1beta1.AMIFamilyCustom)
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			it, ok := lo.Find(instanceTypes, func(i *corecloudprovider.InstanceType) bool { return i.Name == "m5.xlarge" })
+			Expect(ok).To(BeTrue())
+			Expect(it.Overhead.KubeReserved.Cpu().String()).To(Equal("80m"))
+			Expect(it.Requirements.Get(v1.LabelOSStable).Any()).To(Equal(string(v1.Linux)))
+
+			nodeClass.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{
+				KubeReservedModel: aws.String(v1beta1.KubeReservedModelNone),
+				OperatingSystem:   aws.String(string(v1.Windows)),
+			}
+			instanceTypes, err = awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			it, ok = lo.Find(instanceTypes, func(i *corecloudprovider.InstanceType) bool { return i.Name == "m5.xlarge" })
+			Expect(ok).To(BeTrue())
+			Expect(it.Overhead.KubeReserved.Cpu().IsZero()).To(BeTrue())
+			Expect(it.Requirements.Get(v1.LabelOSStable).Any()).To(Equal(string(v1.Windows)))
+		})
+		It("should share requirements and capacity between kubelet configurations that only differ in reserved resources", func() {
+			kubeReserved := &corev1beta1.KubeletConfiguration{KubeReserved: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}}
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, &corev1beta1.KubeletConfiguration{}, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			otherInstanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, kubeReserved, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(otherInstanceTypes).To(HaveLen(len(instanceTypes)))
+			for i := range instanceTypes {
+				Expect(otherInstanceTypes[i].Requirements.Get(v1.LabelInstanceTypeStable)).To(BeIdenticalTo(instanceTypes[i].Requirements.Get(v1.LabelInstanceTypeStable)))
+				Expect(otherInstanceTypes[i].Overhead.KubeReserved.Cpu().String()).To(Equal("1"))
+				Expect(instanceTypes[i].Overhead.KubeReserved.Cpu().String()).ToNot(Equal("1"))
+			}
+		})
+		It("should share overheads between node classes that only differ in their instance type configuration", func() {
+			nodeClass.Spec.AMIFamily = aws.String(v1beta1.AMIFamilyBottlerocket)
+			otherNodeClass := test.EC2NodeClass(v1beta1.EC2NodeClass{
+				Spec: v1beta1.EC2NodeClassSpec{
+					AMIFamily:  aws.String(v1beta1.AMIFamilyBottlerocket),
+					GPUSharing: &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyTimeSlicing, Replicas: aws.Int32(4)},
+				},
+			})
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			otherInstanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, otherNodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(otherInstanceTypes).To(HaveLen(len(instanceTypes)))
+			for i := range instanceTypes {
+				Expect(otherInstanceTypes[i]).ToNot(BeIdenticalTo(instanceTypes[i]))
+				Expect(otherInstanceTypes[i].Overhead).To(BeIdenticalTo(instanceTypes[i].Overhead))
+			}
+		})
+		It("should share offerings between node classes with subnets in the same zones", func() {
+			otherNodeClass := test.EC2NodeClass(v1beta1.EC2NodeClass{
+				Spec: v1beta1.EC2NodeClassSpec{
+					AMIFamily: aws.String(v1beta1.AMIFamilyBottlerocket),
+				},
+			})
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			otherInstanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, otherNodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(otherInstanceTypes[0].Name).To(Equal(instanceTypes[0].Name))
+			Expect(&otherInstanceTypes[0].Offerings[0]).To(BeIdenticalTo(&instanceTypes[0].Offerings[0]))
+		})
 	})
 	Context("CapacityType", func() {
 		It("should default to on-demand", func() {
@@ -1409,6 +1721,219 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 			Expect(node.Labels).To(HaveKeyWithValue(corev1beta1.NodePoolLabelKey, nodePool.Name))
 		})
 	})
+	Context("Zones", func() {
+		BeforeEach(func() {
+			awsEnv.EC2API.DescribeAvailabilityZonesOutput.Set(&ec2.DescribeAvailabilityZonesOutput{AvailabilityZones: []*ec2.AvailabilityZone{
+				{ZoneName: aws.String("test-zone-1a"), ZoneId: aws.String("testzone1a"), ZoneType: aws.String(v1beta1.ZoneTypeAvailabilityZone)},
+				{ZoneName: aws.String("test-zone-1b"), ZoneId: aws.String("testzone1b"), ZoneType: aws.String(v1beta1.ZoneTypeAvailabilityZone)},
+				{ZoneName: aws.String("test-zone-1-lz1a"), ZoneId: aws.String("testzone1-lz1a"), ZoneType: aws.String(v1beta1.ZoneTypeLocalZone)},
+			}})
+			awsEnv.EC2API.DescribeSubnetsOutput.Set(&ec2.DescribeSubnetsOutput{Subnets: []*ec2.Subnet{
+				{SubnetId: aws.String("subnet-test1"), AvailabilityZone: aws.String("test-zone-1a"), AvailabilityZoneId: aws.String("testzone1a"), AvailableIpAddressCount: aws.Int64(100)},
+				{SubnetId: aws.String("subnet-test2"), AvailabilityZone: aws.String("test-zone-1b"), AvailabilityZoneId: aws.String("testzone1b"), AvailableIpAddressCount: aws.Int64(100)},
+				{SubnetId: aws.String("subnet-test3"), AvailabilityZone: aws.String("test-zone-1-lz1a"), AvailabilityZoneId: aws.String("testzone1-lz1a"), AvailableIpAddressCount: aws.Int64(100)},
+			}})
+			awsEnv.EC2API.DescribeInstanceTypeOfferingsOutput.Set(&ec2.DescribeInstanceTypeOfferingsOutput{
+				InstanceTypeOfferings: lo.Map([]string{"test-zone-1a", "test-zone-1b", "test-zone-1-lz1a"}, func(zone string, _ int) *ec2.InstanceTypeOffering {
+					return &ec2.InstanceTypeOffering{InstanceType: aws.String("m5.large"), Location: aws.String(zone)}
+				}),
+			})
+		})
+		It("should launch into the zone matching the zone ID", func() {
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{NodeSelector: map[string]string{v1beta1.LabelTopologyZoneID: "testzone1b"}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(SatisfyAll(
+				HaveKeyWithValue(v1.LabelTopologyZone, "test-zone-1b"),
+				HaveKeyWithValue(v1beta1.LabelTopologyZoneID, "testzone1b"),
+				HaveKeyWithValue(v1beta1.LabelTopologyZoneType, v1beta1.ZoneTypeAvailabilityZone),
+			))
+		})
+		It("should launch on-demand capacity into a local zone", func() {
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{NodeSelector: map[string]string{v1beta1.LabelTopologyZoneType: v1beta1.ZoneTypeLocalZone}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(SatisfyAll(
+				HaveKeyWithValue(v1.LabelTopologyZone, "test-zone-1-lz1a"),
+				HaveKeyWithValue(v1beta1.LabelTopologyZoneID, "testzone1-lz1a"),
+				HaveKeyWithValue(v1beta1.LabelTopologyZoneType, v1beta1.ZoneTypeLocalZone),
+				HaveKeyWithValue(corev1beta1.CapacityTypeLabelKey, corev1beta1.CapacityTypeOnDemand),
+			))
+		})
+		It("should not offer spot capacity in a local zone without a spot price", func() {
+			nodePool.Spec.Template.Spec.Requirements = []v1.NodeSelectorRequirement{
+				{Key: corev1beta1.CapacityTypeLabelKey, Operator: v1.NodeSelectorOpIn, Values: []string{corev1beta1.CapacityTypeSpot}},
+			}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{NodeSelector: map[string]string{v1beta1.LabelTopologyZoneType: v1beta1.ZoneTypeLocalZone}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
+		It("should offer spot capacity in a local zone with a spot price", func() {
+			now := time.Now()
+			awsEnv.EC2API.DescribeSpotPriceHistoryOutput.Set(&ec2.DescribeSpotPriceHistoryOutput{
+				SpotPriceHistory: []*ec2.SpotPrice{
+					{
+						AvailabilityZone: aws.String("test-zone-1-lz1a"),
+						InstanceType:     aws.String("m5.large"),
+						SpotPrice:        aws.String("0.004"),
+						Timestamp:        &now,
+					},
+				},
+			})
+			Expect(awsEnv.PricingProvider.UpdateSpotPricing(ctx)).To(Succeed())
+			nodePool.Spec.Template.Spec.Requirements = []v1.NodeSelectorRequirement{
+				{Key: corev1beta1.CapacityTypeLabelKey, Operator: v1.NodeSelectorOpIn, Values: []string{corev1beta1.CapacityTypeSpot}},
+			}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{NodeSelector: map[string]string{v1beta1.LabelTopologyZoneType: v1beta1.ZoneTypeLocalZone}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(SatisfyAll(
+				HaveKeyWithValue(v1.LabelTopologyZone, "test-zone-1-lz1a"),
+				HaveKeyWithValue(corev1beta1.CapacityTypeLabelKey, corev1beta1.CapacityTypeSpot),
+			))
+		})
+	})
+	Context("GPU Sharing", func() {
+		var info *ec2.InstanceTypeInfo
+		BeforeEach(func() {
+			nodeClass.Spec.AMIFamily = aws.String(v1beta1.AMIFamilyBottlerocket)
+			instanceInfo, err := awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
+			Expect(err).To(BeNil())
+			p3, ok := lo.Find(instanceInfo, func(i *ec2.InstanceTypeInfo) bool {
+				return aws.StringValue(i.InstanceType) == "p3.8xlarge"
+			})
+			Expect(ok).To(BeTrue())
+			// Copy so that the cached instance type info isn't modified
+			info = lo.ToPtr(*p3)
+		})
+		a100 := func() *ec2.GpuInfo {
+			return &ec2.GpuInfo{
+				Gpus: []*ec2.GpuDeviceInfo{{
+					Name:         aws.String("A100"),
+					Manufacturer: aws.String("NVIDIA"),
+					Count:        aws.Int64(8),
+					MemoryInfo:   &ec2.GpuDeviceMemoryInfo{SizeInMiB: aws.Int64(40960)},
+				}},
+				TotalGpuMemoryInMiB: aws.Int64(327680),
+			}
+		}
+		It("should advertise each GPU as the time-slicing replicas", func() {
+			nodeClass.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyTimeSlicing, Replicas: aws.Int32(4)}
+			it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+			Expect(it.Capacity.Name(v1beta1.ResourceNVIDIAGPU, resource.DecimalSI).Value()).To(BeNumerically("==", 16))
+		})
+		It("should advertise the MIG instances that fit on each GPU", func() {
+			info.GpuInfo = a100()
+			nodeClass.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyMIG, MIGProfile: aws.String("1g.5gb")}
+			it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+			Expect(it.Capacity.Name("nvidia.com/mig-1g.5gb", resource.DecimalSI).Value()).To(BeNumerically("==", 56))
+			Expect(it.Capacity.Name(v1beta1.ResourceNVIDIAGPU, resource.DecimalSI).Value()).To(BeNumerically("==", 0))
+		})
+		It("should limit the MIG instances by the memory of the GPU", func() {
+			info.GpuInfo = a100()
+			nodeClass.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyMIG, MIGProfile: aws.String("3g.20gb")}
+			it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+			Expect(it.Capacity.Name("nvidia.com/mig-3g.20gb", resource.DecimalSI).Value()).To(BeNumerically("==", 16))
+		})
+		It("should advertise whole GPUs when the MIG profile doesn't match the GPU memory", func() {
+			info.GpuInfo = a100()
+			nodeClass.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyMIG, MIGProfile: aws.String("1g.12gb")}
+			it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+			Expect(it.Capacity).ToNot(HaveKey(v1.ResourceName("nvidia.com/mig-1g.12gb")))
+			Expect(it.Capacity.Name(v1beta1.ResourceNVIDIAGPU, resource.DecimalSI).Value()).To(BeNumerically("==", 8))
+		})
+		It("should advertise whole GPUs when the GPU isn't MIG capable", func() {
+			nodeClass.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyMIG, MIGProfile: aws.String("1g.5gb")}
+			it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+			Expect(it.Capacity).ToNot(HaveKey(v1.ResourceName("nvidia.com/mig-1g.5gb")))
+			Expect(it.Capacity.Name(v1beta1.ResourceNVIDIAGPU, resource.DecimalSI).Value()).To(BeNumerically("==", 4))
+		})
+		It("should not advertise GPU resources for instance types without GPUs", func() {
+			nodeClass.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyTimeSlicing, Replicas: aws.Int32(4)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{
+				NodeSelector: map[string]string{v1.LabelInstanceTypeStable: "m5.large"},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Status.Capacity.Name(v1beta1.ResourceNVIDIAGPU, resource.DecimalSI).Value()).To(BeNumerically("==", 0))
+		})
+	})
+	Context("Custom AMI Family", func() {
+		var info *ec2.InstanceTypeInfo
+		BeforeEach(func() {
+			nodeClass.Spec.AMIFamily = aws.String(v1beta1.AMIFamilyCustom)
+			instanceInfo, err := awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
+			Expect(err).To(BeNil())
+			var ok bool
+			info, ok = lo.Find(instanceInfo, func(i *ec2.InstanceTypeInfo) bool {
+				return aws.StringValue(i.InstanceType) == "m5.xlarge"
+			})
+			Expect(ok).To(BeTrue())
+		})
+		It("should set pods to 110 when the custom AMI family doesn't support ENI-limited pod density", func() {
+			nodeClass.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{
+				FeatureFlags: &v1beta1.CustomAMIFamilyFeatureFlags{SupportsENILimitedPodDensity: aws.Bool(false)},
+			}
+			it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+			Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 110))
+		})
+		It("should use ENI-limited pod density when the custom AMI family doesn't declare feature flags", func() {
+			it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+			Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 58))
+		})
+		It("should not reserve resources for kubernetes system daemons when the kube-reserved model is None", func() {
+			nodeClass.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{KubeReservedModel: aws.String(v1beta1.KubeReservedModelNone)}
+			it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+			Expect(it.Overhead.KubeReserved.Cpu().IsZero()).To(BeTrue())
+			Expect(it.Overhead.KubeReserved.Memory().IsZero()).To(BeTrue())
+			Expect(it.Overhead.KubeReserved.StorageEphemeral().IsZero()).To(BeTrue())
+		})
+		It("should use the EKS kube-reserved model by default", func() {
+			it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+			Expect(it.Overhead.KubeReserved.Cpu().String()).To(Equal("80m"))
+			Expect(it.Overhead.KubeReserved.Memory().String()).To(Equal("893Mi"))
+		})
+		It("should not list instance types of an architecture that the custom AMI family doesn't support", func() {
+			nodeClass.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{Architectures: []string{corev1beta1.ArchitectureArm64}}
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, &corev1beta1.KubeletConfiguration{}, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instanceTypes).ToNot(BeEmpty())
+			Expect(lo.Map(instanceTypes, func(i *corecloudprovider.InstanceType, _ int) string { return i.Name })).ToNot(ContainElement("m5.xlarge"))
+			for _, it := range instanceTypes {
+				Expect(it.Requirements.Get(v1.LabelArchStable).Any()).To(Equal(corev1beta1.ArchitectureArm64))
+			}
+		})
+		It("should be launchable for an architecture that the custom AMI family supports", func() {
+			nodeClass.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{Architectures: []string{corev1beta1.ArchitectureAmd64}}
+			it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+			Expect(it.Requirements.Get(v1.LabelArchStable).Any()).To(Equal(corev1beta1.ArchitectureAmd64))
+		})
+		It("should use the operating system of the custom AMI family", func() {
+			nodeClass.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{OperatingSystem: aws.String(string(v1.Windows))}
+			it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+			Expect(it.Requirements.Get(v1.LabelOSStable).Any()).To(Equal(string(v1.Windows)))
+		})
+		It("should use the volume size of the ephemeral block device of the custom AMI family", func() {
+			nodeClass.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{EphemeralBlockDevice: aws.String("/dev/xvdb")}
+			nodeClass.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{
+				{
+					DeviceName: aws.String("/dev/xvdb"),
+					EBS:        &v1beta1.BlockDevice{VolumeSize: lo.ToPtr(resource.MustParse("100Gi"))},
+				},
+				{
+					DeviceName: aws.String("/dev/xvdc"),
+					EBS:        &v1beta1.BlockDevice{VolumeSize: lo.ToPtr(resource.MustParse("50Gi"))},
+				},
+			}
+			it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+			Expect(*it.Capacity.StorageEphemeral()).To(Equal(resource.MustParse("100Gi")))
+		})
+	})
 	Context("Ephemeral Storage", func() {
 		BeforeEach(func() {
 			nodeClass.Spec.AMIFamily = aws.String(v1beta1.AMIFamilyAL2)
@@ -1421,6 +1946,55 @@ var _ = Describe("NodeClass/InstanceTypes", func() {
 				},
 			}
 		})
+		It("should use the total instance store size when the instance store policy is RAID0", func() {
+			nodeClass.Spec.InstanceStorePolicy = aws.String(v1beta1.InstanceStorePolicyRAID0)
+			instanceInfo, err := awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
+			Expect(err).To(BeNil())
+			info, ok := lo.Find(instanceInfo, func(i *ec2.InstanceTypeInfo) bool {
+				return aws.StringValue(i.InstanceType) == "m6idn.32xlarge"
+			})
+			Expect(ok).To(BeTrue())
+			it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+			Expect(*it.Capacity.StorageEphemeral()).To(Equal(resource.MustParse("7600G")))
+		})
+		It("should only use the total instance store size for custom AMIs that declare RAID0 support", func() {
+			nodeClass.Spec.AMIFamily = aws.String(v1beta1.AMIFamilyCustom)
+			nodeClass.Spec.InstanceStorePolicy = aws.String(v1beta1.InstanceStorePolicyRAID0)
+			instanceInfo, err := awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
+			Expect(err).To(BeNil())
+			info, ok := lo.Find(instanceInfo, func(i *ec2.InstanceTypeInfo) bool {
+				return aws.StringValue(i.InstanceType) == "m6idn.32xlarge"
+			})
+			Expect(ok).To(BeTrue())
+			it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+			Expect(*it.Capacity.StorageEphemeral()).To(Equal(resource.MustParse("20Gi")))
+
+			nodeClass.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{FeatureFlags: &v1beta1.CustomAMIFamilyFeatureFlags{SupportsInstanceStoreRAID0: aws.Bool(true)}}
+			it = instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+			Expect(*it.Capacity.StorageEphemeral()).To(Equal(resource.MustParse("7600G")))
+		})
+		It("should not use the instance store size for Ubuntu, which doesn't set up the instance store", func() {
+			nodeClass.Spec.AMIFamily = aws.String(v1beta1.AMIFamilyUbuntu)
+			nodeClass.Spec.InstanceStorePolicy = aws.String(v1beta1.InstanceStorePolicyRAID0)
+			instanceInfo, err := awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
+			Expect(err).To(BeNil())
+			info, ok := lo.Find(instanceInfo, func(i *ec2.InstanceTypeInfo) bool {
+				return aws.StringValue(i.InstanceType) == "m6idn.32xlarge"
+			})
+			Expect(ok).To(BeTrue())
+			it := instancetype.NewInstanceType(ctx, info, &corev1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeClass, nil, nil, nil)
+			Expect(*it.Capacity.StorageEphemeral()).To(Equal(resource.MustParse("20Gi")))
+		})
+		It("should use the root volume size when the instance store policy is RAID0 and the instance type has no instance store", func() {
+			nodeClass.Spec.InstanceStorePolicy = aws.String(v1beta1.InstanceStorePolicyRAID0)
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{
+				NodeSelector: map[string]string{v1.LabelInstanceTypeStable: "m5.large"},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(*node.Status.Capacity.StorageEphemeral()).To(Equal(resource.MustParse("20Gi")))
+		})
 		It("should default to EBS defaults when volumeSize is not defined in blockDeviceMappings for custom AMIs", func() {
 			nodeClass.Spec.AMIFamily = aws.String(v1beta1.AMIFamilyCustom)
 			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
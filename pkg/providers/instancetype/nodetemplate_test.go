@@ -113,6 +113,8 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 			v1.LabelArchStable:               "amd64",
 			v1alpha5.LabelCapacityType:       "on-demand",
 			// Well Known to AWS
+			v1alpha1.LabelTopologyZoneID:                       "testzone1a",
+			v1alpha1.LabelTopologyZoneType:                     "availability-zone",
 			v1alpha1.LabelInstanceHypervisor:                   "nitro",
 			v1alpha1.LabelInstanceEncryptionInTransitSupported: "true",
 			v1alpha1.LabelInstanceCategory:                     "g",
@@ -120,6 +122,10 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 			v1alpha1.LabelInstanceFamily:                       "g4dn",
 			v1alpha1.LabelInstanceSize:                         "8xlarge",
 			v1alpha1.LabelInstanceCPU:                          "32",
+			v1alpha1.LabelInstanceCPUManufacturer:              "intel",
+			v1alpha1.LabelInstanceCPUSustainedClockSpeedMhz:    "2500",
+			v1alpha1.LabelInstanceEBSBandwidth:                 "9500",
+			v1alpha1.LabelInstanceENAExpress:                   "false",
 			v1alpha1.LabelInstanceMemory:                       "131072",
 			v1alpha1.LabelInstanceNetworkBandwidth:             "50000",
 			v1alpha1.LabelInstancePods:                         "58",
@@ -131,6 +137,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 			v1alpha1.LabelInstanceAcceleratorName:              "inferentia",
 			v1alpha1.LabelInstanceAcceleratorManufacturer:      "aws",
 			v1alpha1.LabelInstanceAcceleratorCount:             "1",
+			v1alpha1.LabelInstanceNeuronCoreCount:              "4",
 			// Deprecated Labels
 			v1.LabelFailureDomainBetaRegion: fake.DefaultRegion,
 			v1.LabelFailureDomainBetaZone:   "test-zone-1a",
@@ -166,6 +173,8 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 			v1.LabelArchStable:               "amd64",
 			v1alpha5.LabelCapacityType:       "on-demand",
 			// Well Known to AWS
+			v1alpha1.LabelTopologyZoneID:                       "testzone1a",
+			v1alpha1.LabelTopologyZoneType:                     "availability-zone",
 			v1alpha1.LabelInstanceHypervisor:                   "nitro",
 			v1alpha1.LabelInstanceEncryptionInTransitSupported: "true",
 			v1alpha1.LabelInstanceCategory:                     "g",
@@ -173,6 +182,10 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 			v1alpha1.LabelInstanceFamily:                       "g4dn",
 			v1alpha1.LabelInstanceSize:                         "8xlarge",
 			v1alpha1.LabelInstanceCPU:                          "32",
+			v1alpha1.LabelInstanceCPUManufacturer:              "intel",
+			v1alpha1.LabelInstanceCPUSustainedClockSpeedMhz:    "2500",
+			v1alpha1.LabelInstanceEBSBandwidth:                 "9500",
+			v1alpha1.LabelInstanceENAExpress:                   "false",
 			v1alpha1.LabelInstanceMemory:                       "131072",
 			v1alpha1.LabelInstanceNetworkBandwidth:             "50000",
 			v1alpha1.LabelInstancePods:                         "58",
@@ -197,6 +210,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 					v1alpha1.LabelInstanceAcceleratorCount,
 					v1alpha1.LabelInstanceAcceleratorName,
 					v1alpha1.LabelInstanceAcceleratorManufacturer,
+					v1alpha1.LabelInstanceNeuronCoreCount,
 					v1.LabelWindowsBuild,
 				)).UnsortedList(), lo.Keys(v1alpha5.NormalizedLabels)...)))
 
@@ -217,6 +231,8 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 			v1.LabelArchStable:               "amd64",
 			v1alpha5.LabelCapacityType:       "on-demand",
 			// Well Known to AWS
+			v1alpha1.LabelTopologyZoneID:                       "testzone1a",
+			v1alpha1.LabelTopologyZoneType:                     "availability-zone",
 			v1alpha1.LabelInstanceHypervisor:                   "nitro",
 			v1alpha1.LabelInstanceEncryptionInTransitSupported: "true",
 			v1alpha1.LabelInstanceCategory:                     "inf",
@@ -224,12 +240,17 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 			v1alpha1.LabelInstanceFamily:                       "inf1",
 			v1alpha1.LabelInstanceSize:                         "2xlarge",
 			v1alpha1.LabelInstanceCPU:                          "8",
+			v1alpha1.LabelInstanceCPUManufacturer:              "intel",
+			v1alpha1.LabelInstanceCPUSustainedClockSpeedMhz:    "3000",
+			v1alpha1.LabelInstanceEBSBandwidth:                 "4750",
+			v1alpha1.LabelInstanceENAExpress:                   "false",
 			v1alpha1.LabelInstanceMemory:                       "16384",
 			v1alpha1.LabelInstanceNetworkBandwidth:             "5000",
 			v1alpha1.LabelInstancePods:                         "38",
 			v1alpha1.LabelInstanceAcceleratorName:              "inferentia",
 			v1alpha1.LabelInstanceAcceleratorManufacturer:      "aws",
 			v1alpha1.LabelInstanceAcceleratorCount:             "1",
+			v1alpha1.LabelInstanceNeuronCoreCount:              "4",
 			// Deprecated Labels
 			v1.LabelFailureDomainBetaRegion: fake.DefaultRegion,
 			v1.LabelFailureDomainBetaZone:   "test-zone-1a",
@@ -595,7 +616,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 		instanceInfo, err := awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
 		Expect(err).To(BeNil())
 		for _, info := range instanceInfo {
-			it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+			it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 			Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 110))
 		}
 	})
@@ -603,7 +624,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 		instanceInfo, err := awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
 		Expect(err).To(BeNil())
 		for _, info := range instanceInfo {
-			it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+			it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 			Expect(it.Capacity.Pods().Value()).ToNot(BeNumerically("==", 110))
 		}
 	})
@@ -627,7 +648,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 			EnableENILimitedPodDensity: lo.ToPtr(true),
 		}))
 		for _, info := range instanceInfo {
-			it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(windowsNodeTemplate), nil)
+			it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(windowsNodeTemplate), nil, nil, nil)
 			Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 110))
 		}
 	})
@@ -686,7 +707,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 		})
 		Context("System Reserved Resources", func() {
 			It("should use defaults when no kubelet is specified", func() {
-				it := instancetype.NewInstanceType(ctx, info, &v1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+				it := instancetype.NewInstanceType(ctx, info, &v1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 				Expect(it.Overhead.SystemReserved.Cpu().String()).To(Equal("0"))
 				Expect(it.Overhead.SystemReserved.Memory().String()).To(Equal("0"))
 				Expect(it.Overhead.SystemReserved.StorageEphemeral().String()).To(Equal("0"))
@@ -701,7 +722,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 						},
 					},
 				})
-				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 				Expect(it.Overhead.SystemReserved.Cpu().String()).To(Equal("2"))
 				Expect(it.Overhead.SystemReserved.Memory().String()).To(Equal("20Gi"))
 				Expect(it.Overhead.SystemReserved.StorageEphemeral().String()).To(Equal("10Gi"))
@@ -709,7 +730,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 		})
 		Context("Kube Reserved Resources", func() {
 			It("should use defaults when no kubelet is specified", func() {
-				it := instancetype.NewInstanceType(ctx, info, &v1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+				it := instancetype.NewInstanceType(ctx, info, &v1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 				Expect(it.Overhead.KubeReserved.Cpu().String()).To(Equal("80m"))
 				Expect(it.Overhead.KubeReserved.Memory().String()).To(Equal("893Mi"))
 				Expect(it.Overhead.KubeReserved.StorageEphemeral().String()).To(Equal("1Gi"))
@@ -726,7 +747,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 						v1.ResourceMemory:           resource.MustParse("10Gi"),
 						v1.ResourceEphemeralStorage: resource.MustParse("2Gi"),
 					},
-				}), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+				}), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 				Expect(it.Overhead.KubeReserved.Cpu().String()).To(Equal("2"))
 				Expect(it.Overhead.KubeReserved.Memory().String()).To(Equal("10Gi"))
 				Expect(it.Overhead.KubeReserved.StorageEphemeral().String()).To(Equal("2Gi"))
@@ -753,7 +774,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 							},
 						},
 					})
-					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("500Mi"))
 				})
 				It("should override eviction threshold when specified as a percentage value", func() {
@@ -770,7 +791,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 							},
 						},
 					})
-					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 					Expect(it.Overhead.EvictionThreshold.Memory().Value()).To(BeNumerically("~", float64(it.Capacity.Memory().Value())*0.1, 10))
 				})
 				It("should consider the eviction threshold disabled when specified as 100%", func() {
@@ -787,7 +808,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 							},
 						},
 					})
-					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("0"))
 				})
 				It("should used default eviction threshold for memory when evictionHard not specified", func() {
@@ -804,7 +825,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 							},
 						},
 					})
-					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("50Mi"))
 				})
 			})
@@ -823,7 +844,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 							},
 						},
 					})
-					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("500Mi"))
 				})
 				It("should override eviction threshold when specified as a percentage value", func() {
@@ -843,7 +864,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 							},
 						},
 					})
-					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 					Expect(it.Overhead.EvictionThreshold.Memory().Value()).To(BeNumerically("~", float64(it.Capacity.Memory().Value())*0.1, 10))
 				})
 				It("should consider the eviction threshold disabled when specified as 100%", func() {
@@ -860,10 +881,10 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 							},
 						},
 					})
-					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("0"))
 				})
-				It("should ignore eviction threshold when using Bottlerocket AMI", func() {
+				It("should use the eviction soft threshold when using Bottlerocket AMI", func() {
 					nodeTemplate.Spec.AMIFamily = &v1alpha1.AMIFamilyBottlerocket
 					provisioner = test.Provisioner(coretest.ProvisionerOptions{
 						Kubelet: &v1alpha5.KubeletConfiguration{
@@ -881,12 +902,12 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 							},
 						},
 					})
-					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
-					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("1Gi"))
+					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
+					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("10Gi"))
 				})
 			})
 			It("should take the default eviction threshold when none is specified", func() {
-				it := instancetype.NewInstanceType(ctx, info, &v1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+				it := instancetype.NewInstanceType(ctx, info, &v1beta1.KubeletConfiguration{}, fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 				Expect(it.Overhead.EvictionThreshold.Cpu().String()).To(Equal("0"))
 				Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("100Mi"))
 				Expect(it.Overhead.EvictionThreshold.StorageEphemeral().AsApproximateFloat64()).To(BeNumerically("~", resources.Quantity("2Gi").AsApproximateFloat64()))
@@ -908,7 +929,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 						},
 					},
 				})
-				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 				Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("3Gi"))
 			})
 			It("should take the greater of evictionHard and evictionSoft for overhead as a value", func() {
@@ -928,7 +949,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 						},
 					},
 				})
-				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 				Expect(it.Overhead.EvictionThreshold.Memory().Value()).To(BeNumerically("~", float64(it.Capacity.Memory().Value())*0.05, 10))
 			})
 			It("should take the greater of evictionHard and evictionSoft for overhead with mixed percentage/value", func() {
@@ -948,7 +969,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 						},
 					},
 				})
-				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 				Expect(it.Overhead.EvictionThreshold.Memory().Value()).To(BeNumerically("~", float64(it.Capacity.Memory().Value())*0.1, 10))
 			})
 		})
@@ -958,11 +979,11 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 			provisioner = test.Provisioner(coretest.ProvisionerOptions{})
 			for _, info := range instanceInfo {
 				if *info.InstanceType == "t3.large" {
-					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 					Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 35))
 				}
 				if *info.InstanceType == "m6idn.32xlarge" {
-					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+					it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 					Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 345))
 				}
 			}
@@ -972,7 +993,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 			Expect(err).To(BeNil())
 			provisioner = test.Provisioner(coretest.ProvisionerOptions{Kubelet: &v1alpha5.KubeletConfiguration{MaxPods: ptr.Int32(10)}})
 			for _, info := range instanceInfo {
-				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 10))
 			}
 		})
@@ -985,7 +1006,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 			Expect(err).To(BeNil())
 			provisioner = test.Provisioner(coretest.ProvisionerOptions{Kubelet: &v1alpha5.KubeletConfiguration{MaxPods: ptr.Int32(10)}})
 			for _, info := range instanceInfo {
-				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 10))
 			}
 		})
@@ -1000,7 +1021,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 				return *info.InstanceType == "t3.large"
 			})
 			Expect(ok).To(Equal(true))
-			it := instancetype.NewInstanceType(ctx, t3Large, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+			it := instancetype.NewInstanceType(ctx, t3Large, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 			// t3.large
 			// maxInterfaces = 3
 			// maxIPv4PerInterface = 12
@@ -1020,7 +1041,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 				return *info.InstanceType == "t3.large"
 			})
 			Expect(ok).To(Equal(true))
-			it := instancetype.NewInstanceType(ctx, t3Large, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+			it := instancetype.NewInstanceType(ctx, t3Large, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 			// t3.large
 			// maxInterfaces = 3
 			// maxIPv4PerInterface = 12
@@ -1035,7 +1056,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 			Expect(err).To(BeNil())
 			provisioner = test.Provisioner(coretest.ProvisionerOptions{Kubelet: &v1alpha5.KubeletConfiguration{PodsPerCore: ptr.Int32(1)}})
 			for _, info := range instanceInfo {
-				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", ptr.Int64Value(info.VCpuInfo.DefaultVCpus)))
 			}
 		})
@@ -1044,7 +1065,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 			Expect(err).To(BeNil())
 			provisioner = test.Provisioner(coretest.ProvisionerOptions{Kubelet: &v1alpha5.KubeletConfiguration{PodsPerCore: ptr.Int32(4), MaxPods: ptr.Int32(20)}})
 			for _, info := range instanceInfo {
-				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", lo.Min([]int64{20, ptr.Int64Value(info.VCpuInfo.DefaultVCpus) * 4})))
 			}
 		})
@@ -1054,7 +1075,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 			nodeTemplate.Spec.AMIFamily = &v1alpha1.AMIFamilyBottlerocket
 			provisioner = test.Provisioner(coretest.ProvisionerOptions{Kubelet: &v1alpha5.KubeletConfiguration{PodsPerCore: ptr.Int32(1)}})
 			for _, info := range instanceInfo {
-				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 				limitedPods := instancetype.ENILimitedPods(ctx, info)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", limitedPods.Value()))
 			}
@@ -1068,7 +1089,7 @@ var _ = Describe("NodeTemplate/InstanceTypes", func() {
 			Expect(err).To(BeNil())
 			provisioner = test.Provisioner(coretest.ProvisionerOptions{Kubelet: &v1alpha5.KubeletConfiguration{PodsPerCore: ptr.Int32(0)}})
 			for _, info := range instanceInfo {
-				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil)
+				it := instancetype.NewInstanceType(ctx, info, nodepoolutil.NewKubeletConfiguration(provisioner.Spec.KubeletConfiguration), fake.DefaultRegion, nodeclassutil.New(nodeTemplate), nil, nil, nil)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 110))
 			}
 		})
@@ -0,0 +1,196 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancetype
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// OverheadConfigMapName is the name of the ConfigMap in the Karpenter namespace that the learned instance type
+	// overheads are persisted to
+	OverheadConfigMapName = "karpenter-instance-type-overhead"
+	// MinOverheadSamples is the number of nodes that must be observed for an instance type and AMI family before the
+	// learned overhead is used in place of the VM memory overhead percentage
+	MinOverheadSamples = 3
+
+	overheadsKey = "overheads"
+)
+
+// Overhead is the VM memory overhead that has been learned from the registered nodes of an instance type and AMI family
+type Overhead struct {
+	// MemoryMiB is the largest observed difference between the memory reported by EC2 and the memory capacity of the node.
+	// The largest value is used so that the capacity of an instance type is never overestimated.
+	MemoryMiB int64 `json:"memoryMiB"`
+	// Samples is the number of nodes that have been observed
+	Samples int `json:"samples"`
+	// ObservedAt is the creation timestamp of the newest node that has been observed
+	ObservedAt time.Time `json:"observedAt"`
+	// ObservedAtNodes are the names of the observed nodes that were created at ObservedAt. Creation timestamps have a
+	// granularity of a second, so nodes that are launched together commonly share the same creation timestamp.
+	ObservedAtNodes []string `json:"observedAtNodes,omitempty"`
+}
+
+// Includes returns true if the node was already included in the samples
+func (o Overhead) Includes(nodeName string, createdAt time.Time) bool {
+	return createdAt.Before(o.ObservedAt) || (createdAt.Equal(o.ObservedAt) && lo.Contains(o.ObservedAtNodes, nodeName))
+}
+
+// OverheadStore holds the overheads that have been learned per instance type and AMI family
+type OverheadStore struct {
+	mu        sync.RWMutex
+	overheads map[string]Overhead
+
+	// SeqNum is a monotonically increasing change counter used to invalidate instance types that were computed
+	// with a previous set of learned overheads
+	SeqNum uint64
+}
+
+func NewOverheadStore() *OverheadStore {
+	return &OverheadStore{overheads: map[string]Overhead{}}
+}
+
+func overheadKey(instanceType, amiFamily string) string {
+	return fmt.Sprintf("%s/%s", instanceType, amiFamily)
+}
+
+// MemoryOverhead returns the learned VM memory overhead in MiB for the instance type and AMI family. It returns false
+// if the store is nil or there aren't enough samples yet.
+func (s *OverheadStore) MemoryOverhead(instanceType, amiFamily string) (int64, bool) {
+	if s == nil {
+		return 0, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	overhead, ok := s.overheads[overheadKey(instanceType, amiFamily)]
+	if !ok || overhead.Samples < MinOverheadSamples {
+		return 0, false
+	}
+	return overhead.MemoryMiB, true
+}
+
+// Get returns the learned overhead for the instance type and AMI family regardless of the number of samples
+func (s *OverheadStore) Get(instanceType, amiFamily string) (Overhead, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	overhead, ok := s.overheads[overheadKey(instanceType, amiFamily)]
+	return overhead, ok
+}
+
+// Observe records the VM memory overhead of a node that was created at createdAt. Nodes must be observed in order of
+// creation. Nodes that are older than the newest observed node of the same instance type and AMI family are assumed to
+// have been observed already and are ignored, which keeps samples from being double counted across reconciles and
+// restarts. It returns true if the observation was recorded.
+func (s *OverheadStore) Observe(instanceType, amiFamily, nodeName string, memoryMiB int64, createdAt time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := overheadKey(instanceType, amiFamily)
+	overhead := s.overheads[key]
+	if overhead.Includes(nodeName, createdAt) {
+		return false
+	}
+	usedBefore := overhead.Samples >= MinOverheadSamples
+	previous := overhead.MemoryMiB
+	if memoryMiB > overhead.MemoryMiB || overhead.Samples == 0 {
+		overhead.MemoryMiB = memoryMiB
+	}
+	overhead.Samples++
+	if createdAt.After(overhead.ObservedAt) {
+		overhead.ObservedAt = createdAt
+		overhead.ObservedAtNodes = nil
+	}
+	overhead.ObservedAtNodes = append(overhead.ObservedAtNodes, nodeName)
+	s.overheads[key] = overhead
+	// Only bump the sequence number when the overhead that is used for instance types changes
+	if overhead.Samples >= MinOverheadSamples && (!usedBefore || overhead.MemoryMiB != previous) {
+		atomic.AddUint64(&s.SeqNum, 1)
+	}
+	return true
+}
+
+// Reset forgets all learned overheads
+func (s *OverheadStore) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overheads = map[string]Overhead{}
+	atomic.AddUint64(&s.SeqNum, 1)
+}
+
+// Hydrate merges the learned overheads persisted in the ConfigMap into the store. Persisted overheads only replace
+// overheads in the store that were observed earlier.
+func (s *OverheadStore) Hydrate(cm *v1.ConfigMap) error {
+	overheads := map[string]Overhead{}
+	if raw, ok := cm.Data[overheadsKey]; ok {
+		if err := json.Unmarshal([]byte(raw), &overheads); err != nil {
+			return fmt.Errorf("parsing instance type overheads, %w", err)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, overhead := range overheads {
+		if overhead.ObservedAt.After(s.overheads[key].ObservedAt) {
+			s.overheads[key] = overhead
+		}
+	}
+	atomic.AddUint64(&s.SeqNum, 1)
+	return nil
+}
+
+// ConfigMap returns the ConfigMap representation of the learned overheads in the Karpenter namespace
+func (s *OverheadStore) ConfigMap() (*v1.ConfigMap, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	raw, err := json.Marshal(s.overheads)
+	if err != nil {
+		return nil, fmt.Errorf("serializing instance type overheads, %w", err)
+	}
+	return &v1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      OverheadConfigMapName,
+			Namespace: system.Namespace(),
+		},
+		Data: map[string]string{
+			overheadsKey: string(raw),
+		},
+	}, nil
+}
+
+// HydrateOverheadStore loads the persisted overheads from the Karpenter namespace into the store, if they exist
+func HydrateOverheadStore(ctx context.Context, kubernetesInterface kubernetes.Interface, store *OverheadStore) error {
+	cm, err := kubernetesInterface.CoreV1().ConfigMaps(system.Namespace()).Get(ctx, OverheadConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("getting instance type overheads, %w", err)
+	}
+	return store.Hydrate(cm)
+}
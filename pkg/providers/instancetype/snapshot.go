@@ -0,0 +1,227 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancetype
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+)
+
+// SnapshotConfigMapName is the name of the ConfigMap in the Karpenter namespace that the last known instance type
+// catalog and zonal offerings are persisted to, so that instance types can be resolved when the EC2 API is unavailable
+const SnapshotConfigMapName = "karpenter-instance-types"
+
+const (
+	snapshotRegionKey    = "region"
+	snapshotUpdatedAtKey = "updatedAt"
+	// the catalog is compressed since the instance type info of every instance type exceeds the ConfigMap size limit
+	snapshotCatalogKey = "catalog.json.gz"
+)
+
+// Snapshot is the last known instance type catalog and zonal offerings that were retrieved from the EC2 API
+type Snapshot struct {
+	Region        string                  `json:"-"`
+	UpdatedAt     time.Time               `json:"-"`
+	InstanceTypes []*ec2.InstanceTypeInfo `json:"instanceTypes"`
+	// Offerings is keyed by instance type and contains every zone in the region that offers the instance type
+	Offerings map[string][]string `json:"offerings"`
+	Zones     map[string]Zone     `json:"zones"`
+}
+
+// UpdateSnapshot retrieves the instance type catalog and the zonal offerings of every zone in the region from the EC2
+// API, and uses them as the fallback for when the EC2 API is unavailable
+func (p *Provider) UpdateSnapshot(ctx context.Context) (*Snapshot, error) {
+	instanceTypes, err := p.describeInstanceTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	offerings, err := p.describeInstanceTypeOfferings(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	zones, err := p.describeZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{
+		Region:        p.region,
+		UpdatedAt:     time.Now(),
+		InstanceTypes: instanceTypes,
+		Offerings: lo.MapValues(offerings, func(zones sets.Set[string], _ string) []string {
+			return sets.List(zones)
+		}),
+		Zones: zones,
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.snapshot = snapshot
+	return snapshot, nil
+}
+
+// Hydrate uses the instance type catalog from a previously persisted snapshot as the fallback for when the EC2 API is
+// unavailable, unless a fresher snapshot is already in use. A nil snapshot is ignored.
+func (p *Provider) Hydrate(ctx context.Context, snapshot *Snapshot) {
+	if snapshot == nil {
+		return
+	}
+	if snapshot.Region != p.region {
+		logging.FromContext(ctx).With("region", snapshot.Region).Debugf("ignoring instance type snapshot for a different region")
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.snapshot != nil && !snapshot.UpdatedAt.After(p.snapshot.UpdatedAt) {
+		return
+	}
+	p.snapshot = snapshot
+	logging.FromContext(ctx).With("instance-type-count", len(snapshot.InstanceTypes), "updated-at", snapshot.UpdatedAt.Format(time.RFC3339)).Debugf("hydrated instance types from snapshot")
+}
+
+// Reset discards the instance type snapshot
+func (p *Provider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.snapshot = nil
+}
+
+// snapshotInstanceTypes returns the instance types of the snapshot when the EC2 API failed to return them
+func (p *Provider) snapshotInstanceTypes(ctx context.Context, err error) ([]*ec2.InstanceTypeInfo, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.snapshot == nil || len(p.snapshot.InstanceTypes) == 0 {
+		return nil, err
+	}
+	logging.FromContext(ctx).With("updated-at", p.snapshot.UpdatedAt.Format(time.RFC3339)).Errorf("falling back to the instance type snapshot, %s", err)
+	InstanceTypeCatalogUpdateTimestamp.Set(float64(p.snapshot.UpdatedAt.Unix()))
+	return p.snapshot.InstanceTypes, nil
+}
+
+// snapshotInstanceTypeZones returns the offerings of the snapshot in the given zones when the EC2 API failed to return
+// them
+func (p *Provider) snapshotInstanceTypeZones(ctx context.Context, zones sets.Set[string], err error) (map[string]sets.Set[string], error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.snapshot == nil || len(p.snapshot.Offerings) == 0 {
+		return nil, err
+	}
+	logging.FromContext(ctx).With("updated-at", p.snapshot.UpdatedAt.Format(time.RFC3339)).Errorf("falling back to the instance type offering snapshot, %s", err)
+	instanceTypeZones := map[string]sets.Set[string]{}
+	for instanceType, offeringZones := range p.snapshot.Offerings {
+		if available := zones.Intersection(sets.New(offeringZones...)); available.Len() > 0 {
+			instanceTypeZones[instanceType] = available
+		}
+	}
+	return instanceTypeZones, nil
+}
+
+// snapshotZones returns the zones of the snapshot when the EC2 API failed to return them
+func (p *Provider) snapshotZones(ctx context.Context, err error) (map[string]Zone, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.snapshot == nil || len(p.snapshot.Zones) == 0 {
+		return nil, err
+	}
+	logging.FromContext(ctx).With("updated-at", p.snapshot.UpdatedAt.Format(time.RFC3339)).Errorf("falling back to the zone snapshot, %s", err)
+	return p.snapshot.Zones, nil
+}
+
+// GetSnapshot reads the persisted instance type snapshot from the Karpenter namespace, returning nil if none exists
+func GetSnapshot(ctx context.Context, kubernetesInterface kubernetes.Interface) (*Snapshot, error) {
+	cm, err := kubernetesInterface.CoreV1().ConfigMaps(system.Namespace()).Get(ctx, SnapshotConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting instance type snapshot, %w", err)
+	}
+	return SnapshotFromConfigMap(cm)
+}
+
+// SnapshotFromConfigMap parses an instance type snapshot from its ConfigMap representation
+func SnapshotFromConfigMap(cm *v1.ConfigMap) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	if raw, ok := cm.BinaryData[snapshotCatalogKey]; ok {
+		reader, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("decompressing instance type snapshot, %w", err)
+		}
+		catalog, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("decompressing instance type snapshot, %w", err)
+		}
+		if err := json.Unmarshal(catalog, snapshot); err != nil {
+			return nil, fmt.Errorf("parsing instance type snapshot, %w", err)
+		}
+	}
+	snapshot.Region = cm.Data[snapshotRegionKey]
+	if raw, ok := cm.Data[snapshotUpdatedAtKey]; ok {
+		var err error
+		if snapshot.UpdatedAt, err = time.Parse(time.RFC3339, raw); err != nil {
+			return nil, fmt.Errorf("parsing instance type snapshot timestamp, %w", err)
+		}
+	}
+	return snapshot, nil
+}
+
+// ConfigMap returns the ConfigMap representation of the snapshot in the Karpenter namespace
+func (s *Snapshot) ConfigMap() (*v1.ConfigMap, error) {
+	catalog, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("serializing instance type snapshot, %w", err)
+	}
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(catalog); err != nil {
+		return nil, fmt.Errorf("compressing instance type snapshot, %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("compressing instance type snapshot, %w", err)
+	}
+	return &v1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SnapshotConfigMapName,
+			Namespace: system.Namespace(),
+		},
+		Data: map[string]string{
+			snapshotRegionKey:    s.Region,
+			snapshotUpdatedAtKey: s.UpdatedAt.UTC().Format(time.RFC3339),
+		},
+		BinaryData: map[string][]byte{
+			snapshotCatalogKey: compressed.Bytes(),
+		},
+	}, nil
+}
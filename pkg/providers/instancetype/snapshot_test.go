@@ -0,0 +1,154 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancetype_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/system"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1beta1 "github.com/aws/karpenter-core/pkg/apis/v1beta1"
+	corecloudprovider "github.com/aws/karpenter-core/pkg/cloudprovider"
+	coretest "github.com/aws/karpenter-core/pkg/test"
+	. "github.com/aws/karpenter-core/pkg/test/expectations"
+	"github.com/aws/karpenter/pkg/apis/v1beta1"
+	"github.com/aws/karpenter/pkg/fake"
+	"github.com/aws/karpenter/pkg/providers/instancetype"
+	"github.com/aws/karpenter/pkg/test"
+)
+
+var _ = Describe("InstanceTypes/Snapshot", func() {
+	var controller *instancetype.Controller
+	var nodeClass *v1beta1.EC2NodeClass
+	var nodePool *corev1beta1.NodePool
+
+	BeforeEach(func() {
+		controller = instancetype.NewController(env.Client, awsEnv.InstanceTypesProvider)
+		nodeClass = test.EC2NodeClass()
+		nodePool = coretest.NodePool()
+	})
+	AfterEach(func() {
+		Expect(client.IgnoreNotFound(env.Client.Delete(ctx, &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: instancetype.SnapshotConfigMapName, Namespace: system.Namespace()}}))).To(Succeed())
+	})
+	It("should persist the instance type snapshot", func() {
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+
+		snapshot, err := instancetype.GetSnapshot(ctx, env.KubernetesInterface)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(snapshot).ToNot(BeNil())
+		Expect(snapshot.Region).To(Equal(fake.DefaultRegion))
+		Expect(snapshot.UpdatedAt).ToNot(BeZero())
+		Expect(snapshot.InstanceTypes).ToNot(BeEmpty())
+		Expect(snapshot.Offerings).To(HaveKeyWithValue("m5.large", ContainElement("test-zone-1a")))
+		Expect(snapshot.Zones).To(HaveKey("test-zone-1a"))
+	})
+	It("should fail to persist the snapshot if the EC2 API is unavailable", func() {
+		awsEnv.EC2API.NextError.Set(fmt.Errorf("failed"))
+		ExpectReconcileFailed(ctx, controller, types.NamespacedName{})
+
+		snapshot, err := instancetype.GetSnapshot(ctx, env.KubernetesInterface)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(snapshot).To(BeNil())
+	})
+	It("should return an error if the EC2 API is unavailable and there is no snapshot", func() {
+		awsEnv.EC2API.NextError.Set(fmt.Errorf("failed"))
+		_, err := awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
+		Expect(err).To(HaveOccurred())
+	})
+	It("should fall back to the snapshot if describing instance types fails", func() {
+		_, err := awsEnv.InstanceTypesProvider.UpdateSnapshot(ctx)
+		Expect(err).ToNot(HaveOccurred())
+
+		awsEnv.EC2API.NextError.Set(fmt.Errorf("failed"))
+		instanceTypes, err := awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lo.Map(instanceTypes, func(i *ec2.InstanceTypeInfo, _ int) string { return aws.StringValue(i.InstanceType) })).To(ContainElement("m5.large"))
+	})
+	It("should fall back to the snapshot if describing instance type offerings fails", func() {
+		_, err := awsEnv.InstanceTypesProvider.UpdateSnapshot(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		// cache the instance types and subnets so that the next error is returned when describing offerings
+		_, err = awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = awsEnv.SubnetProvider.List(ctx, nodeClass)
+		Expect(err).ToNot(HaveOccurred())
+
+		awsEnv.EC2API.NextError.Set(fmt.Errorf("failed"))
+		instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+		Expect(err).ToNot(HaveOccurred())
+		m5large, ok := lo.Find(instanceTypes, func(i *corecloudprovider.InstanceType) bool { return i.Name == "m5.large" })
+		Expect(ok).To(BeTrue())
+		Expect(lo.Map(m5large.Offerings, func(o corecloudprovider.Offering, _ int) string { return o.Zone })).To(ContainElement("test-zone-1a"))
+	})
+	It("should hydrate a new provider from the persisted snapshot", func() {
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		snapshot, err := instancetype.GetSnapshot(ctx, env.KubernetesInterface)
+		Expect(err).ToNot(HaveOccurred())
+
+		awsEnv.Reset()
+		awsEnv.InstanceTypesProvider.Hydrate(ctx, snapshot)
+		awsEnv.EC2API.NextError.Set(fmt.Errorf("failed"))
+		instanceTypes, err := awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instanceTypes).To(HaveLen(len(snapshot.InstanceTypes)))
+	})
+	It("should ignore a snapshot from a different region", func() {
+		awsEnv.InstanceTypesProvider.Hydrate(ctx, &instancetype.Snapshot{
+			Region:        "eu-west-1",
+			UpdatedAt:     time.Now(),
+			InstanceTypes: []*ec2.InstanceTypeInfo{{InstanceType: aws.String("m5.large")}},
+		})
+		awsEnv.EC2API.NextError.Set(fmt.Errorf("failed"))
+		_, err := awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
+		Expect(err).To(HaveOccurred())
+	})
+	It("should ignore a snapshot that is older than the snapshot in use", func() {
+		_, err := awsEnv.InstanceTypesProvider.UpdateSnapshot(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		awsEnv.InstanceTypesProvider.Hydrate(ctx, &instancetype.Snapshot{
+			Region:        fake.DefaultRegion,
+			UpdatedAt:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			InstanceTypes: []*ec2.InstanceTypeInfo{{InstanceType: aws.String("c99.large")}},
+		})
+		awsEnv.EC2API.NextError.Set(fmt.Errorf("failed"))
+		instanceTypes, err := awsEnv.InstanceTypesProvider.GetInstanceTypes(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lo.Map(instanceTypes, func(i *ec2.InstanceTypeInfo, _ int) string { return aws.StringValue(i.InstanceType) })).ToNot(ContainElement("c99.large"))
+	})
+	It("should round trip a snapshot through its ConfigMap representation", func() {
+		snapshot := &instancetype.Snapshot{
+			Region:        fake.DefaultRegion,
+			UpdatedAt:     time.Now().UTC().Truncate(time.Second),
+			InstanceTypes: []*ec2.InstanceTypeInfo{{InstanceType: aws.String("m5.large")}},
+			Offerings:     map[string][]string{"m5.large": {"test-zone-1a", "test-zone-1b"}},
+			Zones:         map[string]instancetype.Zone{"test-zone-1a": {Name: "test-zone-1a", ID: "testzone1a", Type: v1beta1.ZoneTypeAvailabilityZone}},
+		}
+		cm, err := snapshot.ConfigMap()
+		Expect(err).ToNot(HaveOccurred())
+		parsed, err := instancetype.SnapshotFromConfigMap(cm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed).To(Equal(snapshot))
+	})
+})
@@ -86,6 +86,7 @@ var _ = BeforeEach(func() {
 	awsEnv.Reset()
 	awsEnv.LaunchTemplateProvider.KubeDNSIP = net.ParseIP("10.0.100.10")
 	awsEnv.LaunchTemplateProvider.ClusterEndpoint = "https://test-cluster"
+	awsEnv.LaunchTemplateProvider.ClusterCIDR = lo.ToPtr("10.100.0.0/16")
 })
 
 var _ = AfterEach(func() {
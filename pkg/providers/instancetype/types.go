@@ -47,42 +47,80 @@ const (
 
 var (
 	instanceTypeScheme = regexp.MustCompile(`(^[a-z]+)(\-[0-9]+tb)?([0-9]+).*\.`)
+	migProfileScheme   = regexp.MustCompile(`^([1-7])g\.([0-9]+)gb$`)
+	// migGPUs are the MIG capable GPUs with the number of compute and memory slices that they are partitioned into
+	migGPUs = map[string]struct{ computeSlices, memorySlices int64 }{
+		"a100": {computeSlices: 7, memorySlices: 8},
+		"h100": {computeSlices: 7, memorySlices: 8},
+		"a30":  {computeSlices: 4, memorySlices: 4},
+	}
 )
 
+// NewInstanceType computes the instance type from its EC2 description. The learned overheads are optional and
+// replace the VM memory overhead percentage for instance types that have been observed often enough. The AMI family of
+// the node class must be registered, which List checks before computing instance types.
 func NewInstanceType(ctx context.Context, info *ec2.InstanceTypeInfo, kc *corev1beta1.KubeletConfiguration,
-	region string, nodeClass *v1beta1.EC2NodeClass, offerings cloudprovider.Offerings) *cloudprovider.InstanceType {
+	region string, nodeClass *v1beta1.EC2NodeClass, offerings cloudprovider.Offerings, zones map[string]Zone, overheads *OverheadStore) *cloudprovider.InstanceType {
+
+	instanceType := newInstanceTypeWithoutOverhead(ctx, info, kc, region, nodeClass, offerings, zones, overheads)
+	instanceType.Overhead = newOverhead(ctx, info, kc, nodeClass, overheads)
+	return instanceType
+}
+
+// newInstanceTypeWithoutOverhead computes the requirements and capacity of the instance type, which only depend on the
+// kubelet configuration through the max pods and pods per core
+func newInstanceTypeWithoutOverhead(ctx context.Context, info *ec2.InstanceTypeInfo, kc *corev1beta1.KubeletConfiguration,
+	region string, nodeClass *v1beta1.EC2NodeClass, offerings cloudprovider.Offerings, zones map[string]Zone, overheads *OverheadStore) *cloudprovider.InstanceType {
 
-	amiFamily := amifamily.GetAMIFamily(nodeClass.Spec.AMIFamily, &amifamily.Options{})
+	amiFamily := lo.Must(amifamily.GetAMIFamily(nodeClass.Spec.AMIFamily, &amifamily.Options{CustomAMIFamily: nodeClass.Spec.CustomAMIFamily}))
+	mem := MemoryCapacity(ctx, info, AMIFamilyName(nodeClass), overheads)
+	storage := ephemeralStorage(info, amiFamily, nodeClass.Spec.BlockDeviceMappings, nodeClass.Spec.InstanceStorePolicy)
 	return &cloudprovider.InstanceType{
 		Name:         aws.StringValue(info.InstanceType),
-		Requirements: computeRequirements(ctx, info, offerings, region, amiFamily, kc, nodeClass),
+		Requirements: computeRequirements(ctx, info, offerings, zones, region, amiFamily, kc, nodeClass),
 		Offerings:    offerings,
-		Capacity:     computeCapacity(ctx, info, amiFamily, nodeClass.Spec.BlockDeviceMappings, kc),
-		Overhead: &cloudprovider.InstanceTypeOverhead{
-			KubeReserved:      kubeReservedResources(cpu(info), pods(ctx, info, amiFamily, kc), ENILimitedPods(ctx, info), amiFamily, kc),
-			SystemReserved:    systemReservedResources(kc),
-			EvictionThreshold: evictionThreshold(memory(ctx, info), ephemeralStorage(amiFamily, nodeClass.Spec.BlockDeviceMappings), amiFamily, kc),
-		},
+		Capacity:     computeCapacity(ctx, info, mem, storage, amiFamily, kc, nodeClass.Spec.GPUSharing),
+	}
+}
+
+// newOverhead computes the resources that are reserved on the instance type, which don't depend on its offerings
+func newOverhead(ctx context.Context, info *ec2.InstanceTypeInfo, kc *corev1beta1.KubeletConfiguration, nodeClass *v1beta1.EC2NodeClass,
+	overheads *OverheadStore) *cloudprovider.InstanceTypeOverhead {
+
+	amiFamily := lo.Must(amifamily.GetAMIFamily(nodeClass.Spec.AMIFamily, &amifamily.Options{CustomAMIFamily: nodeClass.Spec.CustomAMIFamily}))
+	mem := MemoryCapacity(ctx, info, AMIFamilyName(nodeClass), overheads)
+	storage := ephemeralStorage(info, amiFamily, nodeClass.Spec.BlockDeviceMappings, nodeClass.Spec.InstanceStorePolicy)
+	return &cloudprovider.InstanceTypeOverhead{
+		KubeReserved:      kubeReservedResources(cpu(info), pods(ctx, info, amiFamily, kc), ENILimitedPods(ctx, info), amiFamily, kc),
+		SystemReserved:    systemReservedResources(amiFamily, kc),
+		EvictionThreshold: evictionThreshold(mem, storage, amiFamily, kc),
 	}
 }
 
+// AMIFamilyName returns the name of the AMI family of the node class that learned overheads are keyed by
+func AMIFamilyName(nodeClass *v1beta1.EC2NodeClass) string {
+	return lo.FromPtrOr(nodeClass.Spec.AMIFamily, v1beta1.AMIFamilyAL2)
+}
+
 //nolint:gocyclo
-func computeRequirements(ctx context.Context, info *ec2.InstanceTypeInfo, offerings cloudprovider.Offerings, region string,
+func computeRequirements(ctx context.Context, info *ec2.InstanceTypeInfo, offerings cloudprovider.Offerings, zones map[string]Zone, region string,
 	amiFamily amifamily.AMIFamily, kc *corev1beta1.KubeletConfiguration, nodeClass *v1beta1.EC2NodeClass) scheduling.Requirements {
 	requirements := scheduling.NewRequirements(
 		// Well Known Upstream
 		scheduling.NewRequirement(v1.LabelInstanceTypeStable, v1.NodeSelectorOpIn, aws.StringValue(info.InstanceType)),
 		scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, getArchitecture(info)),
 		scheduling.NewRequirement(v1.LabelOSStable, v1.NodeSelectorOpIn, getOS(info, amiFamily)...),
-		scheduling.NewRequirement(v1.LabelTopologyZone, v1.NodeSelectorOpIn, lo.Map(offerings.Available(), func(o cloudprovider.Offering, _ int) string { return o.Zone })...),
 		scheduling.NewRequirement(v1.LabelTopologyRegion, v1.NodeSelectorOpIn, region),
 		scheduling.NewRequirement(v1.LabelWindowsBuild, v1.NodeSelectorOpDoesNotExist),
 		// Well Known to Karpenter
-		scheduling.NewRequirement(corev1beta1.CapacityTypeLabelKey, v1.NodeSelectorOpIn, lo.Map(offerings.Available(), func(o cloudprovider.Offering, _ int) string { return o.CapacityType })...),
 		// Well Known to AWS
 		scheduling.NewRequirement(v1beta1.LabelInstanceCPU, v1.NodeSelectorOpIn, fmt.Sprint(aws.Int64Value(info.VCpuInfo.DefaultVCpus))),
+		scheduling.NewRequirement(v1beta1.LabelInstanceCPUManufacturer, v1.NodeSelectorOpDoesNotExist),
+		scheduling.NewRequirement(v1beta1.LabelInstanceCPUSustainedClockSpeedMhz, v1.NodeSelectorOpDoesNotExist),
 		scheduling.NewRequirement(v1beta1.LabelInstanceMemory, v1.NodeSelectorOpIn, fmt.Sprint(aws.Int64Value(info.MemoryInfo.SizeInMiB))),
+		scheduling.NewRequirement(v1beta1.LabelInstanceEBSBandwidth, v1.NodeSelectorOpDoesNotExist),
 		scheduling.NewRequirement(v1beta1.LabelInstanceNetworkBandwidth, v1.NodeSelectorOpDoesNotExist),
+		scheduling.NewRequirement(v1beta1.LabelInstanceENAExpress, v1.NodeSelectorOpIn, fmt.Sprint(aws.BoolValue(info.NetworkInfo.EnaSrdSupported))),
 		scheduling.NewRequirement(v1beta1.LabelInstanceCategory, v1.NodeSelectorOpDoesNotExist),
 		scheduling.NewRequirement(v1beta1.LabelInstanceFamily, v1.NodeSelectorOpDoesNotExist),
 		scheduling.NewRequirement(v1beta1.LabelInstanceGeneration, v1.NodeSelectorOpDoesNotExist),
@@ -95,12 +133,16 @@ func computeRequirements(ctx context.Context, info *ec2.InstanceTypeInfo, offeri
 		scheduling.NewRequirement(v1beta1.LabelInstanceAcceleratorName, v1.NodeSelectorOpDoesNotExist),
 		scheduling.NewRequirement(v1beta1.LabelInstanceAcceleratorManufacturer, v1.NodeSelectorOpDoesNotExist),
 		scheduling.NewRequirement(v1beta1.LabelInstanceAcceleratorCount, v1.NodeSelectorOpDoesNotExist),
+		scheduling.NewRequirement(v1beta1.LabelInstanceNeuronCoreCount, v1.NodeSelectorOpDoesNotExist),
 		scheduling.NewRequirement(v1beta1.LabelInstanceHypervisor, v1.NodeSelectorOpIn, aws.StringValue(info.Hypervisor)),
 		scheduling.NewRequirement(v1beta1.LabelInstanceEncryptionInTransitSupported, v1.NodeSelectorOpIn, fmt.Sprint(aws.BoolValue(info.NetworkInfo.EncryptionInTransitSupported))),
 	)
 	if nodeClass.IsNodeTemplate {
 		requirements.Add(scheduling.NewRequirement(v1alpha1.LabelInstancePods, v1.NodeSelectorOpIn, fmt.Sprint(pods(ctx, info, amiFamily, kc))))
 	}
+	for key, requirement := range offeringRequirements(offerings, zones) {
+		requirements[key] = requirement
+	}
 	// Instance Type Labels
 	instanceFamilyParts := instanceTypeScheme.FindStringSubmatch(aws.StringValue(info.InstanceType))
 	if len(instanceFamilyParts) == 4 {
@@ -115,6 +157,18 @@ func computeRequirements(ctx context.Context, info *ec2.InstanceTypeInfo, offeri
 	if info.InstanceStorageInfo != nil && aws.StringValue(info.InstanceStorageInfo.NvmeSupport) != ec2.EphemeralNvmeSupportUnsupported {
 		requirements[v1beta1.LabelInstanceLocalNVME].Insert(fmt.Sprint(aws.Int64Value(info.InstanceStorageInfo.TotalSizeInGB)))
 	}
+	// CPU manufacturer
+	if info.ProcessorInfo.Manufacturer != nil {
+		requirements[v1beta1.LabelInstanceCPUManufacturer].Insert(cpuManufacturer(info))
+	}
+	// CPU clock speed
+	if info.ProcessorInfo.SustainedClockSpeedInGhz != nil {
+		requirements[v1beta1.LabelInstanceCPUSustainedClockSpeedMhz].Insert(fmt.Sprint(int64(math.Round(aws.Float64Value(info.ProcessorInfo.SustainedClockSpeedInGhz) * 1000))))
+	}
+	// EBS bandwidth
+	if info.EbsInfo != nil && info.EbsInfo.EbsOptimizedInfo != nil && info.EbsInfo.EbsOptimizedInfo.MaximumBandwidthInMbps != nil {
+		requirements[v1beta1.LabelInstanceEBSBandwidth].Insert(fmt.Sprint(aws.Int64Value(info.EbsInfo.EbsOptimizedInfo.MaximumBandwidthInMbps)))
+	}
 	// Network bandwidth
 	if bandwidth, ok := InstanceTypeBandwidthMegabits[aws.StringValue(info.InstanceType)]; ok {
 		requirements[v1beta1.LabelInstanceNetworkBandwidth].Insert(fmt.Sprint(bandwidth))
@@ -134,6 +188,10 @@ func computeRequirements(ctx context.Context, info *ec2.InstanceTypeInfo, offeri
 		requirements.Get(v1beta1.LabelInstanceAcceleratorManufacturer).Insert(lowerKabobCase(aws.StringValue(accelerator.Manufacturer)))
 		requirements.Get(v1beta1.LabelInstanceAcceleratorCount).Insert(fmt.Sprint(aws.Int64Value(accelerator.Count)))
 	}
+	// Neuron Cores
+	if cores, ok := InstanceTypeNeuronCores[aws.StringValue(info.InstanceType)]; ok {
+		requirements.Get(v1beta1.LabelInstanceNeuronCoreCount).Insert(fmt.Sprint(cores))
+	}
 	// Windows Build Version Labels
 	if family, ok := amiFamily.(*amifamily.Windows); ok {
 		requirements.Get(v1.LabelWindowsBuild).Insert(family.Build)
@@ -149,7 +207,27 @@ func computeRequirements(ctx context.Context, info *ec2.InstanceTypeInfo, offeri
 	return requirements
 }
 
+// offeringRequirements computes the zone and capacity type requirements from the available offerings
+func offeringRequirements(offerings cloudprovider.Offerings, zones map[string]Zone) scheduling.Requirements {
+	requirements := scheduling.NewRequirements(
+		scheduling.NewRequirement(v1.LabelTopologyZone, v1.NodeSelectorOpIn, lo.Map(offerings.Available(), func(o cloudprovider.Offering, _ int) string { return o.Zone })...),
+		scheduling.NewRequirement(corev1beta1.CapacityTypeLabelKey, v1.NodeSelectorOpIn, lo.Map(offerings.Available(), func(o cloudprovider.Offering, _ int) string { return o.CapacityType })...),
+		scheduling.NewRequirement(v1beta1.LabelTopologyZoneID, v1.NodeSelectorOpDoesNotExist),
+		scheduling.NewRequirement(v1beta1.LabelTopologyZoneType, v1.NodeSelectorOpDoesNotExist),
+	)
+	for _, offering := range offerings.Available() {
+		if zone, ok := zones[offering.Zone]; ok {
+			requirements.Get(v1beta1.LabelTopologyZoneID).Insert(zone.ID)
+			requirements.Get(v1beta1.LabelTopologyZoneType).Insert(zone.Type)
+		}
+	}
+	return requirements
+}
+
 func getOS(info *ec2.InstanceTypeInfo, amiFamily amifamily.AMIFamily) []string {
+	if custom, ok := amiFamily.(*amifamily.Custom); ok {
+		return []string{custom.OperatingSystem()}
+	}
 	if _, ok := amiFamily.(*amifamily.Windows); ok {
 		if getArchitecture(info) == corev1beta1.ArchitectureAmd64 {
 			return []string{string(v1.Windows)}
@@ -159,6 +237,19 @@ func getOS(info *ec2.InstanceTypeInfo, amiFamily amifamily.AMIFamily) []string {
 	return []string{string(v1.Linux)}
 }
 
+// cpuManufacturer returns the lower-cased manufacturer of the processor as reported by DescribeInstanceTypes, e.g. intel,
+// amd, aws or apple
+func cpuManufacturer(info *ec2.InstanceTypeInfo) string {
+	return strings.ToLower(aws.StringValue(info.ProcessorInfo.Manufacturer))
+}
+
+// supportsArchitecture returns whether the AMIs of the AMI family support the architecture of the instance type. Only
+// custom AMI families can declare that they don't support an architecture.
+func supportsArchitecture(info *ec2.InstanceTypeInfo, amiFamily amifamily.AMIFamily) bool {
+	custom, ok := amiFamily.(*amifamily.Custom)
+	return !ok || len(custom.Architectures()) == 0 || lo.Contains(custom.Architectures(), getArchitecture(info))
+}
+
 func getArchitecture(info *ec2.InstanceTypeInfo) string {
 	for _, architecture := range info.ProcessorInfo.SupportedArchitectures {
 		if value, ok := v1beta1.AWSToKubeArchitectures[aws.StringValue(architecture)]; ok {
@@ -168,45 +259,62 @@ func getArchitecture(info *ec2.InstanceTypeInfo) string {
 	return fmt.Sprint(aws.StringValueSlice(info.ProcessorInfo.SupportedArchitectures)) // Unrecognized, but used for error printing
 }
 
-func computeCapacity(ctx context.Context, info *ec2.InstanceTypeInfo, amiFamily amifamily.AMIFamily,
-	blockDeviceMappings []*v1beta1.BlockDeviceMapping, kc *corev1beta1.KubeletConfiguration) v1.ResourceList {
+func computeCapacity(ctx context.Context, info *ec2.InstanceTypeInfo, memory *resource.Quantity, storage *resource.Quantity,
+	amiFamily amifamily.AMIFamily, kc *corev1beta1.KubeletConfiguration, gpuSharing *v1beta1.GPUSharing) v1.ResourceList {
 
 	resourceList := v1.ResourceList{
-		v1.ResourceCPU:              *cpu(info),
-		v1.ResourceMemory:           *memory(ctx, info),
-		v1.ResourceEphemeralStorage: *ephemeralStorage(amiFamily, blockDeviceMappings),
-		v1.ResourcePods:             *pods(ctx, info, amiFamily, kc),
-		v1beta1.ResourceAWSPodENI:   *awsPodENI(ctx, aws.StringValue(info.InstanceType)),
-		v1beta1.ResourceNVIDIAGPU:   *nvidiaGPUs(info),
-		v1beta1.ResourceAMDGPU:      *amdGPUs(info),
-		v1beta1.ResourceAWSNeuron:   *awsNeurons(info),
-		v1beta1.ResourceHabanaGaudi: *habanaGaudis(info),
+		v1.ResourceCPU:                *cpu(info),
+		v1.ResourceMemory:             *memory,
+		v1.ResourceEphemeralStorage:   *storage,
+		v1.ResourcePods:               *pods(ctx, info, amiFamily, kc),
+		v1beta1.ResourceAWSPodENI:     *awsPodENI(ctx, aws.StringValue(info.InstanceType)),
+		v1beta1.ResourceAMDGPU:        *amdGPUs(info),
+		v1beta1.ResourceAWSNeuron:     *awsNeurons(info),
+		v1beta1.ResourceAWSNeuronCore: *awsNeuronCores(info),
+		v1beta1.ResourceHabanaGaudi:   *habanaGaudis(info),
 	}
 	if _, ok := amiFamily.(*amifamily.Windows); ok {
 		//ResourcePrivateIPv4Address is the same as ENILimitedPods on Windows node
 		resourceList[v1beta1.ResourcePrivateIPv4Address] = *privateIPv4Address(info)
 	}
-	return resourceList
+	return lo.Assign(resourceList, nvidiaGPUResources(info, gpuSharing))
 }
 
 func cpu(info *ec2.InstanceTypeInfo) *resource.Quantity {
 	return resources.Quantity(fmt.Sprint(*info.VCpuInfo.DefaultVCpus))
 }
 
-func memory(ctx context.Context, info *ec2.InstanceTypeInfo) *resource.Quantity {
+// MemoryCapacity returns the memory capacity that is predicted for the instance type and AMI family, which is the memory
+// of the VM less the VM memory overhead
+func MemoryCapacity(ctx context.Context, info *ec2.InstanceTypeInfo, amiFamilyName string, overheads *OverheadStore) *resource.Quantity {
+	mem := resources.Quantity(fmt.Sprintf("%dMi", VMMemoryMiB(info)))
+	// Account for VM overhead in calculation, preferring the overhead that was learned from registered nodes
+	if overheadMiB, ok := overheads.MemoryOverhead(aws.StringValue(info.InstanceType), amiFamilyName); ok {
+		mem.Sub(resource.MustParse(fmt.Sprintf("%dMi", overheadMiB)))
+		return mem
+	}
+	mem.Sub(resource.MustParse(fmt.Sprintf("%dMi", int64(math.Ceil(float64(mem.Value())*awssettings.FromContext(ctx).VMMemoryOverheadPercent/1024/1024)))))
+	return mem
+}
+
+// VMMemoryMiB returns the memory of the instance type that is usable by the VM, before any VM overhead is subtracted
+func VMMemoryMiB(info *ec2.InstanceTypeInfo) int64 {
 	sizeInMib := *info.MemoryInfo.SizeInMiB
 	// Gravitons have an extra 64 MiB of cma reserved memory that we can't use
 	if len(info.ProcessorInfo.SupportedArchitectures) > 0 && *info.ProcessorInfo.SupportedArchitectures[0] == "arm64" {
 		sizeInMib -= 64
 	}
-	mem := resources.Quantity(fmt.Sprintf("%dMi", sizeInMib))
-	// Account for VM overhead in calculation
-	mem.Sub(resource.MustParse(fmt.Sprintf("%dMi", int64(math.Ceil(float64(mem.Value())*awssettings.FromContext(ctx).VMMemoryOverheadPercent/1024/1024)))))
-	return mem
+	return sizeInMib
 }
 
 // Setting ephemeral-storage to be either the default value or what is defined in blockDeviceMappings
-func ephemeralStorage(amiFamily amifamily.AMIFamily, blockDeviceMappings []*v1beta1.BlockDeviceMapping) *resource.Quantity {
+func ephemeralStorage(info *ec2.InstanceTypeInfo, amiFamily amifamily.AMIFamily, blockDeviceMappings []*v1beta1.BlockDeviceMapping, instanceStorePolicy *string) *resource.Quantity {
+	// If the instance-store disks back the kubelet and container runtime, the ephemeral storage is the total size of the
+	// disks. Only the bootstraps of AMI families that support it set up the disks.
+	if lo.FromPtr(instanceStorePolicy) == v1beta1.InstanceStorePolicyRAID0 && amiFamily.FeatureFlags().SupportsInstanceStoreRAID0 &&
+		info.InstanceStorageInfo != nil && info.InstanceStorageInfo.TotalSizeInGB != nil {
+		return resources.Quantity(fmt.Sprintf("%dG", aws.Int64Value(info.InstanceStorageInfo.TotalSizeInGB)))
+	}
 	if len(blockDeviceMappings) != 0 {
 		// First check if there's a root volume configured in blockDeviceMappings.
 		if blockDeviceMapping, ok := lo.Find(blockDeviceMappings, func(bdm *v1beta1.BlockDeviceMapping) bool {
@@ -214,18 +322,16 @@ func ephemeralStorage(amiFamily amifamily.AMIFamily, blockDeviceMappings []*v1be
 		}); ok && blockDeviceMapping.EBS.VolumeSize != nil {
 			return blockDeviceMapping.EBS.VolumeSize
 		}
-		switch amiFamily.(type) {
-		case *amifamily.Custom:
-			// We can't know if a custom AMI is going to have a volume size.
+		if amiFamily.EphemeralBlockDevice() == nil {
+			// We can't know if a custom AMI is going to have a volume size, unless its ephemeral block device is declared.
 			volumeSize := blockDeviceMappings[len(blockDeviceMappings)-1].EBS.VolumeSize
 			return lo.Ternary(volumeSize != nil, volumeSize, amifamily.DefaultEBS.VolumeSize)
-		default:
-			// If a block device mapping exists in the provider for the root volume, use the volume size specified in the provider. If not, use the default
-			if blockDeviceMapping, ok := lo.Find(blockDeviceMappings, func(bdm *v1beta1.BlockDeviceMapping) bool {
-				return *bdm.DeviceName == *amiFamily.EphemeralBlockDevice()
-			}); ok && blockDeviceMapping.EBS.VolumeSize != nil {
-				return blockDeviceMapping.EBS.VolumeSize
-			}
+		}
+		// If a block device mapping exists in the provider for the root volume, use the volume size specified in the provider. If not, use the default
+		if blockDeviceMapping, ok := lo.Find(blockDeviceMappings, func(bdm *v1beta1.BlockDeviceMapping) bool {
+			return *bdm.DeviceName == *amiFamily.EphemeralBlockDevice()
+		}); ok && blockDeviceMapping.EBS.VolumeSize != nil {
+			return blockDeviceMapping.EBS.VolumeSize
 		}
 	}
 	//Return the ephemeralBlockDevice size if defined in ami
@@ -258,6 +364,55 @@ func nvidiaGPUs(info *ec2.InstanceTypeInfo) *resource.Quantity {
 	return resources.Quantity(fmt.Sprint(count))
 }
 
+// nvidiaGPUResources shapes the NVIDIA GPUs of the instance type into extended resources. Time-slicing advertises each
+// GPU as multiple replicas, while MIG advertises the MIG instances of the profile that fit on each MIG capable GPU.
+// GPUs that can't be partitioned with the MIG profile are advertised as whole GPUs.
+func nvidiaGPUResources(info *ec2.InstanceTypeInfo, gpuSharing *v1beta1.GPUSharing) v1.ResourceList {
+	gpus := nvidiaGPUs(info)
+	if gpuSharing == nil || gpus.IsZero() {
+		return v1.ResourceList{v1beta1.ResourceNVIDIAGPU: *gpus}
+	}
+	switch gpuSharing.Strategy {
+	case v1beta1.GPUSharingStrategyTimeSlicing:
+		return v1.ResourceList{v1beta1.ResourceNVIDIAGPU: *resources.Quantity(fmt.Sprint(gpus.Value() * int64(lo.FromPtr(gpuSharing.Replicas))))}
+	case v1beta1.GPUSharingStrategyMIG:
+		profile := lo.FromPtr(gpuSharing.MIGProfile)
+		if instances := migInstances(info, profile); instances > 0 {
+			return v1.ResourceList{
+				v1beta1.ResourceNVIDIAGPU:                                  resource.MustParse("0"),
+				v1.ResourceName(v1beta1.ResourceNVIDIAMIGPrefix + profile): *resources.Quantity(fmt.Sprint(gpus.Value() * instances)),
+			}
+		}
+	}
+	return v1.ResourceList{v1beta1.ResourceNVIDIAGPU: *gpus}
+}
+
+// migInstances returns the number of MIG instances of the profile that each GPU of the instance type is partitioned
+// into, which is limited by both the compute and the memory slices of the GPU. Zero is returned if the GPU isn't MIG
+// capable or if the profile doesn't match the memory slices of the GPU.
+func migInstances(info *ec2.InstanceTypeInfo, profile string) int64 {
+	if info.GpuInfo == nil || len(info.GpuInfo.Gpus) != 1 {
+		return 0
+	}
+	gpu := info.GpuInfo.Gpus[0]
+	slices, ok := migGPUs[lowerKabobCase(aws.StringValue(gpu.Name))]
+	if !ok {
+		return 0
+	}
+	parts := migProfileScheme.FindStringSubmatch(profile)
+	if len(parts) != 3 {
+		return 0
+	}
+	computeSlices := lo.Must(strconv.ParseInt(parts[1], 10, 64))
+	memoryGB := lo.Must(strconv.ParseInt(parts[2], 10, 64))
+	gpuMemoryGB := aws.Int64Value(gpu.MemoryInfo.SizeInMiB) / 1024
+	memorySliceGB := gpuMemoryGB / slices.memorySlices
+	if memorySliceGB == 0 || memoryGB == 0 || computeSlices > slices.computeSlices || memoryGB > gpuMemoryGB || memoryGB%memorySliceGB != 0 {
+		return 0
+	}
+	return lo.Min([]int64{slices.computeSlices / computeSlices, gpuMemoryGB / memoryGB})
+}
+
 func amdGPUs(info *ec2.InstanceTypeInfo) *resource.Quantity {
 	count := int64(0)
 	if info.GpuInfo != nil {
@@ -288,6 +443,10 @@ func awsNeurons(info *ec2.InstanceTypeInfo) *resource.Quantity {
 	return resources.Quantity(fmt.Sprint(count))
 }
 
+func awsNeuronCores(info *ec2.InstanceTypeInfo) *resource.Quantity {
+	return resources.Quantity(fmt.Sprint(InstanceTypeNeuronCores[aws.StringValue(info.InstanceType)]))
+}
+
 func habanaGaudis(info *ec2.InstanceTypeInfo) *resource.Quantity {
 	count := int64(0)
 	if info.GpuInfo != nil {
@@ -323,43 +482,19 @@ func privateIPv4Address(info *ec2.InstanceTypeInfo) *resource.Quantity {
 	return resources.Quantity(fmt.Sprint(capacity))
 }
 
-func systemReservedResources(kc *corev1beta1.KubeletConfiguration) v1.ResourceList {
+func systemReservedResources(amiFamily amifamily.AMIFamily, kc *corev1beta1.KubeletConfiguration) v1.ResourceList {
+	resources := amiFamily.SystemReserved()
 	if kc != nil && kc.SystemReserved != nil {
-		return kc.SystemReserved
+		return lo.Assign(resources, kc.SystemReserved)
 	}
-	return v1.ResourceList{}
+	return resources
 }
 
 func kubeReservedResources(cpus, pods, eniLimitedPods *resource.Quantity, amiFamily amifamily.AMIFamily, kc *corev1beta1.KubeletConfiguration) v1.ResourceList {
 	if amiFamily.FeatureFlags().UsesENILimitedMemoryOverhead {
 		pods = eniLimitedPods
 	}
-	resources := v1.ResourceList{
-		v1.ResourceMemory:           resource.MustParse(fmt.Sprintf("%dMi", (11*pods.Value())+255)),
-		v1.ResourceEphemeralStorage: resource.MustParse("1Gi"), // default kube-reserved ephemeral-storage
-	}
-	// kube-reserved Computed from
-	// https://github.com/bottlerocket-os/bottlerocket/pull/1388/files#diff-bba9e4e3e46203be2b12f22e0d654ebd270f0b478dd34f40c31d7aa695620f2fR611
-	for _, cpuRange := range []struct {
-		start      int64
-		end        int64
-		percentage float64
-	}{
-		{start: 0, end: 1000, percentage: 0.06},
-		{start: 1000, end: 2000, percentage: 0.01},
-		{start: 2000, end: 4000, percentage: 0.005},
-		{start: 4000, end: 1 << 31, percentage: 0.0025},
-	} {
-		if cpu := cpus.MilliValue(); cpu >= cpuRange.start {
-			r := float64(cpuRange.end - cpuRange.start)
-			if cpu < cpuRange.end {
-				r = float64(cpu - cpuRange.start)
-			}
-			cpuOverhead := resources.Cpu()
-			cpuOverhead.Add(*resource.NewMilliQuantity(int64(r*cpuRange.percentage), resource.DecimalSI))
-			resources[v1.ResourceCPU] = *cpuOverhead
-		}
-	}
+	resources := amiFamily.KubeReserved(cpus, pods)
 	if kc != nil && kc.KubeReserved != nil {
 		return lo.Assign(resources, kc.KubeReserved)
 	}
@@ -367,10 +502,7 @@ func kubeReservedResources(cpus, pods, eniLimitedPods *resource.Quantity, amiFam
 }
 
 func evictionThreshold(memory *resource.Quantity, storage *resource.Quantity, amiFamily amifamily.AMIFamily, kc *corev1beta1.KubeletConfiguration) v1.ResourceList {
-	overhead := v1.ResourceList{
-		v1.ResourceMemory:           resource.MustParse("100Mi"),
-		v1.ResourceEphemeralStorage: resource.MustParse(fmt.Sprint(math.Ceil(float64(storage.Value()) / 100 * 10))),
-	}
+	overhead := amiFamily.EvictionThreshold(storage)
 	if kc == nil {
 		return overhead
 	}
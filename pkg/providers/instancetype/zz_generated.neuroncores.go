@@ -0,0 +1,34 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancetype
+
+// GENERATED FILE. DO NOT EDIT DIRECTLY.
+// Update hack/code/neuroncores_gen.go and re-generate to edit
+
+var (
+	InstanceTypeNeuronCores = map[string]int64{
+		"inf1.24xlarge":  64,
+		"inf1.2xlarge":   4,
+		"inf1.6xlarge":   16,
+		"inf1.xlarge":    4,
+		"inf2.24xlarge":  12,
+		"inf2.48xlarge":  24,
+		"inf2.8xlarge":   2,
+		"inf2.xlarge":    2,
+		"trn1.2xlarge":   2,
+		"trn1.32xlarge":  32,
+		"trn1n.32xlarge": 32,
+	}
+)
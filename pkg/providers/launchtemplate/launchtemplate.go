@@ -30,6 +30,7 @@ import (
 	"github.com/mitchellh/hashstructure/v2"
 	"github.com/patrickmn/go-cache"
 	"github.com/samber/lo"
+	"golang.org/x/sync/singleflight"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"knative.dev/pkg/logging"
@@ -61,22 +62,26 @@ type LaunchTemplate struct {
 }
 
 type Provider struct {
-	sync.Mutex
+	// The read lock is held while launch templates are ensured so that they can't be deleted from EC2 concurrently
+	sync.RWMutex
 	ec2api                  ec2iface.EC2API
 	amiFamily               *amifamily.Resolver
 	securityGroupProvider   *securitygroup.Provider
 	subnetProvider          *subnet.Provider
 	instanceProfileProvider *instanceprofile.Provider
 	cache                   *cache.Cache
+	group                   singleflight.Group
 	caBundle                *string
 	cm                      *pretty.ChangeMonitor
+	hydrated                chan struct{}
 	KubeDNSIP               net.IP
 	ClusterEndpoint         string
+	ClusterCIDR             *string
 }
 
 func NewProvider(ctx context.Context, cache *cache.Cache, ec2api ec2iface.EC2API, amiFamily *amifamily.Resolver,
 	securityGroupProvider *securitygroup.Provider, subnetProvider *subnet.Provider, instanceProfileProvider *instanceprofile.Provider,
-	caBundle *string, startAsync <-chan struct{}, kubeDNSIP net.IP, clusterEndpoint string) *Provider {
+	caBundle *string, startAsync <-chan struct{}, kubeDNSIP net.IP, clusterEndpoint string, clusterCIDR *string) *Provider {
 	l := &Provider{
 		ec2api:                  ec2api,
 		amiFamily:               amiFamily,
@@ -86,8 +91,10 @@ func NewProvider(ctx context.Context, cache *cache.Cache, ec2api ec2iface.EC2API
 		cache:                   cache,
 		caBundle:                caBundle,
 		cm:                      pretty.NewChangeMonitor(),
+		hydrated:                make(chan struct{}),
 		KubeDNSIP:               kubeDNSIP,
 		ClusterEndpoint:         clusterEndpoint,
+		ClusterCIDR:             clusterCIDR,
 	}
 	l.cache.OnEvicted(l.cachedEvictedFunc(ctx))
 	go func() {
@@ -98,15 +105,21 @@ func NewProvider(ctx context.Context, cache *cache.Cache, ec2api ec2iface.EC2API
 			return
 		}
 		l.hydrateCache(ctx)
+		close(l.hydrated)
 	}()
 	return l
 }
 
+// Hydrated returns a channel that's closed once the launch template cache has been hydrated from EC2
+func (p *Provider) Hydrated() <-chan struct{} {
+	return p.hydrated
+}
+
 func (p *Provider) EnsureAll(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta1.NodeClaim,
 	instanceTypes []*cloudprovider.InstanceType, additionalLabels map[string]string, tags map[string]string) ([]*LaunchTemplate, error) {
 
-	p.Lock()
-	defer p.Unlock()
+	p.RLock()
+	defer p.RUnlock()
 	// If Launch Template is directly specified then just use it
 	if nodeClass.Spec.LaunchTemplateName != nil {
 		return []*LaunchTemplate{{Name: ptr.StringValue(nodeClass.Spec.LaunchTemplateName), InstanceTypes: instanceTypes}}, nil
@@ -151,6 +164,12 @@ func launchTemplateName(options *amifamily.LaunchTemplate) string {
 	return fmt.Sprintf(launchTemplateNameFormat, fmt.Sprint(hash))
 }
 
+// AMIOptions returns the options that the AMIs of the EC2NodeClass are resolved with when launching instances, leaving
+// out the labels and tags of the NodeClaim since they don't change which AMIs are resolved
+func (p *Provider) AMIOptions(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (*amifamily.Options, error) {
+	return p.createAMIOptions(ctx, nodeClass, map[string]string{}, map[string]string{})
+}
+
 func (p *Provider) createAMIOptions(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, labels, tags map[string]string) (*amifamily.Options, error) {
 	// Remove any labels passed into userData that are prefixed with "node-restriction.kubernetes.io" since the kubelet can't
 	// register the node with any labels from this domain: https://kubernetes.io/docs/reference/access-authn-authz/admission-controllers/#noderestriction
@@ -174,15 +193,20 @@ func (p *Provider) createAMIOptions(ctx context.Context, nodeClass *v1beta1.EC2N
 	options := &amifamily.Options{
 		ClusterName:             settings.FromContext(ctx).ClusterName,
 		ClusterEndpoint:         p.ClusterEndpoint,
+		ClusterCIDR:             p.ClusterCIDR,
 		AWSENILimitedPodDensity: settings.FromContext(ctx).EnableENILimitedPodDensity,
 		InstanceProfile:         instanceProfile,
 		SecurityGroups: lo.Map(securityGroups, func(s *ec2.SecurityGroup, _ int) v1beta1.SecurityGroup {
 			return v1beta1.SecurityGroup{ID: aws.StringValue(s.GroupId), Name: aws.StringValue(s.GroupName)}
 		}),
-		Tags:      tags,
-		Labels:    labels,
-		CABundle:  p.caBundle,
-		KubeDNSIP: p.KubeDNSIP,
+		Tags:                tags,
+		Labels:              labels,
+		CABundle:            p.caBundle,
+		KubeDNSIP:           p.KubeDNSIP,
+		InstanceStorePolicy: nodeClass.Spec.InstanceStorePolicy,
+		GPUSharing:          nodeClass.Spec.GPUSharing,
+		CustomAMIFamily:     nodeClass.Spec.CustomAMIFamily,
+		Bottlerocket:        nodeClass.Spec.Bottlerocket,
 	}
 	if ok, err := p.subnetProvider.CheckAnyPublicIPAssociations(ctx, nodeClass); err != nil {
 		return nil, err
@@ -197,7 +221,6 @@ func (p *Provider) createAMIOptions(ctx context.Context, nodeClass *v1beta1.EC2N
 }
 
 func (p *Provider) ensureLaunchTemplate(ctx context.Context, options *amifamily.LaunchTemplate) (*ec2.LaunchTemplate, error) {
-	var launchTemplate *ec2.LaunchTemplate
 	name := launchTemplateName(options)
 	ctx = logging.WithLogger(ctx, logging.FromContext(ctx).With("launch-template-name", name))
 	// Read from cache
@@ -205,28 +228,40 @@ func (p *Provider) ensureLaunchTemplate(ctx context.Context, options *amifamily.
 		p.cache.SetDefault(name, launchTemplate)
 		return launchTemplate.(*ec2.LaunchTemplate), nil
 	}
-	// Attempt to find an existing LT.
-	output, err := p.ec2api.DescribeLaunchTemplatesWithContext(ctx, &ec2.DescribeLaunchTemplatesInput{
-		LaunchTemplateNames: []*string{aws.String(name)},
-	})
-	// Create LT if one doesn't exist
-	if awserrors.IsNotFound(err) {
-		launchTemplate, err = p.createLaunchTemplate(ctx, options)
-		if err != nil {
-			return nil, fmt.Errorf("creating launch template, %w", err)
+	// Concurrent callers for the same launch template share a single call to EC2 so that the launch template is only
+	// created once, while callers for different launch templates don't block each other
+	result, err, _ := p.group.Do(name, func() (interface{}, error) {
+		if launchTemplate, ok := p.cache.Get(name); ok {
+			return launchTemplate, nil
 		}
-	} else if err != nil {
-		return nil, fmt.Errorf("describing launch templates, %w", err)
-	} else if len(output.LaunchTemplates) != 1 {
-		return nil, fmt.Errorf("expected to find one launch template, but found %d", len(output.LaunchTemplates))
-	} else {
-		if p.cm.HasChanged("launchtemplate-"+name, name) {
-			logging.FromContext(ctx).Debugf("discovered launch template")
+		var launchTemplate *ec2.LaunchTemplate
+		// Attempt to find an existing LT.
+		output, err := p.ec2api.DescribeLaunchTemplatesWithContext(ctx, &ec2.DescribeLaunchTemplatesInput{
+			LaunchTemplateNames: []*string{aws.String(name)},
+		})
+		// Create LT if one doesn't exist
+		if awserrors.IsNotFound(err) {
+			launchTemplate, err = p.createLaunchTemplate(ctx, options)
+			if err != nil {
+				return nil, fmt.Errorf("creating launch template, %w", err)
+			}
+		} else if err != nil {
+			return nil, fmt.Errorf("describing launch templates, %w", err)
+		} else if len(output.LaunchTemplates) != 1 {
+			return nil, fmt.Errorf("expected to find one launch template, but found %d", len(output.LaunchTemplates))
+		} else {
+			if p.cm.HasChanged("launchtemplate-"+name, name) {
+				logging.FromContext(ctx).Debugf("discovered launch template")
+			}
+			launchTemplate = output.LaunchTemplates[0]
 		}
-		launchTemplate = output.LaunchTemplates[0]
+		p.cache.SetDefault(name, launchTemplate)
+		return launchTemplate, nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	p.cache.SetDefault(name, launchTemplate)
-	return launchTemplate, nil
+	return result.(*ec2.LaunchTemplate), nil
 }
 
 func (p *Provider) createLaunchTemplate(ctx context.Context, options *amifamily.LaunchTemplate) (*ec2.LaunchTemplate, error) {
@@ -0,0 +1,104 @@
+//go:build test_performance
+
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package launchtemplate_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/patrickmn/go-cache"
+	"github.com/samber/lo"
+
+	coresettings "github.com/aws/karpenter-core/pkg/apis/settings"
+	corev1beta1 "github.com/aws/karpenter-core/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-core/pkg/operator/scheme"
+	coretest "github.com/aws/karpenter-core/pkg/test"
+	"github.com/aws/karpenter/pkg/apis"
+	"github.com/aws/karpenter/pkg/apis/settings"
+	awscache "github.com/aws/karpenter/pkg/cache"
+	"github.com/aws/karpenter/pkg/providers/launchtemplate"
+	"github.com/aws/karpenter/pkg/test"
+)
+
+// ec2Latency is the simulated latency of the EC2 API, which callers used to be serialized behind
+const ec2Latency = 10 * time.Millisecond
+
+type slowEC2API struct {
+	ec2iface.EC2API
+}
+
+func (s *slowEC2API) DescribeLaunchTemplatesWithContext(ctx context.Context, input *ec2.DescribeLaunchTemplatesInput, opts ...request.Option) (*ec2.DescribeLaunchTemplatesOutput, error) {
+	time.Sleep(ec2Latency)
+	return s.EC2API.DescribeLaunchTemplatesWithContext(ctx, input, opts...)
+}
+
+func (s *slowEC2API) CreateLaunchTemplateWithContext(ctx context.Context, input *ec2.CreateLaunchTemplateInput, opts ...request.Option) (*ec2.CreateLaunchTemplateOutput, error) {
+	time.Sleep(ec2Latency)
+	return s.EC2API.CreateLaunchTemplateWithContext(ctx, input, opts...)
+}
+
+// BenchmarkEnsureAllDifferentLaunchTemplates ensures different launch templates in parallel, which shouldn't block
+// each other
+func BenchmarkEnsureAllDifferentLaunchTemplates(b *testing.B) {
+	ctx = coresettings.ToContext(context.Background(), coretest.Settings())
+	ctx = settings.ToContext(ctx, test.Settings())
+	env = coretest.NewEnvironment(scheme.Scheme, coretest.WithCRDs(apis.CRDs...))
+	defer func() {
+		if err := env.Stop(); err != nil {
+			b.Fatalf("stopping environment, %v", err)
+		}
+	}()
+	awsEnv = test.NewEnvironment(ctx, env)
+	provider := launchtemplate.NewProvider(
+		ctx,
+		cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval),
+		&slowEC2API{EC2API: awsEnv.EC2API},
+		awsEnv.AMIResolver,
+		awsEnv.SecurityGroupProvider,
+		awsEnv.SubnetProvider,
+		awsEnv.InstanceProfileProvider,
+		lo.ToPtr("ca-bundle"),
+		make(chan struct{}),
+		net.ParseIP("10.0.100.10"),
+		"https://test-cluster",
+		lo.ToPtr("10.100.0.0/16"),
+	)
+	nodeClass := test.EC2NodeClass()
+	instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, &corev1beta1.KubeletConfiguration{}, nodeClass)
+	if err != nil {
+		b.Fatalf("listing instance types, %v", err)
+	}
+	var launchTemplate atomic.Int64
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			// the tags are part of the launch template, so every call ensures a different launch template
+			tags := map[string]string{"launch-template": fmt.Sprint(launchTemplate.Add(1))}
+			if _, err := provider.EnsureAll(ctx, nodeClass, coretest.NodeClaim(), instanceTypes, nil, tags); err != nil {
+				b.Fatalf("ensuring launch templates, %v", err)
+			}
+		}
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -44,6 +45,7 @@ import (
 	"github.com/aws/karpenter/pkg/fake"
 	"github.com/aws/karpenter/pkg/providers/amifamily/bootstrap"
 	"github.com/aws/karpenter/pkg/providers/instancetype"
+	"github.com/aws/karpenter/pkg/providers/launchtemplate"
 	"github.com/aws/karpenter/pkg/test"
 )
 
@@ -183,6 +185,29 @@ var _ = Describe("EC2NodeClass/LaunchTemplates", func() {
 			Expect(awsEnv.EC2API.CreateFleetBehavior.SuccessfulCalls()).To(BeNumerically("==", 2))
 
 		})
+		It("should only create each launch template once when ensured concurrently", func() {
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			nodeClaim := coretest.NodeClaim()
+
+			var wg sync.WaitGroup
+			launchTemplateNames := make([]sets.String, 10)
+			for i := range launchTemplateNames {
+				wg.Add(1)
+				go func(i int) {
+					defer GinkgoRecover()
+					defer wg.Done()
+					launchTemplates, err := awsEnv.LaunchTemplateProvider.EnsureAll(ctx, nodeClass, nodeClaim, instanceTypes, nil, nil)
+					Expect(err).ToNot(HaveOccurred())
+					launchTemplateNames[i] = sets.NewString(lo.Map(launchTemplates, func(lt *launchtemplate.LaunchTemplate, _ int) string { return lt.Name })...)
+				}(i)
+			}
+			wg.Wait()
+			for _, names := range launchTemplateNames {
+				Expect(names.Equal(launchTemplateNames[0])).To(BeTrue())
+			}
+			Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(Equal(launchTemplateNames[0].Len()))
+		})
 	})
 	Context("Labels", func() {
 		It("should apply labels to the node", func() {
@@ -471,6 +496,158 @@ var _ = Describe("EC2NodeClass/LaunchTemplates", func() {
 				Expect(*ltInput.LaunchTemplateData.BlockDeviceMappings[0].Ebs.KmsKeyId).To(Equal("arn:aws:kms:us-west-2:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab"))
 			})
 		})
+		Context("Root Device", func() {
+			BeforeEach(func() {
+				nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Tags: map[string]string{"*": "*"}}}
+				awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{
+					{
+						Name:           aws.String(coretest.RandomName()),
+						ImageId:        aws.String("ami-123"),
+						Architecture:   aws.String("x86_64"),
+						CreationDate:   aws.String("2022-08-15T12:00:00Z"),
+						RootDeviceName: aws.String("/dev/sda1"),
+					},
+				}})
+			})
+			It("should target the default block device mappings at the root device of the AMI", func() {
+				nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">=", 1))
+				awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+					Expect(len(ltInput.LaunchTemplateData.BlockDeviceMappings)).To(Equal(1))
+					Expect(*ltInput.LaunchTemplateData.BlockDeviceMappings[0].DeviceName).To(Equal("/dev/sda1"))
+					Expect(*ltInput.LaunchTemplateData.BlockDeviceMappings[0].Ebs.VolumeSize).To(Equal(int64(20)))
+				})
+			})
+			It("should target the root volume at the root device of the AMI", func() {
+				nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyCustom
+				nodeClass.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{
+					{
+						DeviceName: aws.String("/dev/xvda"),
+						EBS:        &v1beta1.BlockDevice{VolumeSize: lo.ToPtr(resource.MustParse("40Gi"))},
+						RootVolume: true,
+					},
+					{
+						DeviceName: aws.String("/dev/xvdb"),
+						EBS:        &v1beta1.BlockDevice{VolumeSize: lo.ToPtr(resource.MustParse("100Gi"))},
+					},
+				}
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">=", 1))
+				awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+					Expect(len(ltInput.LaunchTemplateData.BlockDeviceMappings)).To(Equal(2))
+					Expect(*ltInput.LaunchTemplateData.BlockDeviceMappings[0].DeviceName).To(Equal("/dev/sda1"))
+					Expect(*ltInput.LaunchTemplateData.BlockDeviceMappings[0].Ebs.VolumeSize).To(Equal(int64(40)))
+					Expect(*ltInput.LaunchTemplateData.BlockDeviceMappings[1].DeviceName).To(Equal("/dev/xvdb"))
+				})
+			})
+			It("should target the default block device mappings at the root device of the AMI that's rolled back to", func() {
+				nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
+				nodeClass.Spec.AMIRollout = &v1beta1.AMIRollout{
+					Steps:        []int32{10, 50},
+					StepDuration: metav1.Duration{Duration: time.Hour},
+				}
+				nodeClass.Status.AMIs = []v1beta1.AMI{{ID: "ami-123", RootDeviceName: "/dev/sda1"}}
+				nodeClass.Status.AMIRollouts = []v1beta1.AMIRolloutStatus{
+					{ID: "ami-123", PreviousID: "ami-456", PreviousRootDeviceName: "/dev/sdb1", StepStartTime: metav1.Now(), Halted: true},
+				}
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">=", 1))
+				awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+					Expect(*ltInput.LaunchTemplateData.ImageId).To(Equal("ami-456"))
+					Expect(len(ltInput.LaunchTemplateData.BlockDeviceMappings)).To(Equal(1))
+					Expect(*ltInput.LaunchTemplateData.BlockDeviceMappings[0].DeviceName).To(Equal("/dev/sdb1"))
+				})
+			})
+			It("should not retarget the block device mappings when one already targets the root device of the AMI", func() {
+				nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyCustom
+				nodeClass.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{
+					{
+						DeviceName: aws.String("/dev/sda1"),
+						EBS:        &v1beta1.BlockDevice{VolumeSize: lo.ToPtr(resource.MustParse("40Gi"))},
+					},
+					{
+						DeviceName: aws.String("/dev/xvdb"),
+						EBS:        &v1beta1.BlockDevice{VolumeSize: lo.ToPtr(resource.MustParse("100Gi"))},
+						RootVolume: true,
+					},
+				}
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">=", 1))
+				awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+					Expect(lo.Map(ltInput.LaunchTemplateData.BlockDeviceMappings, func(bdm *ec2.LaunchTemplateBlockDeviceMappingRequest, _ int) string {
+						return *bdm.DeviceName
+					})).To(Equal([]string{"/dev/sda1", "/dev/xvdb"}))
+				})
+			})
+			It("should not target the root volume at the root device of the AMI when the custom AMI family has a separate ephemeral block device", func() {
+				nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyCustom
+				nodeClass.Spec.CustomAMIFamily = &v1beta1.CustomAMIFamily{EphemeralBlockDevice: aws.String("/dev/xvdb")}
+				nodeClass.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{
+					{
+						DeviceName: aws.String("/dev/xvdb"),
+						EBS:        &v1beta1.BlockDevice{VolumeSize: lo.ToPtr(resource.MustParse("100Gi"))},
+						RootVolume: true,
+					},
+				}
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				node := ExpectScheduled(ctx, env.Client, pod)
+				Expect(*node.Status.Capacity.StorageEphemeral()).To(Equal(resource.MustParse("100Gi")))
+				Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">=", 1))
+				awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+					Expect(lo.Map(ltInput.LaunchTemplateData.BlockDeviceMappings, func(bdm *ec2.LaunchTemplateBlockDeviceMappingRequest, _ int) string {
+						return *bdm.DeviceName
+					})).To(Equal([]string{"/dev/xvdb"}))
+				})
+			})
+			It("should not target the bottlerocket data volume at the root device of the AMI", func() {
+				nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
+				awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{
+					{
+						Name:           aws.String(coretest.RandomName()),
+						ImageId:        aws.String("ami-123"),
+						Architecture:   aws.String("x86_64"),
+						CreationDate:   aws.String("2022-08-15T12:00:00Z"),
+						RootDeviceName: aws.String("/dev/xvda"),
+					},
+				}})
+				nodeClass.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{
+					{
+						DeviceName: aws.String("/dev/xvdc"),
+						EBS:        &v1beta1.BlockDevice{VolumeSize: lo.ToPtr(resource.MustParse("4Gi"))},
+					},
+					{
+						DeviceName: aws.String("/dev/xvdb"),
+						EBS:        &v1beta1.BlockDevice{VolumeSize: lo.ToPtr(resource.MustParse("100Gi"))},
+						RootVolume: true,
+					},
+				}
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">=", 1))
+				awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+					Expect(lo.Map(ltInput.LaunchTemplateData.BlockDeviceMappings, func(bdm *ec2.LaunchTemplateBlockDeviceMappingRequest, _ int) string {
+						return *bdm.DeviceName
+					})).To(Equal([]string{"/dev/xvdc", "/dev/xvdb"}))
+				})
+			})
+		})
 	})
 	Context("Ephemeral Storage", func() {
 		It("should pack pods when a daemonset has an ephemeral-storage request", func() {
@@ -696,7 +873,7 @@ var _ = Describe("EC2NodeClass/LaunchTemplates", func() {
 			}))
 
 			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
-			it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, "", nodeClass, nil)
+			it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, "", nodeClass, nil, nil, nil)
 			overhead := it.Overhead.Total()
 			Expect(overhead.Memory().String()).To(Equal("993Mi"))
 		})
@@ -707,7 +884,7 @@ var _ = Describe("EC2NodeClass/LaunchTemplates", func() {
 			}))
 
 			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
-			it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, "", nodeClass, nil)
+			it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, "", nodeClass, nil, nil, nil)
 			overhead := it.Overhead.Total()
 			Expect(overhead.Memory().String()).To(Equal("993Mi"))
 		})
@@ -746,7 +923,7 @@ var _ = Describe("EC2NodeClass/LaunchTemplates", func() {
 			}))
 
 			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
-			it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, "", nodeClass, nil)
+			it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, "", nodeClass, nil, nil, nil)
 			overhead := it.Overhead.Total()
 			Expect(overhead.Memory().String()).To(Equal("993Mi"))
 		})
@@ -757,12 +934,109 @@ var _ = Describe("EC2NodeClass/LaunchTemplates", func() {
 			}))
 
 			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
-			it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, "", nodeClass, nil)
+			it := instancetype.NewInstanceType(ctx, info, nodePool.Spec.Template.Spec.Kubelet, "", nodeClass, nil, nil, nil)
 			overhead := it.Overhead.Total()
 			Expect(overhead.Memory().String()).To(Equal("1565Mi"))
 		})
 	})
 	Context("User Data", func() {
+		It("should configure the instance store disks as RAID0 when the instance store policy is RAID0", func() {
+			nodeClass.Spec.InstanceStorePolicy = aws.String(v1beta1.InstanceStorePolicyRAID0)
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectLaunchTemplatesCreatedWithUserDataContaining("--local-disks raid0")
+		})
+		It("should not configure the instance store disks when the instance store policy is not set", func() {
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectLaunchTemplatesCreatedWithUserDataNotContaining("--local-disks")
+		})
+		It("should configure Bottlerocket ephemeral storage when the instance store policy is RAID0", func() {
+			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
+			nodeClass.Spec.InstanceStorePolicy = aws.String(v1beta1.InstanceStorePolicyRAID0)
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectLaunchTemplatesCreatedWithUserDataContaining(
+				fmt.Sprintf("[settings.bootstrap-commands.%s]", bootstrap.BottlerocketEphemeralStorageCommand),
+				"['apiclient', 'ephemeral-storage', 'init']",
+			)
+		})
+		Context("GPU Sharing", func() {
+			var gpuPod *v1.Pod
+			BeforeEach(func() {
+				nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
+				gpuPod = coretest.UnschedulablePod(coretest.PodOptions{
+					ResourceRequirements: v1.ResourceRequirements{
+						Requests: v1.ResourceList{v1beta1.ResourceNVIDIAGPU: resource.MustParse("1")},
+						Limits:   v1.ResourceList{v1beta1.ResourceNVIDIAGPU: resource.MustParse("1")},
+					},
+				})
+			})
+			bottlerocketConfigs := func() []*bootstrap.BottlerocketConfig {
+				var configs []*bootstrap.BottlerocketConfig
+				Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">=", 1))
+				awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+					userData, err := base64.StdEncoding.DecodeString(*ltInput.LaunchTemplateData.UserData)
+					Expect(err).To(BeNil())
+					config := &bootstrap.BottlerocketConfig{}
+					Expect(config.UnmarshalTOML(userData)).To(Succeed())
+					configs = append(configs, config)
+				})
+				return configs
+			}
+			It("should configure time-slicing of the NVIDIA device plugin when the GPU sharing strategy is TimeSlicing", func() {
+				nodeClass.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyTimeSlicing, Replicas: aws.Int32(4)}
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, gpuPod)
+				ExpectScheduled(ctx, env.Client, gpuPod)
+				for _, config := range bottlerocketConfigs() {
+					Expect(config.Settings.KubeletDevicePlugins).ToNot(BeNil())
+					Expect(config.Settings.KubeletDevicePlugins.NVIDIA.DeviceSharingStrategy).To(Equal(aws.String("time-slicing")))
+					Expect(config.Settings.KubeletDevicePlugins.NVIDIA.TimeSlicing.Replicas).To(Equal(aws.Int(4)))
+					Expect(config.Settings.KubeletDevicePlugins.NVIDIA.TimeSlicing.RenameByDefault).To(Equal(aws.Bool(false)))
+					Expect(config.Settings.KubeletDevicePlugins.NVIDIA.MIG).To(BeNil())
+				}
+			})
+			It("should keep the NVIDIA device plugin settings of the UserData", func() {
+				nodeClass.Spec.UserData = aws.String("[settings.kubelet-device-plugins.nvidia]\npass-device-specs = true\n")
+				nodeClass.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyTimeSlicing, Replicas: aws.Int32(4)}
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, gpuPod)
+				ExpectScheduled(ctx, env.Client, gpuPod)
+				for _, config := range bottlerocketConfigs() {
+					nvidia := config.SettingsRaw["kubelet-device-plugins"].(map[string]interface{})["nvidia"].(map[string]interface{})
+					Expect(nvidia).To(HaveKeyWithValue("pass-device-specs", true))
+					Expect(nvidia).To(HaveKeyWithValue("device-sharing-strategy", "time-slicing"))
+				}
+			})
+			It("should not configure GPU sharing for instance types without NVIDIA GPUs", func() {
+				nodeClass.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyTimeSlicing, Replicas: aws.Int32(4)}
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod(coretest.PodOptions{
+					NodeSelector: map[string]string{v1.LabelInstanceTypeStable: "m5.large"},
+				})
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				for _, config := range bottlerocketConfigs() {
+					Expect(config.Settings.KubeletDevicePlugins).To(BeNil())
+				}
+			})
+			It("should not configure MIG for GPUs that can't be partitioned with the MIG profile", func() {
+				nodeClass.Spec.GPUSharing = &v1beta1.GPUSharing{Strategy: v1beta1.GPUSharingStrategyMIG, MIGProfile: aws.String("1g.10gb")}
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, gpuPod)
+				ExpectScheduled(ctx, env.Client, gpuPod)
+				for _, config := range bottlerocketConfigs() {
+					Expect(config.Settings.KubeletDevicePlugins).To(BeNil())
+				}
+			})
+		})
 		It("should specify --use-max-pods=false when using ENI-based pod density", func() {
 			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
 			pod := coretest.UnschedulablePod()
@@ -1301,6 +1575,95 @@ var _ = Describe("EC2NodeClass/LaunchTemplates", func() {
 					Expect(*config.Settings.Kubernetes.CPUCFSQuota).To(BeFalse())
 				})
 			})
+			It("should pass eviction soft values when specified", func() {
+				nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
+				nodePool.Spec.Template.Spec.Kubelet = &corev1beta1.KubeletConfiguration{
+					EvictionSoft: map[string]string{
+						"memory.available": "10%",
+					},
+					EvictionSoftGracePeriod: map[string]metav1.Duration{
+						"memory.available": {Duration: time.Minute},
+					},
+					EvictionMaxPodGracePeriod: aws.Int32(300),
+				}
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">=", 1))
+				awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+					userData, err := base64.StdEncoding.DecodeString(*ltInput.LaunchTemplateData.UserData)
+					Expect(err).To(BeNil())
+					config := &bootstrap.BottlerocketConfig{}
+					Expect(config.UnmarshalTOML(userData)).To(Succeed())
+					Expect(config.Settings.Kubernetes.EvictionSoft).To(Equal(map[string]string{"memory.available": "10%"}))
+					Expect(config.Settings.Kubernetes.EvictionSoftGracePeriod).To(Equal(map[string]string{"memory.available": "1m0s"}))
+					Expect(lo.FromPtr(config.Settings.Kubernetes.EvictionMaxPodGracePeriod)).To(Equal(300))
+				})
+			})
+			It("should pass the bootstrap containers of the EC2NodeClass", func() {
+				nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
+				nodeClass.Spec.Bottlerocket = &v1beta1.Bottlerocket{
+					BootstrapContainers: map[string]v1beta1.BottlerocketBootstrapContainer{
+						"setup": {
+							Source:    "public.ecr.aws/example/setup:latest",
+							Essential: aws.Bool(true),
+							UserData:  aws.String("echo hello"),
+						},
+					},
+				}
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">=", 1))
+				awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+					userData, err := base64.StdEncoding.DecodeString(*ltInput.LaunchTemplateData.UserData)
+					Expect(err).To(BeNil())
+					config := &bootstrap.BottlerocketConfig{}
+					Expect(config.UnmarshalTOML(userData)).To(Succeed())
+					Expect(config.Settings.BootstrapContainers).To(HaveKeyWithValue("setup", bootstrap.BottlerocketBootstrapContainer{
+						Source:    aws.String("public.ecr.aws/example/setup:latest"),
+						Mode:      aws.String(v1beta1.BottlerocketContainerModeAlways),
+						Essential: aws.Bool(true),
+						UserData:  aws.String(base64.StdEncoding.EncodeToString([]byte("echo hello"))),
+					}))
+				})
+			})
+			It("should merge the host containers of the EC2NodeClass over the custom user data", func() {
+				nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
+				nodeClass.Spec.UserData = aws.String(`
+[settings.host-containers.admin]
+enabled = false
+superpowered = true
+
+[settings.kernel.sysctl]
+"vm.max_map_count" = "262144"
+`)
+				nodeClass.Spec.Bottlerocket = &v1beta1.Bottlerocket{
+					HostContainers: map[string]v1beta1.BottlerocketHostContainer{
+						"admin": {Enabled: aws.Bool(true)},
+					},
+					KernelLockdown: aws.String("integrity"),
+				}
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">=", 1))
+				awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+					userData, err := base64.StdEncoding.DecodeString(*ltInput.LaunchTemplateData.UserData)
+					Expect(err).To(BeNil())
+					config := &bootstrap.BottlerocketConfig{}
+					Expect(config.UnmarshalTOML(userData)).To(Succeed())
+					Expect(config.Settings.HostContainers).To(HaveKeyWithValue("admin", bootstrap.BottlerocketHostContainer{
+						Enabled:      aws.Bool(true),
+						Superpowered: aws.Bool(true),
+					}))
+					Expect(lo.FromPtr(config.Settings.Kernel.Lockdown)).To(Equal("integrity"))
+					Expect(config.SettingsRaw["kernel"]).To(HaveKeyWithValue("sysctl", HaveKeyWithValue("vm.max_map_count", "262144")))
+				})
+			})
 		})
 		Context("AL2 Custom UserData", func() {
 			It("should merge in custom user data", func() {
@@ -1354,6 +1717,85 @@ var _ = Describe("EC2NodeClass/LaunchTemplates", func() {
 				expectedUserData := fmt.Sprintf(string(content), corev1beta1.NodePoolLabelKey, nodePool.Name)
 				ExpectLaunchTemplatesCreatedWithUserData(expectedUserData)
 			})
+			It("should merge in cloud-config custom user data not in multi-part mime format", func() {
+				ctx = settings.ToContext(ctx, test.Settings(test.SettingOptions{
+					EnableENILimitedPodDensity: lo.ToPtr(false),
+				}))
+
+				content, err := os.ReadFile("testdata/al2_cloud_config_userdata_input.golden")
+				Expect(err).To(BeNil())
+				nodeClass.Spec.UserData = aws.String(string(content))
+				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				content, err = os.ReadFile("testdata/al2_cloud_config_userdata_merged.golden")
+				Expect(err).To(BeNil())
+				expectedUserData := fmt.Sprintf(string(content), corev1beta1.NodePoolLabelKey, nodePool.Name)
+				ExpectLaunchTemplatesCreatedWithUserData(expectedUserData)
+			})
+			It("should merge multiple cloud-config parts of custom user data with merge directives", func() {
+				ctx = settings.ToContext(ctx, test.Settings(test.SettingOptions{
+					EnableENILimitedPodDensity: lo.ToPtr(false),
+				}))
+
+				content, err := os.ReadFile("testdata/al2_multiple_cloud_config_userdata_input.golden")
+				Expect(err).To(BeNil())
+				nodeClass.Spec.UserData = aws.String(string(content))
+				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				content, err = os.ReadFile("testdata/al2_multiple_cloud_config_userdata_merged.golden")
+				Expect(err).To(BeNil())
+				expectedUserData := fmt.Sprintf(string(content), corev1beta1.NodePoolLabelKey, nodePool.Name)
+				ExpectLaunchTemplatesCreatedWithUserData(expectedUserData)
+			})
+			It("should keep the merge directives of cloud-config parts of custom user data", func() {
+				nodeClass.Spec.UserData = aws.String(`MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="BOUNDARY"
+
+--BOUNDARY
+Content-Type: text/cloud-config; charset="us-ascii"
+Merge-Type: list()+dict()+str()
+
+#cloud-config
+packages:
+  - htop
+
+--BOUNDARY
+Content-Type: text/cloud-config; charset="us-ascii"
+
+#cloud-config
+packages:
+  - jq
+
+--BOUNDARY--`)
+				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				ExpectLaunchTemplatesCreatedWithUserDataContaining(
+					"Merge-Type: list()+dict()+str()\n\n#cloud-config\npackages:\n  - htop",
+					"Merge-Type: list(append)+dict(no_replace,recurse_list)+str()\n\n#cloud-config\npackages:\n  - jq",
+				)
+			})
+			DescribeTable("should detect the content type of custom user data not in multi-part mime format",
+				func(userData string, contentType string) {
+					nodeClass.Spec.UserData = aws.String(userData)
+					ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+					pod := coretest.UnschedulablePod()
+					ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+					ExpectScheduled(ctx, env.Client, pod)
+					ExpectLaunchTemplatesCreatedWithUserDataContaining(fmt.Sprintf("Content-Type: %s\n\n%s", contentType, userData))
+				},
+				Entry("shell script", "#!/bin/bash\necho hello", `text/x-shellscript; charset="us-ascii"`),
+				Entry("cloud-config", "#cloud-config\nruncmd:\n  - echo hello", `text/cloud-config; charset="us-ascii"`),
+				Entry("boothook", "#cloud-boothook\necho hello", `text/cloud-boothook; charset="us-ascii"`),
+				Entry("include url", "#include\nhttps://example.com/user-data", `text/x-include-url; charset="us-ascii"`),
+				Entry("include once url", "#include-once\nhttps://example.com/user-data", `text/x-include-once-url; charset="us-ascii"`),
+				Entry("unknown format", "echo hello", `text/x-shellscript; charset="us-ascii"`),
+			)
 			It("should handle empty custom user data", func() {
 				ctx = settings.ToContext(ctx, test.Settings(test.SettingOptions{
 					EnableENILimitedPodDensity: lo.ToPtr(false),
@@ -1369,6 +1811,94 @@ var _ = Describe("EC2NodeClass/LaunchTemplates", func() {
 				ExpectLaunchTemplatesCreatedWithUserData(expectedUserData)
 			})
 		})
+		Context("AL2023", func() {
+			BeforeEach(func() {
+				nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2023
+			})
+			It("should bootstrap with a NodeConfig", func() {
+				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				ExpectLaunchTemplatesCreatedWithUserDataContaining(
+					"Content-Type: application/node.eks.aws",
+					"apiVersion: node.eks.aws/v1alpha1",
+					"kind: NodeConfig",
+					"apiServerEndpoint: https://test-cluster",
+					"certificateAuthority: ca-bundle",
+					"cidr: 10.100.0.0/16",
+					fmt.Sprintf("- --node-labels=%s=%s", corev1beta1.NodePoolLabelKey, nodePool.Name),
+				)
+				ExpectLaunchTemplatesCreatedWithUserDataNotContaining("/etc/eks/bootstrap.sh")
+			})
+			It("should configure the kubelet through the NodeConfig", func() {
+				nodePool.Spec.Template.Spec.Kubelet = &corev1beta1.KubeletConfiguration{
+					MaxPods:      aws.Int32(10),
+					EvictionHard: map[string]string{"memory.available": "5%"},
+					SystemReserved: v1.ResourceList{
+						v1.ResourceCPU: resource.MustParse("500m"),
+					},
+				}
+				nodePool.Spec.Template.Spec.Taints = []v1.Taint{{Key: "foo", Value: "bar", Effect: v1.TaintEffectNoSchedule}}
+				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+				pod := coretest.UnschedulablePod(coretest.PodOptions{Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}}})
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				ExpectLaunchTemplatesCreatedWithUserDataContaining(
+					"maxPods: 10",
+					"memory.available: 5%",
+					"cpu: 500m",
+					"clusterDNS:\n      - 10.0.100.10",
+					"registerWithTaints:\n      - effect: NoSchedule\n        key: foo\n        value: bar",
+				)
+			})
+			It("should configure the instance store disks as RAID0 when the instance store policy is RAID0", func() {
+				nodeClass.Spec.InstanceStorePolicy = aws.String(v1beta1.InstanceStorePolicyRAID0)
+				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				ExpectLaunchTemplatesCreatedWithUserDataContaining("localStorage:\n      strategy: RAID0")
+			})
+			It("should merge in custom user data that's a NodeConfig before the NodeConfig of Karpenter", func() {
+				nodeClass.Spec.UserData = aws.String(`apiVersion: node.eks.aws/v1alpha1
+kind: NodeConfig
+spec:
+  kubelet:
+    config:
+      shutdownGracePeriod: 30s`)
+				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">=", 1))
+				awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+					userData, err := base64.StdEncoding.DecodeString(*ltInput.LaunchTemplateData.UserData)
+					Expect(err).To(BeNil())
+					Expect(strings.Count(string(userData), "Content-Type: application/node.eks.aws")).To(Equal(2))
+					Expect(strings.Index(string(userData), "shutdownGracePeriod: 30s")).To(BeNumerically("<", strings.Index(string(userData), "cidr: 10.100.0.0/16")))
+				})
+			})
+			It("should merge in custom user data that's a script", func() {
+				nodeClass.Spec.UserData = aws.String("#!/bin/bash\necho 'hello world'")
+				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				ExpectLaunchTemplatesCreatedWithUserDataContaining(
+					`Content-Type: text/x-shellscript; charset="us-ascii"`,
+					"echo 'hello world'",
+					"Content-Type: application/node.eks.aws",
+				)
+			})
+			It("should fail to launch when the cluster cidr isn't known", func() {
+				awsEnv.LaunchTemplateProvider.ClusterCIDR = nil
+				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectNotScheduled(ctx, env.Client, pod)
+			})
+		})
 		Context("Custom AMI Selector", func() {
 			It("should use ami selector specified in AWSNodeTemplate", func() {
 				nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Tags: map[string]string{"*": "*"}}}
@@ -16,28 +16,40 @@ package pricing
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	lop "github.com/samber/lo/parallel"
 	"go.uber.org/multierr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	corecontroller "github.com/aws/karpenter-core/pkg/operator/controller"
+
+	"github.com/aws/karpenter/pkg/apis/settings"
 )
 
+// onDemandPricingUpdateInterval is the interval at which on-demand prices are refreshed. On-demand prices change
+// rarely, so they are refreshed far less frequently than spot prices.
+const onDemandPricingUpdateInterval = 12 * time.Hour
+
 type Controller struct {
+	kubeClient      client.Client
 	pricingProvider *Provider
 }
 
-func NewController(pricingProvider *Provider) *Controller {
+func NewController(kubeClient client.Client, pricingProvider *Provider) *Controller {
 	return &Controller{
+		kubeClient:      kubeClient,
 		pricingProvider: pricingProvider,
 	}
 }
 
 func (c *Controller) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
-	return reconcile.Result{RequeueAfter: 12 * time.Hour}, c.updatePricing(ctx)
+	err := c.updatePricing(ctx)
+	// Persist whatever pricing data was successfully retrieved, even if one of the updates failed
+	return reconcile.Result{RequeueAfter: settings.FromContext(ctx).SpotPriceUpdateInterval}, multierr.Append(err, c.persistPricing(ctx))
 }
 
 func (c *Controller) Name() string {
@@ -50,9 +62,11 @@ func (c *Controller) Builder(_ context.Context, m manager.Manager) corecontrolle
 
 func (c *Controller) updatePricing(ctx context.Context) error {
 	work := []func(ctx context.Context) error{
-		c.pricingProvider.UpdateOnDemandPricing,
 		c.pricingProvider.UpdateSpotPricing,
 	}
+	if c.pricingProvider.OnDemandPricingAge() >= onDemandPricingUpdateInterval {
+		work = append(work, c.pricingProvider.UpdateOnDemandPricing)
+	}
 	errs := make([]error, len(work))
 	lop.ForEach(work, func(f func(ctx context.Context) error, i int) {
 		if err := f(ctx); err != nil {
@@ -62,3 +76,19 @@ func (c *Controller) updatePricing(ctx context.Context) error {
 
 	return multierr.Combine(errs...)
 }
+
+// persistPricing stores the latest pricing data so that it can be hydrated by the next Karpenter process to start
+func (c *Controller) persistPricing(ctx context.Context) error {
+	snapshot := c.pricingProvider.Snapshot()
+	if len(snapshot.OnDemandPrices) == 0 && len(snapshot.SpotPrices) == 0 {
+		return nil
+	}
+	cm, err := snapshot.ConfigMap()
+	if err != nil {
+		return err
+	}
+	if err := c.kubeClient.Patch(ctx, cm, client.Apply, client.ForceOwnership, client.FieldOwner(c.Name())); err != nil {
+		return fmt.Errorf("persisting pricing snapshot, %w", err)
+	}
+	return nil
+}
@@ -23,6 +23,9 @@ import (
 
 const (
 	cloudProviderSubsystem = "cloudprovider"
+
+	SpotPriceAboveOnDemand = "above"
+	SpotPriceBelowOnDemand = "below"
 )
 
 var (
@@ -30,12 +33,13 @@ var (
 	CapacityTypeLabel     = "capacity_type"
 	RegionLabel           = "region"
 	TopologyLabel         = "zone"
+	DirectionLabel        = "direction"
 	InstancePriceEstimate = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
 			Subsystem: cloudProviderSubsystem,
 			Name:      "instance_type_price_estimate",
-			Help:      "Estimated hourly price used when making informed decisions on node cost calculation. This is updated once on startup and then every 12 hours.",
+			Help:      "Estimated hourly price used when making informed decisions on node cost calculation. On-demand prices are updated once on startup and then every 12 hours. Spot prices are updated at the spot price update interval.",
 		},
 		[]string{
 			InstanceTypeLabel,
@@ -43,8 +47,32 @@ var (
 			RegionLabel,
 			TopologyLabel,
 		})
+	PriceUpdateTimestamp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: cloudProviderSubsystem,
+			Name:      "pricing_updated_timestamp_seconds",
+			Help:      "Unix timestamp at which the pricing data in use was retrieved, either from the AWS APIs or from the persisted pricing snapshot. The age of the pricing data is the current time minus this value.",
+		},
+		[]string{
+			CapacityTypeLabel,
+			RegionLabel,
+		})
+	SpotPriceOnDemandCrossings = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: cloudProviderSubsystem,
+			Name:      "spot_price_on_demand_crossings_total",
+			Help:      "Number of times the spot price of an offering moved above or below the on-demand price of its instance type. Labeled by instance type, region, zone and direction.",
+		},
+		[]string{
+			InstanceTypeLabel,
+			RegionLabel,
+			TopologyLabel,
+			DirectionLabel,
+		})
 )
 
 func init() {
-	crmetrics.Registry.MustRegister(InstancePriceEstimate)
+	crmetrics.Registry.MustRegister(InstancePriceEstimate, PriceUpdateTimestamp, SpotPriceOnDemandCrossings)
 }
@@ -19,10 +19,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -39,7 +41,13 @@ import (
 	"github.com/aws/karpenter-core/pkg/utils/pretty"
 )
 
-var initialOnDemandPrices = lo.Assign(InitialOnDemandPricesAWS, InitialOnDemandPricesUSGov, InitialOnDemandPricesCN)
+const (
+	// maxIncrementalSpotPricingWindow is the longest window of spot price history that is retrieved incrementally. Spot
+	// pricing that is older than this is refreshed in full.
+	maxIncrementalSpotPricingWindow = 6 * time.Hour
+	// materialSpotPriceChange is the relative change in a spot price that causes offerings to be recomputed
+	materialSpotPriceChange = 0.01
+)
 
 // Provider provides actual pricing data to the AWS cloud provider to allow it to make more informed decisions
 // regarding which instances to launch.  This is initialized at startup with a periodically updated static price list to
@@ -52,11 +60,19 @@ type Provider struct {
 	pricing pricingiface.PricingAPI
 	region  string
 	cm      *pretty.ChangeMonitor
-
-	mu                 sync.RWMutex
-	onDemandPrices     map[string]float64
-	spotPrices         map[string]zonal
-	spotPricingUpdated bool
+	// SeqNum is a monotonically increasing change counter that is incremented whenever prices change materially, so
+	// that consumers can avoid recomputing offerings when prices are unchanged
+	SeqNum uint64
+
+	mu                     sync.RWMutex
+	onDemandPrices         map[string]float64
+	onDemandUpdatedAt      time.Time
+	onDemandPricingUpdated bool
+	spotPrices             map[string]zonal
+	spotUpdatedAt          time.Time
+	spotPricingUpdated     bool
+	// hydrated is set once an attempt has been made to load the last persisted pricing snapshot
+	hydrated bool
 }
 
 // zonalPricing is used to capture the per-zone price
@@ -66,11 +82,14 @@ type Provider struct {
 type zonal struct {
 	defaultPrice float64 // Used until we get the spot pricing data
 	prices       map[string]float64
+	// timestamps is the time of the spot price history record that set the price in each zone
+	timestamps map[string]time.Time
 }
 
 func newZonalPricing(defaultPrice float64) zonal {
 	z := zonal{
-		prices: map[string]float64{},
+		prices:     map[string]float64{},
+		timestamps: map[string]time.Time{},
 	}
 	z.defaultPrice = defaultPrice
 	return z
@@ -125,6 +144,14 @@ func (p *Provider) OnDemandPrice(instanceType string) (float64, bool) {
 	return price, true
 }
 
+// OnDemandPricingAge returns the time since on-demand pricing was last retrieved from the AWS APIs. If on-demand
+// pricing has only been loaded from the static pricing data, this is the age of the static pricing data.
+func (p *Provider) OnDemandPricingAge() time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return time.Since(p.onDemandUpdatedAt)
+}
+
 // SpotPrice returns the last known spot price for a given instance type and zone, returning an error
 // if there is no known spot pricing for that instance type or zone
 func (p *Provider) SpotPrice(instanceType string, zone string) (float64, bool) {
@@ -142,6 +169,20 @@ func (p *Provider) SpotPrice(instanceType string, zone string) (float64, bool) {
 	return 0.0, false
 }
 
+// ObservedSpotPrice returns the spot price for a given instance type and zone only if it was retrieved from the spot
+// price history. Unlike SpotPrice, it doesn't fall back to the on-demand price before spot pricing is first updated, so
+// it can be used for zones that may not offer spot capacity at all, such as local and wavelength zones.
+func (p *Provider) ObservedSpotPrice(instanceType string, zone string) (float64, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if val, ok := p.spotPrices[instanceType]; ok {
+		if price, ok := val.prices[zone]; ok {
+			return price, true
+		}
+	}
+	return 0.0, false
+}
+
 func (p *Provider) UpdateOnDemandPricing(ctx context.Context) error {
 	// standard on-demand instances
 	var wg sync.WaitGroup
@@ -195,6 +236,12 @@ func (p *Provider) UpdateOnDemandPricing(ctx context.Context) error {
 	}
 
 	p.onDemandPrices = lo.Assign(onDemandPrices, onDemandMetalPrices)
+	p.onDemandUpdatedAt = time.Now()
+	p.onDemandPricingUpdated = true
+	PriceUpdateTimestamp.With(prometheus.Labels{
+		CapacityTypeLabel: ec2.UsageClassTypeOnDemand,
+		RegionLabel:       p.region,
+	}).Set(float64(p.onDemandUpdatedAt.Unix()))
 	for instanceType, price := range p.onDemandPrices {
 		InstancePriceEstimate.With(prometheus.Labels{
 			InstanceTypeLabel: instanceType,
@@ -204,6 +251,7 @@ func (p *Provider) UpdateOnDemandPricing(ctx context.Context) error {
 		}).Set(price)
 	}
 	if p.cm.HasChanged("on-demand-prices", p.onDemandPrices) {
+		atomic.AddUint64(&p.SeqNum, 1)
 		logging.FromContext(ctx).With("instance-type-count", len(p.onDemandPrices)).Debugf("updated on-demand pricing")
 	}
 	return nil
@@ -217,6 +265,12 @@ func (p *Provider) fetchOnDemandPricing(ctx context.Context, additionalFilters .
 			Type:  aws.String("TERM_MATCH"),
 			Value: aws.String(p.region),
 		},
+		{
+			// only retrieve regional prices, local and wavelength zone products are priced separately
+			Field: aws.String("locationType"),
+			Type:  aws.String("TERM_MATCH"),
+			Value: aws.String("AWS Region"),
+		},
 		{
 			Field: aws.String("serviceCode"),
 			Type:  aws.String("TERM_MATCH"),
@@ -304,69 +358,161 @@ func (p *Provider) onDemandPage(prices map[string]float64) func(output *pricing.
 	}
 }
 
-// nolint: gocyclo
+// UpdateSpotPricing applies the spot prices that changed since the last successful update. The first update, or an
+// update after a gap longer than maxIncrementalSpotPricingWindow, retrieves the current spot price for every instance
+// type and zone instead.
 func (p *Provider) UpdateSpotPricing(ctx context.Context) error {
-	totalOfferings := 0
+	queryTime := time.Now()
+	p.mu.RLock()
+	incremental := p.spotPricingUpdated && queryTime.Sub(p.spotUpdatedAt) < maxIncrementalSpotPricingWindow
+	startTime := lo.Ternary(incremental, p.spotUpdatedAt, queryTime)
+	p.mu.RUnlock()
+
+	records, err := p.fetchSpotPricing(ctx, startTime)
+	if err != nil {
+		return fmt.Errorf("retrieving spot pricing data, %w", err)
+	}
+	if len(records) == 0 && !incremental {
+		return fmt.Errorf("no spot pricing found")
+	}
+	deltas := p.spotPriceDeltas(records)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	material := false
+	for _, d := range deltas {
+		if _, ok := p.spotPrices[d.instanceType]; !ok {
+			p.spotPrices[d.instanceType] = newZonalPricing(0)
+		}
+		p.spotPrices[d.instanceType].prices[d.zone] = d.price
+		p.spotPrices[d.instanceType].timestamps[d.zone] = d.timestamp
+		InstancePriceEstimate.With(prometheus.Labels{
+			InstanceTypeLabel: d.instanceType,
+			CapacityTypeLabel: ec2.UsageClassTypeSpot,
+			RegionLabel:       p.region,
+			TopologyLabel:     d.zone,
+		}).Set(d.price)
+		material = material || d.isMaterial()
+		p.recordOnDemandCrossing(ctx, d)
+	}
+	// Spot prices were previously defaulted to the on-demand price, so the first update is always a material change
+	if material || !p.spotPricingUpdated {
+		atomic.AddUint64(&p.SeqNum, 1)
+	}
+	p.spotPricingUpdated = true
+	p.spotUpdatedAt = queryTime
+	PriceUpdateTimestamp.With(prometheus.Labels{
+		CapacityTypeLabel: ec2.UsageClassTypeSpot,
+		RegionLabel:       p.region,
+	}).Set(float64(p.spotUpdatedAt.Unix()))
+	if len(deltas) > 0 {
+		logging.FromContext(ctx).With(
+			"incremental", incremental,
+			"offering-count", len(deltas)).Debugf("updated spot pricing with instance types and offerings")
+	}
+	return nil
+}
 
-	prices := map[string]map[string]float64{}
+// fetchSpotPricing returns the latest spot price record for every instance type and zone whose price changed after
+// the start time, keyed by instance type and then zone
+func (p *Provider) fetchSpotPricing(ctx context.Context, startTime time.Time) (map[string]map[string]*ec2.SpotPrice, error) {
+	records := map[string]map[string]*ec2.SpotPrice{}
 	err := p.ec2.DescribeSpotPriceHistoryPagesWithContext(ctx, &ec2.DescribeSpotPriceHistoryInput{
 		ProductDescriptions: []*string{aws.String("Linux/UNIX"), aws.String("Linux/UNIX (Amazon VPC)")},
-		// get the latest spot price for each instance type
-		StartTime: aws.Time(time.Now()),
+		// when the start time is now, this returns the latest spot price for each instance type
+		StartTime: aws.Time(startTime),
 	}, func(output *ec2.DescribeSpotPriceHistoryOutput, b bool) bool {
 		for _, sph := range output.SpotPriceHistory {
-			spotPriceStr := aws.StringValue(sph.SpotPrice)
-			spotPrice, err := strconv.ParseFloat(spotPriceStr, 64)
-			// these errors shouldn't occur, but if pricing API does have an error, we ignore the record
-			if err != nil {
+			// these shouldn't occur, but if pricing API does have an error, we ignore the record
+			if _, err := strconv.ParseFloat(aws.StringValue(sph.SpotPrice), 64); err != nil || sph.Timestamp == nil {
 				logging.FromContext(ctx).Debugf("unable to parse price record %#v", sph)
 				continue
 			}
-			if sph.Timestamp == nil {
-				continue
-			}
 			instanceType := aws.StringValue(sph.InstanceType)
 			az := aws.StringValue(sph.AvailabilityZone)
-			_, ok := prices[instanceType]
-			if !ok {
-				prices[instanceType] = map[string]float64{}
+			if _, ok := records[instanceType]; !ok {
+				records[instanceType] = map[string]*ec2.SpotPrice{}
 			}
-			prices[instanceType][az] = spotPrice
-			InstancePriceEstimate.With(prometheus.Labels{
-				InstanceTypeLabel: instanceType,
-				CapacityTypeLabel: ec2.UsageClassTypeSpot,
-				RegionLabel:       p.region,
-				TopologyLabel:     az,
-			}).Set(spotPrice)
+			// history is returned for both product descriptions and possibly multiple changes within the window
+			if existing, ok := records[instanceType][az]; ok && !sph.Timestamp.After(*existing.Timestamp) {
+				continue
+			}
+			records[instanceType][az] = sph
 		}
 		return true
 	})
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	return records, err
+}
 
-	if err != nil {
-		return fmt.Errorf("retrieving spot pricing data, %w", err)
-	}
-	if len(prices) == 0 {
-		return fmt.Errorf("no spot pricing found")
+// spotPriceDelta is a change to the spot price of a single offering
+type spotPriceDelta struct {
+	instanceType   string
+	zone           string
+	price          float64
+	timestamp      time.Time
+	previousPrice  float64
+	previouslySet  bool
+	onDemandPrice  float64
+	onDemandPriced bool
+}
+
+// isMaterial returns true if the price change is large enough that offerings computed from the previous price should
+// be recomputed
+func (d spotPriceDelta) isMaterial() bool {
+	if !d.previouslySet || d.previousPrice == 0 {
+		return true
 	}
-	for it, zoneData := range prices {
-		if _, ok := p.spotPrices[it]; !ok {
-			p.spotPrices[it] = newZonalPricing(0)
-		}
-		for zone, price := range zoneData {
-			p.spotPrices[it].prices[zone] = price
+	return math.Abs(d.price-d.previousPrice)/d.previousPrice >= materialSpotPriceChange
+}
+
+// spotPriceDeltas computes the changes that records make to the current spot prices. Only records that are newer than
+// the record that set the current price are considered.
+func (p *Provider) spotPriceDeltas(records map[string]map[string]*ec2.SpotPrice) []spotPriceDelta {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var deltas []spotPriceDelta
+	for instanceType, zonalRecords := range records {
+		for zone, sph := range zonalRecords {
+			price := lo.Must(strconv.ParseFloat(aws.StringValue(sph.SpotPrice), 64))
+			d := spotPriceDelta{instanceType: instanceType, zone: zone, price: price, timestamp: aws.TimeValue(sph.Timestamp)}
+			if existing, ok := p.spotPrices[instanceType]; ok {
+				if !d.timestamp.After(existing.timestamps[zone]) {
+					continue
+				}
+				d.previousPrice, d.previouslySet = existing.prices[zone]
+			}
+			d.onDemandPrice, d.onDemandPriced = p.onDemandPrices[instanceType]
+			deltas = append(deltas, d)
 		}
-		totalOfferings += len(zoneData)
 	}
+	return deltas
+}
 
-	p.spotPricingUpdated = true
-	if p.cm.HasChanged("spot-prices", p.spotPrices) {
-		logging.FromContext(ctx).With(
-			"instance-type-count", len(p.onDemandPrices),
-			"offering-count", totalOfferings).Debugf("updated spot pricing with instance types and offerings")
+// recordOnDemandCrossing records when the spot price of an offering moves above or below its on-demand price
+func (p *Provider) recordOnDemandCrossing(ctx context.Context, d spotPriceDelta) {
+	if !d.previouslySet || !d.onDemandPriced {
+		return
 	}
-	return nil
+	var direction string
+	switch {
+	case d.previousPrice <= d.onDemandPrice && d.price > d.onDemandPrice:
+		direction = SpotPriceAboveOnDemand
+	case d.previousPrice > d.onDemandPrice && d.price <= d.onDemandPrice:
+		direction = SpotPriceBelowOnDemand
+	default:
+		return
+	}
+	SpotPriceOnDemandCrossings.With(prometheus.Labels{
+		InstanceTypeLabel: d.instanceType,
+		RegionLabel:       p.region,
+		TopologyLabel:     d.zone,
+		DirectionLabel:    direction,
+	}).Inc()
+	logging.FromContext(ctx).With(
+		"instance-type", d.instanceType,
+		"zone", d.zone,
+		"spot-price", d.price,
+		"on-demand-price", d.onDemandPrice).Infof("spot price moved %s on-demand price", direction)
 }
 
 func (p *Provider) LivenessProbe(_ *http.Request) error {
@@ -377,6 +523,18 @@ func (p *Provider) LivenessProbe(_ *http.Request) error {
 	return nil
 }
 
+// ReadinessProbe reports ready once the last persisted pricing snapshot has been loaded, so that the first provisioning
+// decisions after a restart aren't made with the static pricing data when fresher data is available
+func (p *Provider) ReadinessProbe(_ *http.Request) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if !p.hydrated {
+		return fmt.Errorf("pricing data not yet loaded, on-demand pricing age %s, spot pricing age %s",
+			time.Since(p.onDemandUpdatedAt).Truncate(time.Second), time.Since(p.spotUpdatedAt).Truncate(time.Second))
+	}
+	return nil
+}
+
 func populateInitialSpotPricing(pricing map[string]float64) map[string]zonal {
 	m := map[string]zonal{}
 	for it, price := range pricing {
@@ -385,16 +543,33 @@ func populateInitialSpotPricing(pricing map[string]float64) map[string]zonal {
 	return m
 }
 
+// staticPricing returns the compiled-in on-demand pricing data for a region along with the time it was generated
+func staticPricing(region string) (map[string]float64, time.Time) {
+	for _, partition := range []struct {
+		prices      map[string]map[string]float64
+		generatedAt string
+	}{
+		{prices: InitialOnDemandPricesAWS, generatedAt: InitialOnDemandPricesAWSGeneratedAt},
+		{prices: InitialOnDemandPricesUSGov, generatedAt: InitialOnDemandPricesUSGovGeneratedAt},
+		{prices: InitialOnDemandPricesCN, generatedAt: InitialOnDemandPricesCNGeneratedAt},
+	} {
+		if prices, ok := partition.prices[region]; ok {
+			return prices, lo.Must(time.Parse(time.RFC3339, partition.generatedAt))
+		}
+	}
+	// fall back to the always available us-east-1
+	return InitialOnDemandPricesAWS["us-east-1"], lo.Must(time.Parse(time.RFC3339, InitialOnDemandPricesAWSGeneratedAt))
+}
+
 func (p *Provider) Reset() {
 	// see if we've got region specific pricing data
-	staticPricing, ok := initialOnDemandPrices[p.region]
-	if !ok {
-		// and if not, fall back to the always available us-east-1
-		staticPricing = initialOnDemandPrices["us-east-1"]
-	}
+	prices, generatedAt := staticPricing(p.region)
 
-	p.onDemandPrices = staticPricing
+	p.onDemandPrices = prices
+	p.onDemandUpdatedAt = generatedAt
+	p.onDemandPricingUpdated = false
 	// default our spot pricing to the same as the on-demand pricing until a price update
-	p.spotPrices = populateInitialSpotPricing(staticPricing)
+	p.spotPrices = populateInitialSpotPricing(prices)
+	p.spotUpdatedAt = generatedAt
 	p.spotPricingUpdated = false
 }
@@ -0,0 +1,215 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+)
+
+// SnapshotConfigMapName is the name of the ConfigMap in the Karpenter namespace that the last known pricing data is
+// persisted to, so that restarts don't fall back to the static pricing data
+const SnapshotConfigMapName = "karpenter-pricing"
+
+const (
+	snapshotRegionKey            = "region"
+	snapshotOnDemandPricesKey    = "onDemandPrices"
+	snapshotOnDemandUpdatedAtKey = "onDemandUpdatedAt"
+	snapshotSpotPricesKey        = "spotPrices"
+	snapshotSpotUpdatedAtKey     = "spotUpdatedAt"
+)
+
+// Snapshot is the last known pricing data that was retrieved from the AWS APIs
+type Snapshot struct {
+	Region            string
+	OnDemandPrices    map[string]float64
+	OnDemandUpdatedAt time.Time
+	// SpotPrices is keyed by instance type and then by zone
+	SpotPrices    map[string]map[string]float64
+	SpotUpdatedAt time.Time
+}
+
+// Snapshot returns the pricing data that has been retrieved from the AWS APIs or hydrated from a previous snapshot.
+// Static pricing data is never included.
+func (p *Provider) Snapshot() *Snapshot {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	snapshot := &Snapshot{Region: p.region}
+	if p.onDemandPricingUpdated {
+		snapshot.OnDemandPrices = make(map[string]float64, len(p.onDemandPrices))
+		for instanceType, price := range p.onDemandPrices {
+			snapshot.OnDemandPrices[instanceType] = price
+		}
+		snapshot.OnDemandUpdatedAt = p.onDemandUpdatedAt
+	}
+	if p.spotPricingUpdated {
+		snapshot.SpotPrices = make(map[string]map[string]float64, len(p.spotPrices))
+		for instanceType, zonalPrices := range p.spotPrices {
+			if len(zonalPrices.prices) == 0 {
+				continue
+			}
+			snapshot.SpotPrices[instanceType] = make(map[string]float64, len(zonalPrices.prices))
+			for zone, price := range zonalPrices.prices {
+				snapshot.SpotPrices[instanceType][zone] = price
+			}
+		}
+		snapshot.SpotUpdatedAt = p.spotUpdatedAt
+	}
+	return snapshot
+}
+
+// Hydrate applies the pricing data from a previously persisted snapshot when it is fresher than the pricing data
+// currently in use. A nil snapshot is accepted and only marks the provider as hydrated.
+func (p *Provider) Hydrate(ctx context.Context, snapshot *Snapshot) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer func() { p.hydrated = true }()
+
+	if snapshot == nil {
+		return
+	}
+	if snapshot.Region != p.region {
+		logging.FromContext(ctx).With("region", snapshot.Region).Debugf("ignoring pricing snapshot for a different region")
+		return
+	}
+	if len(snapshot.OnDemandPrices) > 0 && snapshot.OnDemandUpdatedAt.After(p.onDemandUpdatedAt) {
+		p.onDemandPrices = snapshot.OnDemandPrices
+		p.onDemandUpdatedAt = snapshot.OnDemandUpdatedAt
+		p.onDemandPricingUpdated = true
+		atomic.AddUint64(&p.SeqNum, 1)
+		for instanceType, price := range p.onDemandPrices {
+			InstancePriceEstimate.With(prometheus.Labels{
+				InstanceTypeLabel: instanceType,
+				CapacityTypeLabel: ec2.UsageClassTypeOnDemand,
+				RegionLabel:       p.region,
+				TopologyLabel:     "",
+			}).Set(price)
+		}
+		PriceUpdateTimestamp.With(prometheus.Labels{
+			CapacityTypeLabel: ec2.UsageClassTypeOnDemand,
+			RegionLabel:       p.region,
+		}).Set(float64(p.onDemandUpdatedAt.Unix()))
+		logging.FromContext(ctx).With("instance-type-count", len(p.onDemandPrices), "updated-at", p.onDemandUpdatedAt.Format(time.RFC3339)).Debugf("hydrated on-demand pricing from snapshot")
+	}
+	if len(snapshot.SpotPrices) > 0 && snapshot.SpotUpdatedAt.After(p.spotUpdatedAt) {
+		for instanceType, zonalPrices := range snapshot.SpotPrices {
+			if _, ok := p.spotPrices[instanceType]; !ok {
+				p.spotPrices[instanceType] = newZonalPricing(0)
+			}
+			for zone, price := range zonalPrices {
+				p.spotPrices[instanceType].prices[zone] = price
+				InstancePriceEstimate.With(prometheus.Labels{
+					InstanceTypeLabel: instanceType,
+					CapacityTypeLabel: ec2.UsageClassTypeSpot,
+					RegionLabel:       p.region,
+					TopologyLabel:     zone,
+				}).Set(price)
+			}
+		}
+		p.spotUpdatedAt = snapshot.SpotUpdatedAt
+		p.spotPricingUpdated = true
+		atomic.AddUint64(&p.SeqNum, 1)
+		PriceUpdateTimestamp.With(prometheus.Labels{
+			CapacityTypeLabel: ec2.UsageClassTypeSpot,
+			RegionLabel:       p.region,
+		}).Set(float64(p.spotUpdatedAt.Unix()))
+		logging.FromContext(ctx).With("instance-type-count", len(snapshot.SpotPrices), "updated-at", p.spotUpdatedAt.Format(time.RFC3339)).Debugf("hydrated spot pricing from snapshot")
+	}
+}
+
+// GetSnapshot reads the persisted pricing snapshot from the Karpenter namespace, returning nil if none exists
+func GetSnapshot(ctx context.Context, kubernetesInterface kubernetes.Interface) (*Snapshot, error) {
+	cm, err := kubernetesInterface.CoreV1().ConfigMaps(system.Namespace()).Get(ctx, SnapshotConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting pricing snapshot, %w", err)
+	}
+	return SnapshotFromConfigMap(cm)
+}
+
+// SnapshotFromConfigMap parses a pricing snapshot from its ConfigMap representation
+func SnapshotFromConfigMap(cm *v1.ConfigMap) (*Snapshot, error) {
+	snapshot := &Snapshot{Region: cm.Data[snapshotRegionKey]}
+	if raw, ok := cm.Data[snapshotOnDemandPricesKey]; ok {
+		if err := json.Unmarshal([]byte(raw), &snapshot.OnDemandPrices); err != nil {
+			return nil, fmt.Errorf("parsing on-demand pricing snapshot, %w", err)
+		}
+	}
+	if raw, ok := cm.Data[snapshotSpotPricesKey]; ok {
+		if err := json.Unmarshal([]byte(raw), &snapshot.SpotPrices); err != nil {
+			return nil, fmt.Errorf("parsing spot pricing snapshot, %w", err)
+		}
+	}
+	var err error
+	if raw, ok := cm.Data[snapshotOnDemandUpdatedAtKey]; ok {
+		if snapshot.OnDemandUpdatedAt, err = time.Parse(time.RFC3339, raw); err != nil {
+			return nil, fmt.Errorf("parsing on-demand pricing snapshot timestamp, %w", err)
+		}
+	}
+	if raw, ok := cm.Data[snapshotSpotUpdatedAtKey]; ok {
+		if snapshot.SpotUpdatedAt, err = time.Parse(time.RFC3339, raw); err != nil {
+			return nil, fmt.Errorf("parsing spot pricing snapshot timestamp, %w", err)
+		}
+	}
+	return snapshot, nil
+}
+
+// ConfigMap returns the ConfigMap representation of the snapshot in the Karpenter namespace
+func (s *Snapshot) ConfigMap() (*v1.ConfigMap, error) {
+	cm := &v1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SnapshotConfigMapName,
+			Namespace: system.Namespace(),
+		},
+		Data: map[string]string{
+			snapshotRegionKey: s.Region,
+		},
+	}
+	if len(s.OnDemandPrices) > 0 {
+		raw, err := json.Marshal(s.OnDemandPrices)
+		if err != nil {
+			return nil, fmt.Errorf("serializing on-demand pricing snapshot, %w", err)
+		}
+		cm.Data[snapshotOnDemandPricesKey] = string(raw)
+		cm.Data[snapshotOnDemandUpdatedAtKey] = s.OnDemandUpdatedAt.UTC().Format(time.RFC3339)
+	}
+	if len(s.SpotPrices) > 0 {
+		raw, err := json.Marshal(s.SpotPrices)
+		if err != nil {
+			return nil, fmt.Errorf("serializing spot pricing snapshot, %w", err)
+		}
+		cm.Data[snapshotSpotPricesKey] = string(raw)
+		cm.Data[snapshotSpotUpdatedAtKey] = s.SpotUpdatedAt.UTC().Format(time.RFC3339)
+	}
+	return cm, nil
+}
@@ -17,6 +17,7 @@ package pricing_test
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -26,8 +27,13 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	. "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	coresettings "github.com/aws/karpenter-core/pkg/apis/settings"
 	"github.com/aws/karpenter-core/pkg/operator/options"
@@ -61,7 +67,8 @@ var _ = BeforeSuite(func() {
 	ctx = settings.ToContext(ctx, test.Settings())
 	ctx, stop = context.WithCancel(ctx)
 	awsEnv = test.NewEnvironment(ctx, env)
-	controller = pricing.NewController(awsEnv.PricingProvider)
+	controller = pricing.NewController(env.Client, awsEnv.PricingProvider)
+	ExpectApplied(ctx, env.Client, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: system.Namespace()}})
 })
 
 var _ = AfterSuite(func() {
@@ -79,6 +86,7 @@ var _ = BeforeEach(func() {
 
 var _ = AfterEach(func() {
 	ExpectCleanedUp(ctx, env.Client)
+	Expect(client.IgnoreNotFound(env.Client.Delete(ctx, &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: pricing.SnapshotConfigMapName, Namespace: system.Namespace()}}))).To(Succeed())
 })
 
 var _ = Describe("Pricing", func() {
@@ -238,6 +246,28 @@ var _ = Describe("Pricing", func() {
 		_, ok := awsEnv.PricingProvider.SpotPrice("c99.large", "test-zone-1b")
 		Expect(ok).To(BeFalse())
 	})
+	It("should only return observed spot prices from the spot price history", func() {
+		_, ok := awsEnv.PricingProvider.ObservedSpotPrice("c5.large", "test-zone-1a")
+		Expect(ok).To(BeFalse())
+
+		now := time.Now()
+		awsEnv.EC2API.DescribeSpotPriceHistoryOutput.Set(&ec2.DescribeSpotPriceHistoryOutput{
+			SpotPriceHistory: []*ec2.SpotPrice{
+				{
+					AvailabilityZone: aws.String("test-zone-1a"),
+					InstanceType:     aws.String("c5.large"),
+					SpotPrice:        aws.String("1.23"),
+					Timestamp:        &now,
+				},
+			},
+		})
+		Expect(awsEnv.PricingProvider.UpdateSpotPricing(ctx)).To(Succeed())
+		price, ok := awsEnv.PricingProvider.ObservedSpotPrice("c5.large", "test-zone-1a")
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("==", 1.23))
+		_, ok = awsEnv.PricingProvider.ObservedSpotPrice("c5.large", "test-zone-1b")
+		Expect(ok).To(BeFalse())
+	})
 	It("should query for both `Linux/UNIX` and `Linux/UNIX (Amazon VPC)`", func() {
 		// If an account supports EC2 classic, then the non-classic instance types have a product
 		// description of Linux/UNIX (Amazon VPC)
@@ -265,6 +295,220 @@ var _ = Describe("Pricing", func() {
 		Expect(lo.Map(inp.ProductDescriptions, func(x *string, _ int) string { return *x })).
 			To(ContainElements("Linux/UNIX", "Linux/UNIX (Amazon VPC)"))
 	})
+	Context("Incremental Spot Pricing", func() {
+		var firstUpdate time.Time
+		BeforeEach(func() {
+			firstUpdate = time.Now().Add(-time.Minute)
+			awsEnv.EC2API.DescribeSpotPriceHistoryOutput.Set(&ec2.DescribeSpotPriceHistoryOutput{
+				SpotPriceHistory: []*ec2.SpotPrice{
+					{
+						AvailabilityZone: aws.String("test-zone-1a"),
+						InstanceType:     aws.String("c99.large"),
+						SpotPrice:        aws.String("1.00"),
+						Timestamp:        &firstUpdate,
+					},
+				},
+			})
+			awsEnv.PricingAPI.GetProductsOutput.Set(&awspricing.GetProductsOutput{
+				PriceList: []aws.JSONValue{
+					fake.NewOnDemandPrice("c98.large", 1.20),
+					fake.NewOnDemandPrice("c99.large", 1.23),
+				},
+			})
+			ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		})
+		setSpotPriceHistory := func(price string, timestamp time.Time) {
+			awsEnv.EC2API.DescribeSpotPriceHistoryOutput.Set(&ec2.DescribeSpotPriceHistoryOutput{
+				SpotPriceHistory: []*ec2.SpotPrice{
+					{
+						AvailabilityZone: aws.String("test-zone-1a"),
+						InstanceType:     aws.String("c99.large"),
+						SpotPrice:        aws.String(price),
+						Timestamp:        &timestamp,
+					},
+				},
+			})
+		}
+		It("should requeue at the spot price update interval", func() {
+			result := ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+			Expect(result.RequeueAfter).To(Equal(test.Settings().SpotPriceUpdateInterval))
+		})
+		It("should query spot price history from the previous update", func() {
+			secondUpdate := time.Now()
+			ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+			inp := awsEnv.EC2API.DescribeSpotPriceHistoryInput.Clone()
+			Expect(aws.TimeValue(inp.StartTime)).To(BeTemporally("<", secondUpdate))
+			Expect(aws.TimeValue(inp.StartTime)).To(BeTemporally(">", firstUpdate))
+		})
+		It("should apply spot price records that are newer than the current price", func() {
+			setSpotPriceHistory("0.50", time.Now())
+			ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+			price, ok := awsEnv.PricingProvider.SpotPrice("c99.large", "test-zone-1a")
+			Expect(ok).To(BeTrue())
+			Expect(price).To(BeNumerically("==", 0.50))
+			Expect(getPricingEstimateMetricValue("c99.large", ec2.UsageClassTypeSpot, "test-zone-1a")).To(BeNumerically("==", 0.50))
+		})
+		It("should ignore spot price records that are older than the current price", func() {
+			setSpotPriceHistory("0.50", firstUpdate.Add(-time.Hour))
+			ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+			price, ok := awsEnv.PricingProvider.SpotPrice("c99.large", "test-zone-1a")
+			Expect(ok).To(BeTrue())
+			Expect(price).To(BeNumerically("==", 1.00))
+		})
+		It("should succeed when no spot prices changed since the previous update", func() {
+			awsEnv.EC2API.DescribeSpotPriceHistoryOutput.Set(&ec2.DescribeSpotPriceHistoryOutput{})
+			ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+			price, ok := awsEnv.PricingProvider.SpotPrice("c99.large", "test-zone-1a")
+			Expect(ok).To(BeTrue())
+			Expect(price).To(BeNumerically("==", 1.00))
+		})
+		It("should only increment the sequence number when spot prices change materially", func() {
+			seqNum := atomic.LoadUint64(&awsEnv.PricingProvider.SeqNum)
+			setSpotPriceHistory("1.001", time.Now())
+			ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+			Expect(atomic.LoadUint64(&awsEnv.PricingProvider.SeqNum)).To(Equal(seqNum))
+
+			setSpotPriceHistory("1.10", time.Now())
+			ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+			Expect(atomic.LoadUint64(&awsEnv.PricingProvider.SeqNum)).To(BeNumerically(">", seqNum))
+		})
+		It("should not refresh on-demand pricing before the on-demand update interval", func() {
+			awsEnv.PricingAPI.GetProductsOutput.Set(&awspricing.GetProductsOutput{
+				PriceList: []aws.JSONValue{
+					fake.NewOnDemandPrice("c98.large", 2.20),
+					fake.NewOnDemandPrice("c99.large", 2.23),
+				},
+			})
+			ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+			price, ok := awsEnv.PricingProvider.OnDemandPrice("c99.large")
+			Expect(ok).To(BeTrue())
+			Expect(price).To(BeNumerically("==", 1.23))
+		})
+		It("should record when the spot price crosses the on-demand price", func() {
+			setSpotPriceHistory("1.50", time.Now())
+			ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+			metric, ok := FindMetricWithLabelValues("karpenter_cloudprovider_spot_price_on_demand_crossings_total", map[string]string{
+				pricing.InstanceTypeLabel: "c99.large",
+				pricing.RegionLabel:       fake.DefaultRegion,
+				pricing.TopologyLabel:     "test-zone-1a",
+				pricing.DirectionLabel:    pricing.SpotPriceAboveOnDemand,
+			})
+			Expect(ok).To(BeTrue())
+			Expect(metric.GetCounter().GetValue()).To(BeNumerically(">=", 1))
+		})
+	})
+	Context("Snapshot", func() {
+		BeforeEach(func() {
+			now := time.Now()
+			awsEnv.EC2API.DescribeSpotPriceHistoryOutput.Set(&ec2.DescribeSpotPriceHistoryOutput{
+				SpotPriceHistory: []*ec2.SpotPrice{
+					{
+						AvailabilityZone: aws.String("test-zone-1a"),
+						InstanceType:     aws.String("c99.large"),
+						SpotPrice:        aws.String("0.42"),
+						Timestamp:        &now,
+					},
+				},
+			})
+			awsEnv.PricingAPI.GetProductsOutput.Set(&awspricing.GetProductsOutput{
+				PriceList: []aws.JSONValue{
+					fake.NewOnDemandPrice("c98.large", 1.20),
+					fake.NewOnDemandPrice("c99.large", 1.23),
+				},
+			})
+		})
+		It("should persist the pricing snapshot after updating pricing", func() {
+			ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+
+			snapshot, err := pricing.GetSnapshot(ctx, env.KubernetesInterface)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot).ToNot(BeNil())
+			Expect(snapshot.Region).To(Equal(fake.DefaultRegion))
+			Expect(snapshot.OnDemandPrices).To(HaveKeyWithValue("c99.large", 1.23))
+			Expect(snapshot.SpotPrices).To(HaveKeyWithValue("c99.large", HaveKeyWithValue("test-zone-1a", 0.42)))
+			Expect(snapshot.OnDemandUpdatedAt).ToNot(BeZero())
+			Expect(snapshot.SpotUpdatedAt).ToNot(BeZero())
+		})
+		It("should persist the on-demand pricing snapshot if spot pricing fails to update", func() {
+			awsEnv.EC2API.DescribeSpotPriceHistoryOutput.Set(&ec2.DescribeSpotPriceHistoryOutput{})
+			ExpectReconcileFailed(ctx, controller, types.NamespacedName{})
+
+			snapshot, err := pricing.GetSnapshot(ctx, env.KubernetesInterface)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot.OnDemandPrices).To(HaveKeyWithValue("c99.large", 1.23))
+			Expect(snapshot.SpotPrices).To(BeEmpty())
+		})
+		It("should not persist a snapshot if only static pricing data is available", func() {
+			awsEnv.PricingAPI.NextError.Set(fmt.Errorf("failed"))
+			awsEnv.EC2API.DescribeSpotPriceHistoryOutput.Set(&ec2.DescribeSpotPriceHistoryOutput{})
+			ExpectReconcileFailed(ctx, controller, types.NamespacedName{})
+
+			snapshot, err := pricing.GetSnapshot(ctx, env.KubernetesInterface)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot).To(BeNil())
+		})
+		It("should hydrate a new provider from the persisted snapshot", func() {
+			ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+			snapshot, err := pricing.GetSnapshot(ctx, env.KubernetesInterface)
+			Expect(err).ToNot(HaveOccurred())
+
+			provider := pricing.NewProvider(ctx, awsEnv.PricingAPI, awsEnv.EC2API, fake.DefaultRegion)
+			provider.Hydrate(ctx, snapshot)
+
+			price, ok := provider.OnDemandPrice("c99.large")
+			Expect(ok).To(BeTrue())
+			Expect(price).To(BeNumerically("==", 1.23))
+			price, ok = provider.SpotPrice("c99.large", "test-zone-1a")
+			Expect(ok).To(BeTrue())
+			Expect(price).To(BeNumerically("==", 0.42))
+			// spot pricing is no longer defaulted to the on-demand price once hydrated
+			_, ok = provider.SpotPrice("c99.large", "test-zone-1b")
+			Expect(ok).To(BeFalse())
+		})
+		It("should ignore a snapshot that is older than the static pricing data", func() {
+			provider := pricing.NewProvider(ctx, awsEnv.PricingAPI, awsEnv.EC2API, fake.DefaultRegion)
+			staticPrice, ok := provider.OnDemandPrice("c5.large")
+			Expect(ok).To(BeTrue())
+			provider.Hydrate(ctx, &pricing.Snapshot{
+				Region:            fake.DefaultRegion,
+				OnDemandPrices:    map[string]float64{"c5.large": staticPrice * 2},
+				OnDemandUpdatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			})
+			price, ok := provider.OnDemandPrice("c5.large")
+			Expect(ok).To(BeTrue())
+			Expect(price).To(BeNumerically("==", staticPrice))
+		})
+		It("should ignore a snapshot from a different region", func() {
+			provider := pricing.NewProvider(ctx, awsEnv.PricingAPI, awsEnv.EC2API, fake.DefaultRegion)
+			provider.Hydrate(ctx, &pricing.Snapshot{
+				Region:            "eu-west-1",
+				OnDemandPrices:    map[string]float64{"c99.large": 1.23},
+				OnDemandUpdatedAt: time.Now(),
+			})
+			_, ok := provider.OnDemandPrice("c99.large")
+			Expect(ok).To(BeFalse())
+		})
+		It("should round trip a snapshot through its ConfigMap representation", func() {
+			snapshot := &pricing.Snapshot{
+				Region:            fake.DefaultRegion,
+				OnDemandPrices:    map[string]float64{"c99.large": 1.23},
+				OnDemandUpdatedAt: time.Now().UTC().Truncate(time.Second),
+				SpotPrices:        map[string]map[string]float64{"c99.large": {"test-zone-1a": 0.42}},
+				SpotUpdatedAt:     time.Now().UTC().Truncate(time.Second),
+			}
+			cm, err := snapshot.ConfigMap()
+			Expect(err).ToNot(HaveOccurred())
+			parsed, err := pricing.SnapshotFromConfigMap(cm)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed).To(Equal(snapshot))
+		})
+		It("should only report ready once hydrated", func() {
+			provider := pricing.NewProvider(ctx, awsEnv.PricingAPI, awsEnv.EC2API, fake.DefaultRegion)
+			Expect(provider.ReadinessProbe(nil)).ToNot(Succeed())
+			provider.Hydrate(ctx, nil)
+			Expect(provider.ReadinessProbe(nil)).To(Succeed())
+		})
+	})
 })
 
 func getPricingEstimateMetricValue(instanceType string, capacityType string, zone string) float64 {
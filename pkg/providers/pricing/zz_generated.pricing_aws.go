@@ -18,6 +18,9 @@ package pricing
 
 // generated at 2023-09-18T13:06:44Z for us-east-1
 
+// InitialOnDemandPricesAWSGeneratedAt is the time at which the static pricing data below was retrieved
+const InitialOnDemandPricesAWSGeneratedAt = "2023-09-18T13:06:44Z"
+
 var InitialOnDemandPricesAWS = map[string]map[string]float64{
 	"us-east-1": {
 		// a1 family
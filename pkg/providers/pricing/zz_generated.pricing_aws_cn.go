@@ -18,6 +18,9 @@ package pricing
 
 // generated at 2023-09-18T13:06:44Z for cn-north-1
 
+// InitialOnDemandPricesCNGeneratedAt is the time at which the static pricing data below was retrieved
+const InitialOnDemandPricesCNGeneratedAt = "2023-09-18T13:06:44Z"
+
 var InitialOnDemandPricesCN = map[string]map[string]float64{
 	"cn-north-1": {
 		// c3 family
@@ -18,6 +18,9 @@ package pricing
 
 // generated at 2023-09-18T13:06:44Z for us-east-1
 
+// InitialOnDemandPricesUSGovGeneratedAt is the time at which the static pricing data below was retrieved
+const InitialOnDemandPricesUSGovGeneratedAt = "2023-09-18T13:06:44Z"
+
 var InitialOnDemandPricesUSGov = map[string]map[string]float64{
 	"us-gov-west-1": {
 		// c1 family
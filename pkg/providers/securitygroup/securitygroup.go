@@ -17,7 +17,6 @@ package securitygroup
 import (
 	"context"
 	"fmt"
-	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -26,6 +25,7 @@ import (
 	"github.com/mitchellh/hashstructure/v2"
 	"github.com/patrickmn/go-cache"
 	"github.com/samber/lo"
+	"golang.org/x/sync/singleflight"
 	"knative.dev/pkg/logging"
 
 	"github.com/aws/karpenter-core/pkg/utils/functional"
@@ -34,10 +34,11 @@ import (
 )
 
 type Provider struct {
-	sync.Mutex
 	ec2api ec2iface.EC2API
 	cache  *cache.Cache
-	cm     *pretty.ChangeMonitor
+	// group deduplicates concurrent calls to EC2 for the same security group selector
+	group singleflight.Group
+	cm    *pretty.ChangeMonitor
 }
 
 const TTL = 5 * time.Minute
@@ -52,8 +53,6 @@ func NewProvider(ec2api ec2iface.EC2API, cache *cache.Cache) *Provider {
 }
 
 func (p *Provider) List(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) ([]*ec2.SecurityGroup, error) {
-	p.Lock()
-	defer p.Unlock()
 	// Get SecurityGroups
 	// TODO: When removing custom launchTemplates for v1beta1, security groups will be required.
 	// The check will not be necessary
@@ -80,21 +79,33 @@ func (p *Provider) getSecurityGroups(ctx context.Context, filterSets [][]*ec2.Fi
 	if err != nil {
 		return nil, err
 	}
-	if sg, ok := p.cache.Get(fmt.Sprint(hash)); ok {
+	key := fmt.Sprint(hash)
+	if sg, ok := p.cache.Get(key); ok {
 		return sg.([]*ec2.SecurityGroup), nil
 	}
-	securityGroups := map[string]*ec2.SecurityGroup{}
-	for _, filters := range filterSets {
-		output, err := p.ec2api.DescribeSecurityGroupsWithContext(ctx, &ec2.DescribeSecurityGroupsInput{Filters: filters})
-		if err != nil {
-			return nil, fmt.Errorf("describing security groups %+v, %w", filterSets, err)
+	// Concurrent callers with the same security group selector share a single call to EC2, while callers with different
+	// security group selectors don't block each other
+	result, err, _ := p.group.Do(key, func() (interface{}, error) {
+		if sg, ok := p.cache.Get(key); ok {
+			return sg, nil
 		}
-		for i := range output.SecurityGroups {
-			securityGroups[lo.FromPtr(output.SecurityGroups[i].GroupId)] = output.SecurityGroups[i]
+		securityGroups := map[string]*ec2.SecurityGroup{}
+		for _, filters := range filterSets {
+			output, err := p.ec2api.DescribeSecurityGroupsWithContext(ctx, &ec2.DescribeSecurityGroupsInput{Filters: filters})
+			if err != nil {
+				return nil, fmt.Errorf("describing security groups %+v, %w", filterSets, err)
+			}
+			for i := range output.SecurityGroups {
+				securityGroups[lo.FromPtr(output.SecurityGroups[i].GroupId)] = output.SecurityGroups[i]
+			}
 		}
+		p.cache.SetDefault(key, lo.Values(securityGroups))
+		return lo.Values(securityGroups), nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	p.cache.SetDefault(fmt.Sprint(hash), lo.Values(securityGroups))
-	return lo.Values(securityGroups), nil
+	return result.([]*ec2.SecurityGroup), nil
 }
 
 func getFilterSets(terms []v1beta1.SecurityGroupSelectorTerm) (res [][]*ec2.Filter) {
@@ -27,6 +27,7 @@ import (
 	"github.com/mitchellh/hashstructure/v2"
 	"github.com/patrickmn/go-cache"
 	"github.com/samber/lo"
+	"golang.org/x/sync/singleflight"
 	"knative.dev/pkg/logging"
 
 	"github.com/aws/karpenter/pkg/apis/v1beta1"
@@ -38,8 +39,10 @@ import (
 
 type Provider struct {
 	sync.RWMutex
-	ec2api      ec2iface.EC2API
-	cache       *cache.Cache
+	ec2api ec2iface.EC2API
+	cache  *cache.Cache
+	// group deduplicates concurrent calls to EC2 for the same subnet selector
+	group       singleflight.Group
 	cm          *pretty.ChangeMonitor
 	inflightIPs map[string]int64
 }
@@ -57,8 +60,6 @@ func NewProvider(ec2api ec2iface.EC2API, cache *cache.Cache) *Provider {
 }
 
 func (p *Provider) List(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) ([]*ec2.Subnet, error) {
-	p.Lock()
-	defer p.Unlock()
 	filterSets := getFilterSets(nodeClass.Spec.SubnetSelectorTerms)
 	if len(filterSets) == 0 {
 		return []*ec2.Subnet{}, nil
@@ -67,31 +68,46 @@ func (p *Provider) List(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) ([
 	if err != nil {
 		return nil, err
 	}
-	if subnets, ok := p.cache.Get(fmt.Sprint(hash)); ok {
+	key := fmt.Sprint(hash)
+	if subnets, ok := p.cache.Get(key); ok {
 		return subnets.([]*ec2.Subnet), nil
 	}
-
-	// Ensure that all the subnets that are returned here are unique
-	subnets := map[string]*ec2.Subnet{}
-	for _, filters := range filterSets {
-		output, err := p.ec2api.DescribeSubnetsWithContext(ctx, &ec2.DescribeSubnetsInput{Filters: filters})
-		if err != nil {
-			return nil, fmt.Errorf("describing subnets %s, %w", pretty.Concise(filters), err)
+	// Concurrent callers with the same subnet selector share a single call to EC2, while callers with different subnet
+	// selectors don't block each other
+	result, err, _ := p.group.Do(key, func() (interface{}, error) {
+		if subnets, ok := p.cache.Get(key); ok {
+			return subnets, nil
 		}
-		for i := range output.Subnets {
-			subnets[lo.FromPtr(output.Subnets[i].SubnetId)] = output.Subnets[i]
-			delete(p.inflightIPs, lo.FromPtr(output.Subnets[i].SubnetId)) // remove any previously tracked IP addresses since we just refreshed from EC2
+		// Ensure that all the subnets that are returned here are unique
+		subnets := map[string]*ec2.Subnet{}
+		for _, filters := range filterSets {
+			output, err := p.ec2api.DescribeSubnetsWithContext(ctx, &ec2.DescribeSubnetsInput{Filters: filters})
+			if err != nil {
+				return nil, fmt.Errorf("describing subnets %s, %w", pretty.Concise(filters), err)
+			}
+			for i := range output.Subnets {
+				subnets[lo.FromPtr(output.Subnets[i].SubnetId)] = output.Subnets[i]
+			}
 		}
+		p.Lock()
+		defer p.Unlock()
+		for subnetID := range subnets {
+			delete(p.inflightIPs, subnetID) // remove any previously tracked IP addresses since we just refreshed from EC2
+		}
+		p.cache.SetDefault(key, lo.Values(subnets))
+		if p.cm.HasChanged(fmt.Sprintf("subnets/%t/%s", nodeClass.IsNodeTemplate, nodeClass.Name), subnets) {
+			logging.FromContext(ctx).
+				With("subnets", lo.Map(lo.Values(subnets), func(s *ec2.Subnet, _ int) string {
+					return fmt.Sprintf("%s (%s)", aws.StringValue(s.SubnetId), aws.StringValue(s.AvailabilityZone))
+				})).
+				Debugf("discovered subnets")
+		}
+		return lo.Values(subnets), nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	p.cache.SetDefault(fmt.Sprint(hash), lo.Values(subnets))
-	if p.cm.HasChanged(fmt.Sprintf("subnets/%t/%s", nodeClass.IsNodeTemplate, nodeClass.Name), subnets) {
-		logging.FromContext(ctx).
-			With("subnets", lo.Map(lo.Values(subnets), func(s *ec2.Subnet, _ int) string {
-				return fmt.Sprintf("%s (%s)", aws.StringValue(s.SubnetId), aws.StringValue(s.AvailabilityZone))
-			})).
-			Debugf("discovered subnets")
-	}
-	return lo.Values(subnets), nil
+	return result.([]*ec2.Subnet), nil
 }
 
 // CheckAnyPublicIPAssociations returns a bool indicating whether all referenced subnets assign public IPv4 addresses to EC2 instances created therein
@@ -117,7 +133,9 @@ func (p *Provider) ZonalSubnetsForLaunch(ctx context.Context, nodeClass *v1beta1
 	}
 	p.Lock()
 	defer p.Unlock()
-	// sort subnets in ascending order of available IP addresses and populate map with most available subnet per AZ
+	// sort subnets in ascending order of available IP addresses and populate map with most available subnet per AZ. The
+	// subnets are copied before sorting since they're shared with concurrent callers through the cache.
+	subnets = append([]*ec2.Subnet{}, subnets...)
 	zonalSubnets := map[string]*ec2.Subnet{}
 	sort.Slice(subnets, func(i, j int) bool {
 		iIPs := aws.Int64Value(subnets[i].AvailableIpAddressCount)
@@ -0,0 +1,96 @@
+//go:build test_performance
+
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subnet_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/patrickmn/go-cache"
+
+	"github.com/aws/karpenter/pkg/apis/v1beta1"
+	awscache "github.com/aws/karpenter/pkg/cache"
+	"github.com/aws/karpenter/pkg/fake"
+	"github.com/aws/karpenter/pkg/providers/subnet"
+	"github.com/aws/karpenter/pkg/test"
+)
+
+// ec2Latency is the simulated latency of the EC2 API, which callers used to be serialized behind
+const ec2Latency = 10 * time.Millisecond
+
+type slowEC2API struct {
+	ec2iface.EC2API
+	calls atomic.Int64
+}
+
+func (s *slowEC2API) DescribeSubnetsWithContext(ctx context.Context, input *ec2.DescribeSubnetsInput, opts ...request.Option) (*ec2.DescribeSubnetsOutput, error) {
+	s.calls.Add(1)
+	time.Sleep(ec2Latency)
+	return s.EC2API.DescribeSubnetsWithContext(ctx, input, opts...)
+}
+
+// BenchmarkListDifferentSelectors lists the subnets of node classes with different subnet selectors in parallel, which
+// shouldn't block each other
+func BenchmarkListDifferentSelectors(b *testing.B) {
+	ec2api := &slowEC2API{EC2API: fake.NewEC2API()}
+	provider := subnet.NewProvider(ec2api, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
+	var selector atomic.Int64
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			nodeClass := test.EC2NodeClass(v1beta1.EC2NodeClass{
+				Spec: v1beta1.EC2NodeClassSpec{
+					// every node class selects the same subnets through a different selector
+					SubnetSelectorTerms: []v1beta1.SubnetSelectorTerm{
+						{Tags: map[string]string{"foo": "bar"}},
+						{Tags: map[string]string{"selector": fmt.Sprint(selector.Add(1))}},
+					},
+				},
+			})
+			if _, err := provider.List(context.Background(), nodeClass); err != nil {
+				b.Fatalf("listing subnets, %v", err)
+			}
+		}
+	})
+}
+
+// BenchmarkListSameSelector lists the subnets of node classes with the same subnet selector in parallel while the cache
+// is repeatedly flushed, which should only result in a single call to EC2 for each flush
+func BenchmarkListSameSelector(b *testing.B) {
+	ec2api := &slowEC2API{EC2API: fake.NewEC2API()}
+	subnetCache := cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval)
+	provider := subnet.NewProvider(ec2api, subnetCache)
+	nodeClass := test.EC2NodeClass()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			subnetCache.Flush()
+			if _, err := provider.List(context.Background(), nodeClass); err != nil {
+				b.Fatalf("listing subnets, %v", err)
+			}
+		}
+	})
+	b.ReportMetric(float64(ec2api.calls.Load())/float64(b.N), "ec2-calls/op")
+}
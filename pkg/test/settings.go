@@ -16,6 +16,7 @@ package test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/imdario/mergo"
 	"github.com/samber/lo"
@@ -26,6 +27,7 @@ import (
 type SettingOptions struct {
 	ClusterName                *string
 	ClusterEndpoint            *string
+	ClusterCIDR                *string
 	DefaultInstanceProfile     *string
 	EnablePodENI               *bool
 	EnableENILimitedPodDensity *bool
@@ -34,6 +36,7 @@ type SettingOptions struct {
 	InterruptionQueueName      *string
 	Tags                       map[string]string
 	ReservedENIs               *int
+	SpotPriceUpdateInterval    *time.Duration
 }
 
 func Settings(overrides ...SettingOptions) *awssettings.Settings {
@@ -46,6 +49,7 @@ func Settings(overrides ...SettingOptions) *awssettings.Settings {
 	return &awssettings.Settings{
 		ClusterName:                lo.FromPtrOr(options.ClusterName, "test-cluster"),
 		ClusterEndpoint:            lo.FromPtrOr(options.ClusterEndpoint, "https://test-cluster"),
+		ClusterCIDR:                lo.FromPtrOr(options.ClusterCIDR, ""),
 		DefaultInstanceProfile:     lo.FromPtrOr(options.DefaultInstanceProfile, "test-instance-profile"),
 		EnablePodENI:               lo.FromPtrOr(options.EnablePodENI, true),
 		EnableENILimitedPodDensity: lo.FromPtrOr(options.EnableENILimitedPodDensity, true),
@@ -54,5 +58,6 @@ func Settings(overrides ...SettingOptions) *awssettings.Settings {
 		InterruptionQueueName:      lo.FromPtrOr(options.InterruptionQueueName, ""),
 		Tags:                       options.Tags,
 		ReservedENIs:               lo.FromPtrOr(options.ReservedENIs, 0),
+		SpotPriceUpdateInterval:    lo.FromPtrOr(options.SpotPriceUpdateInterval, 5*time.Minute),
 	}
 }
@@ -79,15 +79,19 @@ var _ = Describe("Scheduling", Ordered, ContinueOnFailure, func() {
 			v1alpha5.ProvisionerNameLabelKey: provisioner.Name,
 			v1.LabelInstanceTypeStable:       "c5.large",
 			// Well Known to AWS
-			v1alpha1.LabelInstanceHypervisor:       "nitro",
-			v1alpha1.LabelInstanceCategory:         "c",
-			v1alpha1.LabelInstanceGeneration:       "5",
-			v1alpha1.LabelInstanceFamily:           "c5",
-			v1alpha1.LabelInstanceSize:             "large",
-			v1alpha1.LabelInstanceCPU:              "2",
-			v1alpha1.LabelInstanceMemory:           "4096",
-			v1alpha1.LabelInstanceNetworkBandwidth: "750",
-			v1alpha1.LabelInstancePods:             "29",
+			v1alpha1.LabelInstanceHypervisor:                "nitro",
+			v1alpha1.LabelInstanceCategory:                  "c",
+			v1alpha1.LabelInstanceGeneration:                "5",
+			v1alpha1.LabelInstanceFamily:                    "c5",
+			v1alpha1.LabelInstanceSize:                      "large",
+			v1alpha1.LabelInstanceCPU:                       "2",
+			v1alpha1.LabelInstanceCPUManufacturer:           "intel",
+			v1alpha1.LabelInstanceCPUSustainedClockSpeedMhz: "3400",
+			v1alpha1.LabelInstanceMemory:                    "4096",
+			v1alpha1.LabelInstanceEBSBandwidth:              "4750",
+			v1alpha1.LabelInstanceNetworkBandwidth:          "750",
+			v1alpha1.LabelInstanceENAExpress:                "false",
+			v1alpha1.LabelInstancePods:                      "29",
 		}
 		selectors.Insert(lo.Keys(nodeSelector)...) // Add node selector keys to selectors used in testing to ensure we test all labels
 		requirements := lo.MapToSlice(nodeSelector, func(key string, value string) v1.NodeSelectorRequirement {
@@ -124,6 +128,20 @@ var _ = Describe("Scheduling", Ordered, ContinueOnFailure, func() {
 		env.EventuallyExpectHealthyPodCount(labels.SelectorFromSet(deployment.Spec.Selector.MatchLabels), int(*deployment.Spec.Replicas))
 		env.ExpectCreatedNodeCount("==", 1)
 	})
+	It("should support well-known labels for zone id", func() {
+		selectors.Insert(v1alpha1.LabelTopologyZoneID) // Add node selector keys to selectors used in testing to ensure we test all labels
+		deployment := test.Deployment(test.DeploymentOptions{Replicas: 1, PodOptions: test.PodOptions{
+			NodeRequirements: []v1.NodeSelectorRequirement{
+				{
+					Key:      v1alpha1.LabelTopologyZoneID,
+					Operator: v1.NodeSelectorOpExists,
+				},
+			},
+		}})
+		env.ExpectCreated(provisioner, provider, deployment)
+		env.EventuallyExpectHealthyPodCount(labels.SelectorFromSet(deployment.Spec.Selector.MatchLabels), int(*deployment.Spec.Replicas))
+		env.ExpectCreatedNodeCount("==", 1)
+	})
 	It("should support well-known labels for encryption in transit", func() {
 		selectors.Insert(v1alpha1.LabelInstanceEncryptionInTransitSupported) // Add node selector keys to selectors used in testing to ensure we test all labels
 		deployment := test.Deployment(test.DeploymentOptions{Replicas: 1, PodOptions: test.PodOptions{
@@ -177,6 +195,8 @@ var _ = Describe("Scheduling", Ordered, ContinueOnFailure, func() {
 			v1.LabelOSStable:                 "linux",
 			v1.LabelArchStable:               "amd64",
 			v1alpha5.LabelCapacityType:       "on-demand",
+			// Well Known to AWS
+			v1alpha1.LabelTopologyZoneType: "availability-zone",
 		}
 		selectors.Insert(lo.Keys(nodeSelector)...) // Add node selector keys to selectors used in testing to ensure we test all labels
 		requirements := lo.MapToSlice(nodeSelector, func(key string, value string) v1.NodeSelectorRequirement {
@@ -216,6 +236,7 @@ var _ = Describe("Scheduling", Ordered, ContinueOnFailure, func() {
 			v1alpha1.LabelInstanceAcceleratorName:         "inferentia",
 			v1alpha1.LabelInstanceAcceleratorManufacturer: "aws",
 			v1alpha1.LabelInstanceAcceleratorCount:        "1",
+			v1alpha1.LabelInstanceNeuronCoreCount:         "4",
 		}
 		selectors.Insert(lo.Keys(nodeSelector)...) // Add node selector keys to selectors used in testing to ensure we test all labels
 		requirements := lo.MapToSlice(nodeSelector, func(key string, value string) v1.NodeSelectorRequirement {